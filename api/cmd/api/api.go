@@ -20,13 +20,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	go surveys.RunScheduler(svc)
+	go surveys.RunWebhookWorker(svc)
+
 	handler := controllers.NewHandler(svc)
 	if err != nil {
 		svc.Logger.Error("unable to start server", "err", err)
 		os.Exit(1)
 	}
 
-	r := controllers.NewRouter(handler)
+	r, err := controllers.NewRouter(handler)
+	if err != nil {
+		svc.Logger.Error("unable to create router", "err", err)
+		os.Exit(1)
+	}
 
 	if err := r.Start(":8080"); err != nil {
 		svc.Logger.Info("shutting down the server", "err", err)
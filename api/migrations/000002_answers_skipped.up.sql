@@ -0,0 +1,2 @@
+ALTER TABLE surveys_answers
+  ADD COLUMN skipped boolean NOT NULL DEFAULT FALSE;
@@ -0,0 +1 @@
+ALTER TABLE surveys_sessions ADD COLUMN display_order JSONB;
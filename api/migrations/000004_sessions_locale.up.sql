@@ -0,0 +1 @@
+ALTER TABLE surveys_sessions ADD COLUMN locale varchar(16);
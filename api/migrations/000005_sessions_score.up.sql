@@ -0,0 +1,3 @@
+ALTER TABLE surveys_sessions ADD COLUMN score INTEGER;
+
+ALTER TABLE surveys_sessions ADD COLUMN max_score INTEGER;
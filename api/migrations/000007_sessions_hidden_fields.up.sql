@@ -0,0 +1,3 @@
+ALTER TABLE surveys_sessions ADD COLUMN hidden_fields JSONB;
+
+CREATE INDEX surveys_sessions_hidden_fields ON surveys_sessions USING GIN (hidden_fields jsonb_path_ops);
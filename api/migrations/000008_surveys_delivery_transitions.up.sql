@@ -0,0 +1,10 @@
+CREATE TABLE surveys_delivery_transitions (
+  id serial NOT NULL PRIMARY KEY,
+  created_at timestamp without time zone default (now () at time zone 'utc'),
+  survey_id integer NOT NULL,
+  delivery_status survey_delivery_statuses NOT NULL,
+  scheduled_at timestamp without time zone NOT NULL,
+  CONSTRAINT fk_surveys_delivery_transitions1 FOREIGN KEY (survey_id) REFERENCES surveys (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX surveys_delivery_transitions_unique ON surveys_delivery_transitions (survey_id, delivery_status, scheduled_at);
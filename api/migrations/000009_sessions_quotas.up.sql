@@ -0,0 +1,3 @@
+ALTER TYPE surveys_sessions_status ADD VALUE 'screened_out';
+
+ALTER TABLE surveys_sessions ADD COLUMN quota_id varchar(256);
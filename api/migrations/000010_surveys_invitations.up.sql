@@ -0,0 +1,15 @@
+CREATE TABLE surveys_invitations (
+  id serial NOT NULL PRIMARY KEY,
+  uuid uuid NOT NULL DEFAULT uuid_generate_v4 () UNIQUE,
+  created_at timestamp without time zone default (now () at time zone 'utc'),
+  survey_id integer NOT NULL,
+  token varchar(64) NOT NULL UNIQUE,
+  email varchar(512) NOT NULL,
+  name varchar(512),
+  session_id integer,
+  used_at timestamp without time zone,
+  CONSTRAINT fk_surveys_invitations1 FOREIGN KEY (survey_id) REFERENCES surveys (id) ON DELETE CASCADE,
+  CONSTRAINT fk_surveys_invitations2 FOREIGN KEY (session_id) REFERENCES surveys_sessions (id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX surveys_invitations_email ON surveys_invitations (survey_id, email);
@@ -0,0 +1,29 @@
+CREATE TYPE surveys_webhook_deliveries_status AS ENUM ('pending', 'delivered', 'dead');
+
+CREATE TABLE surveys_webhook_deliveries (
+  id serial NOT NULL PRIMARY KEY,
+  uuid uuid NOT NULL DEFAULT uuid_generate_v4 () UNIQUE,
+  created_at timestamp without time zone default (now () at time zone 'utc'),
+  session_id integer,
+  url varchar(2048) NOT NULL,
+  method varchar(16) NOT NULL,
+  payload JSONB NOT NULL,
+  status surveys_webhook_deliveries_status NOT NULL DEFAULT 'pending',
+  attempts integer NOT NULL DEFAULT 0,
+  max_attempts integer NOT NULL,
+  next_attempt_at timestamp without time zone NOT NULL default (now () at time zone 'utc'),
+  delivered_at timestamp without time zone,
+  CONSTRAINT fk_surveys_webhook_deliveries1 FOREIGN KEY (session_id) REFERENCES surveys_sessions (id) ON DELETE SET NULL
+);
+
+CREATE INDEX surveys_webhook_deliveries_next_attempt ON surveys_webhook_deliveries (status, next_attempt_at);
+
+ALTER TABLE surveys_webhook_responses ADD COLUMN delivery_id integer;
+ALTER TABLE surveys_webhook_responses ADD COLUMN attempt integer;
+ALTER TABLE surveys_webhook_responses ADD COLUMN error TEXT;
+ALTER TABLE surveys_webhook_responses ADD CONSTRAINT fk_surveys_webhooks2 FOREIGN KEY (delivery_id) REFERENCES surveys_webhook_deliveries (id) ON DELETE CASCADE;
+
+-- the delivery log is kept when a session is deleted
+ALTER TABLE surveys_webhook_responses ALTER COLUMN session_id DROP NOT NULL;
+ALTER TABLE surveys_webhook_responses DROP CONSTRAINT fk_surveys_webhooks1;
+ALTER TABLE surveys_webhook_responses ADD CONSTRAINT fk_surveys_webhooks1 FOREIGN KEY (session_id) REFERENCES surveys_sessions (id) ON DELETE SET NULL;
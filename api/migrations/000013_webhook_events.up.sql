@@ -0,0 +1,5 @@
+ALTER TABLE surveys_webhook_deliveries ADD COLUMN survey_id integer;
+ALTER TABLE surveys_webhook_deliveries ADD COLUMN event_type varchar(64) NOT NULL DEFAULT 'session.completed';
+ALTER TABLE surveys_webhook_deliveries ADD CONSTRAINT fk_surveys_webhook_deliveries2 FOREIGN KEY (survey_id) REFERENCES surveys (id) ON DELETE CASCADE;
+
+UPDATE surveys_webhook_deliveries AS wd SET survey_id = ss.survey_id FROM surveys_sessions AS ss WHERE ss.id = wd.session_id;
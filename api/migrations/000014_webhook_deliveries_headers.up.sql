@@ -0,0 +1 @@
+ALTER TABLE surveys_webhook_deliveries ADD COLUMN headers JSONB;
@@ -0,0 +1,2 @@
+-- raw IP addresses were stored before they were hashed, they are removed as they can't be hashed with the salt of the API
+UPDATE surveys_sessions SET ip_addr = NULL WHERE ip_addr !~ '^[0-9a-f]{64}$';
@@ -0,0 +1,2 @@
+ALTER TABLE surveys_answers
+  ADD COLUMN prefilled boolean NOT NULL DEFAULT FALSE;
@@ -1,16 +1,32 @@
 package controllers
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+
 	"github.com/plutov/formulosity/api/pkg/services"
 )
 
 type Handler struct {
 	services.Services
+	// signs respondent cookies of surveys with cookie duplicate protection
+	cookieSecret string
 }
 
 func NewHandler(svc services.Services) *Handler {
 	h := &Handler{
-		Services: svc,
+		Services:     svc,
+		cookieSecret: os.Getenv("COOKIE_SECRET"),
+	}
+
+	// respondent cookies are not valid after restart with a random secret
+	if h.cookieSecret == "" {
+		h.Logger.Warn("COOKIE_SECRET is not set, using a random secret")
+		secret := make([]byte, 32)
+		_, _ = rand.Read(secret)
+		h.cookieSecret = hex.EncodeToString(secret)
 	}
+
 	return h
 }
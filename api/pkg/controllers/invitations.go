@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/labstack/echo/v4"
+	"github.com/plutov/formulosity/api/pkg/http/response"
+	surveyspkg "github.com/plutov/formulosity/api/pkg/surveys"
+	"github.com/plutov/formulosity/api/pkg/types"
+)
+
+const maxInvitationsFileSize = 5 << 20
+
+// createSurveyInvitations accepts CSV with respondents as a multipart "file" or as a raw body
+func (h *Handler) createSurveyInvitations(c echo.Context) error {
+	survey := c.Get("survey").(types.Survey)
+	if survey.Config == nil {
+		return response.BadRequest(c, "invalid survey configuration")
+	}
+	if survey.Config.DuplicateProtection() != types.DuplicateProtectionType_Invite {
+		return response.BadRequest(c, "survey duplicate protection is not invite")
+	}
+
+	var reader io.Reader = c.Request().Body
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return response.BadRequest(c, "unable to open file")
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	invitations, err := types.ParseInvitationsCSV(io.LimitReader(reader, maxInvitationsFileSize))
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	created, err := surveyspkg.CreateSurveyInvitations(h.Services, &survey, invitations)
+	if err != nil {
+		return response.InternalError(c, err.Error())
+	}
+
+	setInvitationURLs(&survey, created)
+
+	return response.Created(c, fmt.Sprintf("%d invitations created", len(created)), created)
+}
+
+func (h *Handler) getSurveyInvitations(c echo.Context) error {
+	survey := c.Get("survey").(types.Survey)
+
+	invitations, err := surveyspkg.GetSurveyInvitations(h.Services, &survey)
+	if err != nil {
+		return response.InternalError(c, err.Error())
+	}
+
+	setInvitationURLs(&survey, invitations)
+
+	return response.Ok(c, invitations)
+}
+
+func setInvitationURLs(survey *types.Survey, invitations []types.Invitation) {
+	for i, invitation := range invitations {
+		invitations[i].URL = fmt.Sprintf("/survey/%s?token=%s", survey.URLSlug, invitation.Token)
+	}
+}
@@ -1,7 +1,11 @@
 package controllers
 
 import (
+	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -9,11 +13,17 @@ import (
 )
 
 // NewRouter returns new router
-func NewRouter(h *Handler) *echo.Echo {
+func NewRouter(h *Handler) (*echo.Echo, error) {
+	ipExtractor, err := ipExtractor()
+	if err != nil {
+		return nil, err
+	}
+
 	e := echo.New()
+	e.IPExtractor = ipExtractor
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
-	e.Use(middleware.CORS())
+	e.Use(middleware.CORSWithConfig(corsConfig()))
 
 	e.GET("/", h.healthCheckHandler)
 	e.GET("/app/surveys", h.getSurveys)
@@ -21,6 +31,10 @@ func NewRouter(h *Handler) *echo.Echo {
 	e.GET("/app/surveys/:survey_uuid/sessions", h.surveyUUIDMiddleware(h.getSurveySessions))
 	e.DELETE("/app/surveys/:survey_uuid/sessions/:session_uuid", h.surveyUUIDMiddleware(h.deleteSurveySession))
 	e.GET("/app/surveys/:survey_uuid/download/:file_name", h.surveyUUIDMiddleware(h.downloadFile))
+	e.GET("/app/surveys/:survey_uuid/invitations", h.surveyUUIDMiddleware(h.getSurveyInvitations))
+	e.POST("/app/surveys/:survey_uuid/invitations", h.surveyUUIDMiddleware(h.createSurveyInvitations))
+	e.GET("/app/surveys/:survey_uuid/webhooks/deliveries", h.surveyUUIDMiddleware(h.getWebhookDeliveries))
+	e.POST("/app/surveys/:survey_uuid/webhooks/deliveries/:delivery_uuid/redeliver", h.surveyUUIDMiddleware(h.redeliverWebhook))
 
 	surveys := e.Group("/surveys")
 	surveys.GET("/:url_slug", h.getSurvey)
@@ -28,8 +42,47 @@ func NewRouter(h *Handler) *echo.Echo {
 	surveys.PUT("/:url_slug/sessions", h.createSurveySession)
 	surveys.GET("/:url_slug/sessions/:session_uuid", h.getSurveySessionHandler)
 	surveys.POST("/:url_slug/sessions/:session_uuid/questions/:question_uuid/answers", h.submitSurveyAnswer)
+	surveys.POST("/:url_slug/sessions/:session_uuid/pages/:page_id/answers", h.submitSurveyPageAnswers)
+
+	return e, nil
+}
+
+// corsConfig allows all origins without credentials by default, the respondent cookie is only sent
+// by the UI served from another origin if it's listed in comma separated CORS_ALLOWED_ORIGINS
+func corsConfig() middleware.CORSConfig {
+	origins := os.Getenv("CORS_ALLOWED_ORIGINS")
+	if origins == "" {
+		return middleware.DefaultCORSConfig
+	}
+
+	return middleware.CORSConfig{
+		AllowOrigins:     strings.Split(origins, ","),
+		AllowCredentials: true,
+	}
+}
+
+// ipExtractor takes the client IP address from the connection, unless proxies are configured
+// with comma separated TRUSTED_PROXIES CIDR ranges, then it's taken from X-Forwarded-For set by them
+func ipExtractor() (echo.IPExtractor, error) {
+	proxies := os.Getenv("TRUSTED_PROXIES")
+	if proxies == "" {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range strings.Split(proxies, ",") {
+		_, ipRange, err := net.ParseCIDR(strings.TrimSpace(proxy))
+		if err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES is invalid: %s", proxy)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
 
-	return e
+	return echo.ExtractIPFromXFFHeader(options...), nil
 }
 
 func (h *Handler) healthCheckHandler(c echo.Context) error {
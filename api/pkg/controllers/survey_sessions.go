@@ -2,9 +2,12 @@ package controllers
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -15,21 +18,114 @@ import (
 	"github.com/plutov/formulosity/api/pkg/types"
 )
 
+const respondentCookieMaxAge = 365 * 24 * 60 * 60
+
+type createSessionReq struct {
+	HiddenFields map[string]interface{} `json:"hidden_fields"`
+	Token        string                 `json:"token"`
+}
+
 func (h *Handler) createSurveySession(c echo.Context) error {
 	survey, err := h.getLaunchedSurvey(c)
 	if err != nil {
-		return response.NotFound(c, err.Error())
+		return surveyNotAvailable(c, err)
+	}
+
+	req := new(createSessionReq)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequestDefaultMessage(c)
+	}
+
+	// respondent who has already started the survey resumes the session
+	if survey.Config.DuplicateProtection() == types.DuplicateProtectionType_Cookie {
+		if sessionUUID, ok := h.getRespondentCookie(c, survey); ok {
+			session, err := surveyspkg.ResumeSurveySession(h.Services, survey, sessionUUID)
+			if err != nil {
+				if errors.Is(err, types.ErrDuplicateSession) {
+					return response.Forbidden(c, err.Error())
+				}
+				return response.InternalError(c, err.Error())
+			}
+			if session != nil {
+				return response.Ok(c, *session)
+			}
+		}
+	}
+
+	// hidden fields can be passed as query params of the survey URL or in the body
+	hiddenFields, err := survey.Config.HiddenFieldValues(c.QueryParams(), req.HiddenFields)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	prefillValues, err := survey.Config.PrefillValues(survey.URLSlug, c.QueryParams(), os.Getenv("PREFILL_SECRET"))
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	prefilled, mainErr, detailsErr := surveyspkg.ParsePrefilledAnswers(h.Services, survey, prefillValues)
+	if mainErr != nil {
+		if detailsErr != nil {
+			return response.BadRequestWithDetails(c, mainErr.Error(), detailsErr.Error())
+		}
+
+		return response.BadRequest(c, mainErr.Error())
 	}
 
 	ipAddr := c.RealIP()
-	session, err := surveyspkg.CreateSurveySession(h.Services, survey, ipAddr)
+	locale := survey.Config.NegotiateLocale(c.QueryParam("lang"), c.Request().Header.Get("Accept-Language"))
+	// invitation token can be passed as a query param of the invitation link or in the body
+	token := req.Token
+	if token == "" {
+		token = c.QueryParam("token")
+	}
+
+	session, err := surveyspkg.CreateSurveySession(h.Services, survey, ipAddr, locale, hiddenFields, prefilled, token)
 	if err != nil {
 		return response.Forbidden(c, err.Error())
 	}
 
+	if survey.Config.DuplicateProtection() == types.DuplicateProtectionType_Cookie {
+		h.setRespondentCookie(c, survey, session)
+	}
+
 	return response.Ok(c, *session)
 }
 
+// getRespondentCookie returns the session UUID from the respondent cookie if it's signed by the server
+func (h *Handler) getRespondentCookie(c echo.Context, survey *types.Survey) (string, bool) {
+	cookie, err := c.Cookie(types.RespondentCookieName(survey.URLSlug))
+	if err != nil {
+		return "", false
+	}
+
+	sessionUUID, err := types.VerifyRespondentCookie(survey.UUID, cookie.Value, h.cookieSecret)
+	if err != nil {
+		h.Logger.Warn("invalid respondent cookie", "survey_uuid", survey.UUID, "err", err)
+		return "", false
+	}
+
+	return sessionUUID, true
+}
+
+func (h *Handler) setRespondentCookie(c echo.Context, survey *types.Survey, session *types.SurveySession) {
+	cookie := &http.Cookie{
+		Name:     types.RespondentCookieName(survey.URLSlug),
+		Value:    types.SignRespondentCookie(survey.UUID, session.UUID, h.cookieSecret),
+		Path:     "/surveys/" + survey.URLSlug,
+		MaxAge:   respondentCookieMaxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	// UI can be served from another site, which requires secure cookies
+	if c.Scheme() == "https" {
+		cookie.Secure = true
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+
+	c.SetCookie(cookie)
+}
+
 func (h *Handler) getSurveySessionHandler(c echo.Context) error {
 	session, _, err := h.getSurveySession(c)
 	if err != nil {
@@ -97,17 +193,55 @@ func (h *Handler) submitSurveyAnswer(c echo.Context) error {
 		return response.BadRequest(c, mainErr.Error())
 	}
 
-	session, _, err = h.getSurveySession(c)
+	return h.answersSubmitted(c)
+}
+
+type submitPageAnswersReq struct {
+	Answers map[string]json.RawMessage `json:"answers"`
+}
+
+func (h *Handler) submitSurveyPageAnswers(c echo.Context) error {
+	pageID := c.Param("page_id")
+	if pageID == "" {
+		return response.BadRequest(c, "page_id is required")
+	}
+
+	session, survey, err := h.getSurveySession(c)
 	if err != nil {
-		return response.NotFound(c, err.Error())
+		return response.BadRequest(c, err.Error())
 	}
 
-	if session.Status == types.SurveySessionStatus_Completed {
-		go func() {
-			if err := surveyspkg.CallWebhook(h.Services, survey, session); err != nil {
-				h.Logger.Error("call webhook error", "err", err)
-			}
-		}()
+	if session.Status != types.SurveySessionStatus_InProgress {
+		return response.BadRequest(c, "session is not in progress")
+	}
+
+	page, err := survey.Config.Questions.FindPageByID(pageID)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	req := new(submitPageAnswersReq)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequestDefaultMessage(c)
+	}
+
+	mainErr, detailsErr := surveyspkg.SubmitPageAnswers(h.Services, session, survey, page, req.Answers)
+	if mainErr != nil {
+		if detailsErr != nil {
+			return response.BadRequestWithDetails(c, mainErr.Error(), detailsErr.Error())
+		}
+
+		return response.BadRequest(c, mainErr.Error())
+	}
+
+	return h.answersSubmitted(c)
+}
+
+// answersSubmitted returns the updated session
+func (h *Handler) answersSubmitted(c echo.Context) error {
+	session, _, err := h.getSurveySession(c)
+	if err != nil {
+		return response.NotFound(c, err.Error())
 	}
 
 	return response.Ok(c, *session)
@@ -123,6 +257,7 @@ func (h *Handler) getSurveySessions(c echo.Context) error {
 	if err := req.Validate(); err != nil {
 		return response.BadRequest(c, err.Error())
 	}
+	req.SetHiddenFields(c.QueryParams())
 
 	survey, err := surveyspkg.GetSurveyByUUID(h.Services, surveyCtx.UUID)
 	if err != nil || survey == nil {
@@ -134,6 +269,9 @@ func (h *Handler) getSurveySessions(c echo.Context) error {
 		return response.InternalErrorDefaultMsg(c)
 	}
 
+	// sessions are still listed without NPS if stats can't be computed, the error is logged by the service
+	survey.Stats.NPS, _ = surveyspkg.GetSurveyNPSStats(h.Services, *survey)
+
 	return response.Ok(c, echo.Map{
 		"survey":      *survey,
 		"sessions":    sessions,
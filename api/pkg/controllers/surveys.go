@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/plutov/formulosity/api/pkg/http/response"
@@ -14,16 +15,21 @@ import (
 func (h *Handler) getSurvey(c echo.Context) error {
 	survey, err := h.getLaunchedSurvey(c)
 	if err != nil {
-		return response.NotFound(c, err.Error())
+		return surveyNotAvailable(c, err)
 	}
 
+	// texts are returned in the locale from ?lang= or Accept-Language
+	survey.Locale = survey.Config.NegotiateLocale(c.QueryParam("lang"), c.Request().Header.Get("Accept-Language"))
+	survey.Locales = survey.Config.Locales()
+	survey.Config = survey.Config.Localize(survey.Locale).HideCorrect().Public()
+
 	return response.Ok(c, survey)
 }
 
 func (h *Handler) getSurveyCSS(c echo.Context) error {
 	survey, err := h.getLaunchedSurvey(c)
 	if err != nil {
-		return response.NotFound(c, err.Error())
+		return surveyNotAvailable(c, err)
 	}
 
 	// serve css
@@ -35,6 +41,20 @@ func (h *Handler) getSurveyCSS(c echo.Context) error {
 	return response.Ok(c, "ok")
 }
 
+var (
+	errSurveyNotYetOpen = errors.New("survey is not yet open")
+	errSurveyClosed     = errors.New("survey is closed")
+)
+
+// surveyNotAvailable responds with 403 if the survey is not open by schedule and 404 otherwise
+func surveyNotAvailable(c echo.Context, err error) error {
+	if errors.Is(err, errSurveyNotYetOpen) || errors.Is(err, errSurveyClosed) {
+		return response.Forbidden(c, err.Error())
+	}
+
+	return response.NotFound(c, err.Error())
+}
+
 func (h *Handler) getLaunchedSurvey(c echo.Context) (*types.Survey, error) {
 	urlSlug := c.Param("url_slug")
 	res, err := surveyspkg.GetSurvey(h.Services, urlSlug)
@@ -42,6 +62,16 @@ func (h *Handler) getLaunchedSurvey(c echo.Context) (*types.Survey, error) {
 		return nil, errors.New("survey not found")
 	}
 
+	// schedule is checked first, as the scheduler may not have switched delivery status yet
+	if res.Config != nil {
+		switch res.Config.ScheduleStatus(time.Now()) {
+		case types.SurveyScheduleStatus_NotYetOpen:
+			return nil, errSurveyNotYetOpen
+		case types.SurveyScheduleStatus_Closed:
+			return nil, errSurveyClosed
+		}
+	}
+
 	if res.DeliveryStatus != types.SurveyDeliveryStatus_Launched {
 		return nil, errors.New("survey is stopped")
 	}
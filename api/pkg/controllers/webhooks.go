@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/plutov/formulosity/api/pkg/http/response"
+	surveyspkg "github.com/plutov/formulosity/api/pkg/surveys"
+	"github.com/plutov/formulosity/api/pkg/types"
+)
+
+func (h *Handler) getWebhookDeliveries(c echo.Context) error {
+	survey := c.Get("survey").(types.Survey)
+
+	req := new(types.WebhookDeliveriesFilter)
+	if err := c.Bind(req); err != nil {
+		return response.BadRequestDefaultMessage(c)
+	}
+	if err := req.Validate(); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	deliveries, err := surveyspkg.GetWebhookDeliveries(h.Services, &survey, req)
+	if err != nil {
+		return response.InternalError(c, err.Error())
+	}
+
+	return response.Ok(c, deliveries)
+}
+
+func (h *Handler) redeliverWebhook(c echo.Context) error {
+	survey := c.Get("survey").(types.Survey)
+
+	delivery, err := surveyspkg.RedeliverWebhook(h.Services, &survey, c.Param("delivery_uuid"))
+	if err != nil {
+		switch {
+		case errors.Is(err, types.ErrWebhookDeliveryNotFound):
+			return response.NotFound(c, err.Error())
+		case errors.Is(err, types.ErrWebhookDeliveryPending):
+			return response.Conflict(c, err.Error())
+		default:
+			return response.InternalError(c, err.Error())
+		}
+	}
+
+	return response.OkWithMsg(c, "webhook queued for redelivery", delivery)
+}
@@ -28,3 +28,13 @@ func DecodeUUID(uuidStr string) (pgtype.UUID, error) {
 	err := uuid.Scan(uuidStr)
 	return uuid, err
 }
+
+// EncodeInt4 returns nil for NULL values
+func EncodeInt4(v pgtype.Int4) *int {
+	if !v.Valid {
+		return nil
+	}
+
+	i := int(v.Int32)
+	return &i
+}
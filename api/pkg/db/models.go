@@ -141,8 +141,9 @@ func (ns NullSurveyParseStatuses) Value() (driver.Value, error) {
 type SurveysSessionsStatus string
 
 const (
-	SurveysSessionsStatusInProgress SurveysSessionsStatus = "in_progress"
-	SurveysSessionsStatusCompleted  SurveysSessionsStatus = "completed"
+	SurveysSessionsStatusInProgress  SurveysSessionsStatus = "in_progress"
+	SurveysSessionsStatusCompleted   SurveysSessionsStatus = "completed"
+	SurveysSessionsStatusScreenedOut SurveysSessionsStatus = "screened_out"
 )
 
 func (e *SurveysSessionsStatus) Scan(src interface{}) error {
@@ -180,6 +181,49 @@ func (ns NullSurveysSessionsStatus) Value() (driver.Value, error) {
 	return string(ns.SurveysSessionsStatus), nil
 }
 
+type SurveysWebhookDeliveriesStatus string
+
+const (
+	SurveysWebhookDeliveriesStatusPending   SurveysWebhookDeliveriesStatus = "pending"
+	SurveysWebhookDeliveriesStatusDelivered SurveysWebhookDeliveriesStatus = "delivered"
+	SurveysWebhookDeliveriesStatusDead      SurveysWebhookDeliveriesStatus = "dead"
+)
+
+func (e *SurveysWebhookDeliveriesStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SurveysWebhookDeliveriesStatus(s)
+	case string:
+		*e = SurveysWebhookDeliveriesStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SurveysWebhookDeliveriesStatus: %T", src)
+	}
+	return nil
+}
+
+type NullSurveysWebhookDeliveriesStatus struct {
+	SurveysWebhookDeliveriesStatus SurveysWebhookDeliveriesStatus
+	Valid                          bool // Valid is true if SurveysWebhookDeliveriesStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSurveysWebhookDeliveriesStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.SurveysWebhookDeliveriesStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SurveysWebhookDeliveriesStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSurveysWebhookDeliveriesStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SurveysWebhookDeliveriesStatus), nil
+}
+
 type Survey struct {
 	ID             int32
 	Uuid           pgtype.UUID
@@ -199,6 +243,28 @@ type SurveysAnswer struct {
 	SessionID  int32
 	QuestionID int32
 	Answer     []byte
+	Skipped    bool
+	Prefilled  bool
+}
+
+type SurveysDeliveryTransition struct {
+	ID             int32
+	CreatedAt      pgtype.Timestamp
+	SurveyID       int32
+	DeliveryStatus SurveyDeliveryStatuses
+	ScheduledAt    pgtype.Timestamp
+}
+
+type SurveysInvitation struct {
+	ID        int32
+	Uuid      pgtype.UUID
+	CreatedAt pgtype.Timestamp
+	SurveyID  int32
+	Token     string
+	Email     string
+	Name      pgtype.Text
+	SessionID pgtype.Int4
+	UsedAt    pgtype.Timestamp
 }
 
 type SurveysQuestion struct {
@@ -209,19 +275,48 @@ type SurveysQuestion struct {
 }
 
 type SurveysSession struct {
-	ID          int32
-	Uuid        pgtype.UUID
-	CreatedAt   pgtype.Timestamp
-	CompletedAt pgtype.Timestamp
-	Status      NullSurveysSessionsStatus
-	SurveyID    int32
-	IpAddr      pgtype.Text
+	ID           int32
+	Uuid         pgtype.UUID
+	CreatedAt    pgtype.Timestamp
+	CompletedAt  pgtype.Timestamp
+	Status       NullSurveysSessionsStatus
+	SurveyID     int32
+	IpAddr       pgtype.Text
+	DisplayOrder []byte
+	Locale       pgtype.Text
+	Score        pgtype.Int4
+	MaxScore     pgtype.Int4
+	Variables    []byte
+	HiddenFields []byte
+	QuotaID      pgtype.Text
+}
+
+type SurveysWebhookDelivery struct {
+	ID            int32
+	Uuid          pgtype.UUID
+	CreatedAt     pgtype.Timestamp
+	SessionID     pgtype.Int4
+	Url           string
+	Method        string
+	Payload       []byte
+	Status        SurveysWebhookDeliveriesStatus
+	Attempts      int32
+	MaxAttempts   int32
+	NextAttemptAt pgtype.Timestamp
+	DeliveredAt   pgtype.Timestamp
+	SecretEnv     pgtype.Text
+	SurveyID      pgtype.Int4
+	EventType     string
+	Headers       []byte
 }
 
 type SurveysWebhookResponse struct {
 	ID             int32
 	CreatedAt      pgtype.Timestamp
-	SessionID      int32
+	SessionID      pgtype.Int4
 	ResponseStatus int32
 	Response       pgtype.Text
+	DeliveryID     pgtype.Int4
+	Attempt        pgtype.Int4
+	Error          pgtype.Text
 }
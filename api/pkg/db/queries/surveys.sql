@@ -4,6 +4,12 @@ INSERT INTO surveys (parse_status, delivery_status, error_log, name, config, url
 RETURNING
     *;
 
+-- name: CreateSurveyDeliveryTransition :execrows
+INSERT INTO surveys_delivery_transitions (survey_id, delivery_status, scheduled_at)
+    VALUES ($1, $2, $3)
+ON CONFLICT (survey_id, delivery_status, scheduled_at)
+    DO NOTHING;
+
 -- name: UpdateSurvey :exec
 UPDATE
     surveys
@@ -104,14 +110,14 @@ ORDER BY
     sq.question_id;
 
 -- name: CreateSurveySession :one
-INSERT INTO surveys_sessions (status, survey_id, ip_addr)
+INSERT INTO surveys_sessions (status, survey_id, ip_addr, locale, hidden_fields)
     VALUES ($1, (
             SELECT
                 s.id
             FROM
                 surveys s
             WHERE
-                s.uuid = $2), $3)
+                s.uuid = $2), $3, $4, $5)
 RETURNING
     id,
     uuid;
@@ -125,6 +131,40 @@ SET
 WHERE
     uuid = $2;
 
+-- name: UpdateSurveySessionDisplayOrder :exec
+UPDATE
+    surveys_sessions
+SET
+    display_order = $1
+WHERE
+    uuid = $2;
+
+-- name: UpdateSurveySessionScore :exec
+UPDATE
+    surveys_sessions
+SET
+    score = $1,
+    max_score = $2
+WHERE
+    uuid = $3;
+
+-- name: UpdateSurveySessionVariables :exec
+UPDATE
+    surveys_sessions
+SET
+    variables = $1
+WHERE
+    uuid = $2;
+
+-- name: UpdateSurveySessionScreenedOut :exec
+UPDATE
+    surveys_sessions
+SET
+    status = $1,
+    quota_id = $2
+WHERE
+    uuid = $3;
+
 -- name: UpdateSurveySessionStatus :exec
 UPDATE
     surveys_sessions
@@ -139,6 +179,13 @@ SELECT
     ss.uuid,
     ss.created_at,
     ss.status,
+    ss.display_order,
+    ss.locale,
+    ss.score,
+    ss.max_score,
+    ss.variables,
+    ss.hidden_fields,
+    ss.quota_id,
     s.uuid AS survey_uuid
 FROM
     surveys_sessions AS ss
@@ -151,13 +198,9 @@ WHERE
 DELETE FROM surveys_sessions
 WHERE uuid = $1;
 
--- name: GetSurveySessionByIPAddress :one
+-- name: GetSurveySessionsCountByIPAddress :one
 SELECT
-    ss.id,
-    ss.uuid,
-    ss.created_at,
-    ss.status,
-    s.uuid AS survey_uuid
+    COUNT(*)
 FROM
     surveys_sessions AS ss
     INNER JOIN surveys AS s ON s.id = ss.survey_id
@@ -169,7 +212,9 @@ WHERE
 SELECT
     q.question_id,
     q.uuid AS question_uuid,
-    sa.answer
+    sa.answer,
+    sa.skipped,
+    sa.prefilled
 FROM
     surveys_answers AS sa
     LEFT JOIN surveys_questions AS q ON q.id = sa.question_id
@@ -185,7 +230,7 @@ ORDER BY
     q.question_id;
 
 -- name: UpsertSurveyQuestionAnswer :exec
-INSERT INTO surveys_answers (session_id, question_id, answer)
+INSERT INTO surveys_answers (session_id, question_id, answer, skipped, prefilled)
     VALUES ((
             SELECT
                 ss.id
@@ -198,16 +243,49 @@ INSERT INTO surveys_answers (session_id, question_id, answer)
                 FROM
                     surveys_questions sq
                 WHERE
-                    sq.uuid = $2), $3)
+                    sq.uuid = $2), $3, $4, $5)
     ON CONFLICT (session_id,
         question_id)
     DO UPDATE SET
-        answer = EXCLUDED.answer;
+        answer = EXCLUDED.answer,
+        skipped = EXCLUDED.skipped,
+        prefilled = EXCLUDED.prefilled;
 
 -- name: GetSurveySessionsWithAnswers :many
 WITH limited_sessions AS (
     SELECT
-        ss.*
+        ss.*,
+        ROW_NUMBER() OVER (ORDER BY CASE WHEN @sort_by::text = 'uuid'
+                AND @sort_order::text = 'asc' THEN
+                ss.uuid
+            END ASC, CASE WHEN @sort_by::text = 'uuid'
+                AND @sort_order::text = 'desc' THEN
+                ss.uuid
+            END DESC, CASE WHEN @sort_by::text = 'created_at'
+                AND @sort_order::text = 'asc' THEN
+                ss.created_at
+            END ASC, CASE WHEN @sort_by::text = 'created_at'
+                AND @sort_order::text = 'desc' THEN
+                ss.created_at
+            END DESC, CASE WHEN @sort_by::text = 'completed_at'
+                AND @sort_order::text = 'asc' THEN
+                ss.completed_at
+            END ASC NULLS LAST, CASE WHEN @sort_by::text = 'completed_at'
+                AND @sort_order::text = 'desc' THEN
+                ss.completed_at
+            END DESC NULLS LAST, CASE WHEN @sort_by::text = 'status'
+                AND @sort_order::text = 'asc' THEN
+                ss.status
+            END ASC, CASE WHEN @sort_by::text = 'status'
+                AND @sort_order::text = 'desc' THEN
+                ss.status
+            END DESC, CASE WHEN @sort_by::text = 'score'
+                AND @sort_order::text = 'asc' THEN
+                ss.score
+            END ASC NULLS LAST, CASE WHEN @sort_by::text = 'score'
+                AND @sort_order::text = 'desc' THEN
+                ss.score
+            END DESC NULLS LAST, ss.created_at DESC) AS position
     FROM
         surveys_sessions ss
     WHERE
@@ -217,10 +295,12 @@ WITH limited_sessions AS (
             FROM
                 surveys s
             WHERE
-                s.uuid = $1)
-        ORDER BY
-            ss.created_at DESC
-        LIMIT $2 OFFSET $3
+                s.uuid = @survey_uuid)
+        AND (@hidden_fields::jsonb IS NULL
+            OR ss.hidden_fields @> @hidden_fields::jsonb)
+    ORDER BY
+        position
+    LIMIT @page_limit OFFSET @page_offset
 )
 SELECT
     ss.id,
@@ -228,18 +308,49 @@ SELECT
     ss.created_at,
     ss.completed_at,
     ss.status,
+    ss.display_order,
+    ss.locale,
+    ss.score,
+    ss.max_score,
+    ss.variables,
+    ss.hidden_fields,
+    ss.quota_id,
     q.question_id,
     q.uuid AS question_uuid,
     sa.answer,
+    sa.skipped,
     w.response_status,
-    w.response
+    w.response,
+    wd.status AS webhook_status,
+    wd.attempts AS webhook_attempts
 FROM
     limited_sessions AS ss
     LEFT JOIN surveys_answers AS sa ON sa.session_id = ss.id
     LEFT JOIN surveys_questions AS q ON q.id = sa.question_id
-    LEFT JOIN surveys_webhook_responses AS w ON w.session_id = ss.id
+    LEFT JOIN LATERAL (
+        SELECT
+            wr.response_status,
+            wr.response
+        FROM
+            surveys_webhook_responses AS wr
+        WHERE
+            wr.session_id = ss.id
+        ORDER BY
+            wr.id DESC
+        LIMIT 1) AS w ON TRUE
+    LEFT JOIN LATERAL (
+        SELECT
+            d.status,
+            d.attempts
+        FROM
+            surveys_webhook_deliveries AS d
+        WHERE
+            d.session_id = ss.id
+        ORDER BY
+            d.id DESC
+        LIMIT 1) AS wd ON TRUE
 ORDER BY
-    ss.created_at DESC;
+    ss.position;
 
 -- name: GetSurveySessionsCount :one
 SELECT
@@ -248,9 +359,243 @@ FROM
     surveys_sessions AS ss
     INNER JOIN surveys AS s ON s.id = ss.survey_id
 WHERE
-    s.uuid = $1;
+    s.uuid = @survey_uuid
+    AND (@hidden_fields::jsonb IS NULL
+        OR ss.hidden_fields @> @hidden_fields::jsonb);
+
+-- name: LockSurvey :exec
+SELECT
+    id
+FROM
+    surveys
+WHERE
+    uuid = $1
+FOR UPDATE;
+
+-- name: GetSurveyQuotaCount :one
+SELECT
+    COUNT(*)
+FROM
+    surveys_sessions AS ss
+    INNER JOIN surveys AS s ON s.id = ss.survey_id
+WHERE
+    s.uuid = @survey_uuid
+    AND ss.status = 'completed'
+    AND (@question_id::text = ''
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                surveys_answers AS sa
+                INNER JOIN surveys_questions AS sq ON sq.id = sa.question_id
+            WHERE
+                sa.session_id = ss.id
+                AND sq.question_id = @question_id::text
+                AND sa.skipped = FALSE
+                AND (sa.answer ->> 'value' = ANY (@answer_values::text[])
+                    OR (jsonb_typeof(sa.answer -> 'value') = 'array'
+                        AND sa.answer -> 'value' ?| @answer_values::text[]))));
+
+-- name: GetSurveyAnswerValueCounts :many
+SELECT
+    q.question_id,
+    (sa.answer ->> 'value')::bigint AS answer_value,
+    COUNT(*) AS answers_count
+FROM
+    surveys_answers AS sa
+    INNER JOIN surveys_questions AS q ON q.id = sa.question_id
+    INNER JOIN surveys AS s ON s.id = q.survey_id
+WHERE
+    s.uuid = $1
+    AND q.question_id = ANY ($2::text[])
+    AND sa.skipped = FALSE
+    -- answers stored before the question type was changed may not be integers
+    AND jsonb_typeof(sa.answer -> 'value') = 'number'
+    AND sa.answer ->> 'value' ~ '^-?[0-9]+$'
+GROUP BY
+    q.question_id,
+    answer_value;
 
 -- name: StoreWebhookResponse :exec
-INSERT INTO surveys_webhook_responses (created_at, session_id, response_status, response)
-    VALUES ($1, $2, $3, $4);
+INSERT INTO surveys_webhook_responses (created_at, session_id, response_status, response, delivery_id, attempt, error)
+    VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO surveys_webhook_deliveries (survey_id, session_id, event_type, url, method, payload, max_attempts, secret_env, headers)
+    VALUES ((
+            SELECT
+                s.id
+            FROM
+                surveys s
+            WHERE
+                s.uuid = $1), $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING
+    id,
+    uuid,
+    created_at,
+    next_attempt_at;
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE
+    surveys_webhook_deliveries
+SET
+    next_attempt_at = @next_attempt_at
+WHERE
+    id IN (
+        SELECT
+            wd.id
+        FROM
+            surveys_webhook_deliveries AS wd
+        WHERE
+            wd.status = 'pending'
+            AND wd.next_attempt_at <= @now
+        ORDER BY
+            wd.next_attempt_at
+        LIMIT @claim_limit
+        FOR UPDATE
+            SKIP LOCKED)
+RETURNING
+    id,
+    uuid,
+    created_at,
+    session_id,
+    event_type,
+    url,
+    method,
+    payload,
+    status,
+    attempts,
+    max_attempts,
+    secret_env,
+    headers;
+
+-- name: UpdateWebhookDelivery :exec
+UPDATE
+    surveys_webhook_deliveries
+SET
+    status = $1,
+    attempts = $2,
+    next_attempt_at = $3,
+    delivered_at = $4
+WHERE
+    id = $5;
 
+-- name: GetWebhookDeliveries :many
+SELECT
+    wd.id,
+    wd.uuid,
+    wd.created_at,
+    ss.uuid AS session_uuid,
+    wd.event_type,
+    wd.url,
+    wd.method,
+    wd.status,
+    wd.attempts,
+    wd.max_attempts,
+    wd.next_attempt_at,
+    wd.delivered_at
+FROM
+    surveys_webhook_deliveries AS wd
+    INNER JOIN surveys AS s ON s.id = wd.survey_id
+    LEFT JOIN surveys_sessions AS ss ON ss.id = wd.session_id
+    LEFT JOIN LATERAL (
+        SELECT
+            wr.response_status
+        FROM
+            surveys_webhook_responses AS wr
+        WHERE
+            wr.delivery_id = wd.id
+        ORDER BY
+            wr.id DESC
+        LIMIT 1) AS w ON TRUE
+WHERE
+    s.uuid = @survey_uuid
+    AND (@delivery_uuid::text = ''
+        OR wd.uuid::text = @delivery_uuid::text)
+    AND (@session_uuid::text = ''
+        OR ss.uuid::text = @session_uuid::text)
+    AND (@status::text = ''
+        OR wd.status::text = @status::text)
+    AND (@response_status::int = 0
+        OR w.response_status = @response_status::int)
+ORDER BY
+    wd.id DESC
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: GetWebhookDeliveryResponses :many
+SELECT
+    delivery_id,
+    created_at,
+    attempt,
+    response_status,
+    response,
+    error
+FROM
+    surveys_webhook_responses
+WHERE
+    delivery_id = ANY (@delivery_ids::int[])
+ORDER BY
+    id;
+
+-- name: RedeliverWebhookDelivery :execrows
+UPDATE
+    surveys_webhook_deliveries
+SET
+    status = 'pending',
+    max_attempts = $1,
+    next_attempt_at = $2,
+    delivered_at = NULL
+WHERE
+    id = $3
+    AND status <> 'pending';
+
+-- name: CreateSurveyInvitation :one
+INSERT INTO surveys_invitations (survey_id, token, email, name)
+    VALUES ((
+            SELECT
+                s.id
+            FROM
+                surveys s
+            WHERE
+                s.uuid = $1), $2, $3, $4)
+ON CONFLICT (survey_id, email)
+    DO NOTHING
+RETURNING
+    uuid,
+    created_at;
+
+-- name: GetSurveyInvitations :many
+SELECT
+    i.uuid,
+    i.created_at,
+    i.token,
+    i.email,
+    i.name,
+    i.used_at,
+    ss.uuid AS session_uuid,
+    ss.status AS session_status
+FROM
+    surveys_invitations AS i
+    INNER JOIN surveys AS s ON s.id = i.survey_id
+    LEFT JOIN surveys_sessions AS ss ON ss.id = i.session_id
+WHERE
+    s.uuid = $1
+ORDER BY
+    i.id;
+
+-- name: UseSurveyInvitation :execrows
+UPDATE
+    surveys_invitations
+SET
+    session_id = $1,
+    used_at = NOW()
+WHERE
+    survey_id = (
+        SELECT
+            s.id
+        FROM
+            surveys s
+        WHERE
+            s.uuid = $2)
+    AND token = $3
+    AND used_at IS NULL;
@@ -11,6 +11,97 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+UPDATE
+    surveys_webhook_deliveries
+SET
+    next_attempt_at = $1
+WHERE
+    id IN (
+        SELECT
+            wd.id
+        FROM
+            surveys_webhook_deliveries AS wd
+        WHERE
+            wd.status = 'pending'
+            AND wd.next_attempt_at <= $2
+        ORDER BY
+            wd.next_attempt_at
+        LIMIT $3
+        FOR UPDATE
+            SKIP LOCKED)
+RETURNING
+    id,
+    uuid,
+    created_at,
+    session_id,
+    event_type,
+    url,
+    method,
+    payload,
+    status,
+    attempts,
+    max_attempts,
+    secret_env,
+    headers
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	NextAttemptAt pgtype.Timestamp
+	Now           pgtype.Timestamp
+	ClaimLimit    int32
+}
+
+type ClaimWebhookDeliveriesRow struct {
+	ID          int32
+	Uuid        pgtype.UUID
+	CreatedAt   pgtype.Timestamp
+	SessionID   pgtype.Int4
+	EventType   string
+	Url         string
+	Method      string
+	Payload     []byte
+	Status      SurveysWebhookDeliveriesStatus
+	Attempts    int32
+	MaxAttempts int32
+	SecretEnv   pgtype.Text
+	Headers     []byte
+}
+
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, claimWebhookDeliveries, arg.NextAttemptAt, arg.Now, arg.ClaimLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimWebhookDeliveriesRow
+	for rows.Next() {
+		var i ClaimWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.CreatedAt,
+			&i.SessionID,
+			&i.EventType,
+			&i.Url,
+			&i.Method,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.MaxAttempts,
+			&i.SecretEnv,
+			&i.Headers,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createSurvey = `-- name: CreateSurvey :one
 INSERT INTO surveys (parse_status, delivery_status, error_log, name, config, url_slug)
     VALUES ($1, $2, $3, $4, $5, $6)
@@ -51,24 +142,87 @@ func (q *Queries) CreateSurvey(ctx context.Context, arg CreateSurveyParams) (Sur
 	return i, err
 }
 
+const createSurveyDeliveryTransition = `-- name: CreateSurveyDeliveryTransition :execrows
+INSERT INTO surveys_delivery_transitions (survey_id, delivery_status, scheduled_at)
+    VALUES ($1, $2, $3)
+ON CONFLICT (survey_id, delivery_status, scheduled_at)
+    DO NOTHING
+`
+
+type CreateSurveyDeliveryTransitionParams struct {
+	SurveyID       int32
+	DeliveryStatus SurveyDeliveryStatuses
+	ScheduledAt    pgtype.Timestamp
+}
+
+func (q *Queries) CreateSurveyDeliveryTransition(ctx context.Context, arg CreateSurveyDeliveryTransitionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createSurveyDeliveryTransition, arg.SurveyID, arg.DeliveryStatus, arg.ScheduledAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createSurveyInvitation = `-- name: CreateSurveyInvitation :one
+INSERT INTO surveys_invitations (survey_id, token, email, name)
+    VALUES ((
+            SELECT
+                s.id
+            FROM
+                surveys s
+            WHERE
+                s.uuid = $1), $2, $3, $4)
+ON CONFLICT (survey_id, email)
+    DO NOTHING
+RETURNING
+    uuid,
+    created_at
+`
+
+type CreateSurveyInvitationParams struct {
+	Uuid  pgtype.UUID
+	Token string
+	Email string
+	Name  pgtype.Text
+}
+
+type CreateSurveyInvitationRow struct {
+	Uuid      pgtype.UUID
+	CreatedAt pgtype.Timestamp
+}
+
+func (q *Queries) CreateSurveyInvitation(ctx context.Context, arg CreateSurveyInvitationParams) (CreateSurveyInvitationRow, error) {
+	row := q.db.QueryRow(ctx, createSurveyInvitation,
+		arg.Uuid,
+		arg.Token,
+		arg.Email,
+		arg.Name,
+	)
+	var i CreateSurveyInvitationRow
+	err := row.Scan(&i.Uuid, &i.CreatedAt)
+	return i, err
+}
+
 const createSurveySession = `-- name: CreateSurveySession :one
-INSERT INTO surveys_sessions (status, survey_id, ip_addr)
+INSERT INTO surveys_sessions (status, survey_id, ip_addr, locale, hidden_fields)
     VALUES ($1, (
             SELECT
                 s.id
             FROM
                 surveys s
             WHERE
-                s.uuid = $2), $3)
+                s.uuid = $2), $3, $4, $5)
 RETURNING
     id,
     uuid
 `
 
 type CreateSurveySessionParams struct {
-	Status NullSurveysSessionsStatus
-	Uuid   pgtype.UUID
-	IpAddr pgtype.Text
+	Status       NullSurveysSessionsStatus
+	Uuid         pgtype.UUID
+	IpAddr       pgtype.Text
+	Locale       pgtype.Text
+	HiddenFields []byte
 }
 
 type CreateSurveySessionRow struct {
@@ -77,12 +231,75 @@ type CreateSurveySessionRow struct {
 }
 
 func (q *Queries) CreateSurveySession(ctx context.Context, arg CreateSurveySessionParams) (CreateSurveySessionRow, error) {
-	row := q.db.QueryRow(ctx, createSurveySession, arg.Status, arg.Uuid, arg.IpAddr)
+	row := q.db.QueryRow(ctx, createSurveySession,
+		arg.Status,
+		arg.Uuid,
+		arg.IpAddr,
+		arg.Locale,
+		arg.HiddenFields,
+	)
 	var i CreateSurveySessionRow
 	err := row.Scan(&i.ID, &i.Uuid)
 	return i, err
 }
 
+const createWebhookDelivery = `-- name: CreateWebhookDelivery :one
+INSERT INTO surveys_webhook_deliveries (survey_id, session_id, event_type, url, method, payload, max_attempts, secret_env, headers)
+    VALUES ((
+            SELECT
+                s.id
+            FROM
+                surveys s
+            WHERE
+                s.uuid = $1), $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING
+    id,
+    uuid,
+    created_at,
+    next_attempt_at
+`
+
+type CreateWebhookDeliveryParams struct {
+	Uuid        pgtype.UUID
+	SessionID   pgtype.Int4
+	EventType   string
+	Url         string
+	Method      string
+	Payload     []byte
+	MaxAttempts int32
+	SecretEnv   pgtype.Text
+	Headers     []byte
+}
+
+type CreateWebhookDeliveryRow struct {
+	ID            int32
+	Uuid          pgtype.UUID
+	CreatedAt     pgtype.Timestamp
+	NextAttemptAt pgtype.Timestamp
+}
+
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (CreateWebhookDeliveryRow, error) {
+	row := q.db.QueryRow(ctx, createWebhookDelivery,
+		arg.Uuid,
+		arg.SessionID,
+		arg.EventType,
+		arg.Url,
+		arg.Method,
+		arg.Payload,
+		arg.MaxAttempts,
+		arg.SecretEnv,
+		arg.Headers,
+	)
+	var i CreateWebhookDeliveryRow
+	err := row.Scan(
+		&i.ID,
+		&i.Uuid,
+		&i.CreatedAt,
+		&i.NextAttemptAt,
+	)
+	return i, err
+}
+
 const deleteSurveyQuestionsNotInList = `-- name: DeleteSurveyQuestionsNotInList :exec
 DELETE FROM surveys_questions
 WHERE survey_id = $1
@@ -109,6 +326,58 @@ func (q *Queries) DeleteSurveySession(ctx context.Context, uuid pgtype.UUID) err
 	return err
 }
 
+const getSurveyAnswerValueCounts = `-- name: GetSurveyAnswerValueCounts :many
+SELECT
+    q.question_id,
+    (sa.answer ->> 'value')::bigint AS answer_value,
+    COUNT(*) AS answers_count
+FROM
+    surveys_answers AS sa
+    INNER JOIN surveys_questions AS q ON q.id = sa.question_id
+    INNER JOIN surveys AS s ON s.id = q.survey_id
+WHERE
+    s.uuid = $1
+    AND q.question_id = ANY ($2::text[])
+    AND sa.skipped = FALSE
+    -- answers stored before the question type was changed may not be integers
+    AND jsonb_typeof(sa.answer -> 'value') = 'number'
+    AND sa.answer ->> 'value' ~ '^-?[0-9]+$'
+GROUP BY
+    q.question_id,
+    answer_value
+`
+
+type GetSurveyAnswerValueCountsParams struct {
+	Uuid    pgtype.UUID
+	Column2 []string
+}
+
+type GetSurveyAnswerValueCountsRow struct {
+	QuestionID   string
+	AnswerValue  int64
+	AnswersCount int64
+}
+
+func (q *Queries) GetSurveyAnswerValueCounts(ctx context.Context, arg GetSurveyAnswerValueCountsParams) ([]GetSurveyAnswerValueCountsRow, error) {
+	rows, err := q.db.Query(ctx, getSurveyAnswerValueCounts, arg.Uuid, arg.Column2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSurveyAnswerValueCountsRow
+	for rows.Next() {
+		var i GetSurveyAnswerValueCountsRow
+		if err := rows.Scan(&i.QuestionID, &i.AnswerValue, &i.AnswersCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSurveyByURLSlug = `-- name: GetSurveyByURLSlug :one
 SELECT
     s.id,
@@ -201,6 +470,66 @@ func (q *Queries) GetSurveyByUUID(ctx context.Context, uuid pgtype.UUID) (GetSur
 	return i, err
 }
 
+const getSurveyInvitations = `-- name: GetSurveyInvitations :many
+SELECT
+    i.uuid,
+    i.created_at,
+    i.token,
+    i.email,
+    i.name,
+    i.used_at,
+    ss.uuid AS session_uuid,
+    ss.status AS session_status
+FROM
+    surveys_invitations AS i
+    INNER JOIN surveys AS s ON s.id = i.survey_id
+    LEFT JOIN surveys_sessions AS ss ON ss.id = i.session_id
+WHERE
+    s.uuid = $1
+ORDER BY
+    i.id
+`
+
+type GetSurveyInvitationsRow struct {
+	Uuid          pgtype.UUID
+	CreatedAt     pgtype.Timestamp
+	Token         string
+	Email         string
+	Name          pgtype.Text
+	UsedAt        pgtype.Timestamp
+	SessionUuid   pgtype.UUID
+	SessionStatus NullSurveysSessionsStatus
+}
+
+func (q *Queries) GetSurveyInvitations(ctx context.Context, uuid pgtype.UUID) ([]GetSurveyInvitationsRow, error) {
+	rows, err := q.db.Query(ctx, getSurveyInvitations, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSurveyInvitationsRow
+	for rows.Next() {
+		var i GetSurveyInvitationsRow
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.CreatedAt,
+			&i.Token,
+			&i.Email,
+			&i.Name,
+			&i.UsedAt,
+			&i.SessionUuid,
+			&i.SessionStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSurveyQuestions = `-- name: GetSurveyQuestions :many
 SELECT
     sq.uuid,
@@ -238,12 +567,57 @@ func (q *Queries) GetSurveyQuestions(ctx context.Context, surveyID int32) ([]Get
 	return items, nil
 }
 
+const getSurveyQuotaCount = `-- name: GetSurveyQuotaCount :one
+SELECT
+    COUNT(*)
+FROM
+    surveys_sessions AS ss
+    INNER JOIN surveys AS s ON s.id = ss.survey_id
+WHERE
+    s.uuid = $1
+    AND ss.status = 'completed'
+    AND ($2::text = ''
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                surveys_answers AS sa
+                INNER JOIN surveys_questions AS sq ON sq.id = sa.question_id
+            WHERE
+                sa.session_id = ss.id
+                AND sq.question_id = $2::text
+                AND sa.skipped = FALSE
+                AND (sa.answer ->> 'value' = ANY ($3::text[])
+                    OR (jsonb_typeof(sa.answer -> 'value') = 'array'
+                        AND sa.answer -> 'value' ?| $3::text[]))))
+`
+
+type GetSurveyQuotaCountParams struct {
+	SurveyUuid   pgtype.UUID
+	QuestionID   string
+	AnswerValues []string
+}
+
+func (q *Queries) GetSurveyQuotaCount(ctx context.Context, arg GetSurveyQuotaCountParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getSurveyQuotaCount, arg.SurveyUuid, arg.QuestionID, arg.AnswerValues)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getSurveySession = `-- name: GetSurveySession :one
 SELECT
     ss.id,
     ss.uuid,
     ss.created_at,
     ss.status,
+    ss.display_order,
+    ss.locale,
+    ss.score,
+    ss.max_score,
+    ss.variables,
+    ss.hidden_fields,
+    ss.quota_id,
     s.uuid AS survey_uuid
 FROM
     surveys_sessions AS ss
@@ -259,11 +633,18 @@ type GetSurveySessionParams struct {
 }
 
 type GetSurveySessionRow struct {
-	ID         int32
-	Uuid       pgtype.UUID
-	CreatedAt  pgtype.Timestamp
-	Status     NullSurveysSessionsStatus
-	SurveyUuid pgtype.UUID
+	ID           int32
+	Uuid         pgtype.UUID
+	CreatedAt    pgtype.Timestamp
+	Status       NullSurveysSessionsStatus
+	DisplayOrder []byte
+	Locale       pgtype.Text
+	Score        pgtype.Int4
+	MaxScore     pgtype.Int4
+	Variables    []byte
+	HiddenFields []byte
+	QuotaID      pgtype.Text
+	SurveyUuid   pgtype.UUID
 }
 
 func (q *Queries) GetSurveySession(ctx context.Context, arg GetSurveySessionParams) (GetSurveySessionRow, error) {
@@ -274,6 +655,13 @@ func (q *Queries) GetSurveySession(ctx context.Context, arg GetSurveySessionPara
 		&i.Uuid,
 		&i.CreatedAt,
 		&i.Status,
+		&i.DisplayOrder,
+		&i.Locale,
+		&i.Score,
+		&i.MaxScore,
+		&i.Variables,
+		&i.HiddenFields,
+		&i.QuotaID,
 		&i.SurveyUuid,
 	)
 	return i, err
@@ -283,7 +671,9 @@ const getSurveySessionAnswers = `-- name: GetSurveySessionAnswers :many
 SELECT
     q.question_id,
     q.uuid AS question_uuid,
-    sa.answer
+    sa.answer,
+    sa.skipped,
+    sa.prefilled
 FROM
     surveys_answers AS sa
     LEFT JOIN surveys_questions AS q ON q.id = sa.question_id
@@ -303,6 +693,8 @@ type GetSurveySessionAnswersRow struct {
 	QuestionID   pgtype.Text
 	QuestionUuid pgtype.UUID
 	Answer       []byte
+	Skipped      bool
+	Prefilled    bool
 }
 
 func (q *Queries) GetSurveySessionAnswers(ctx context.Context, uuid pgtype.UUID) ([]GetSurveySessionAnswersRow, error) {
@@ -314,7 +706,13 @@ func (q *Queries) GetSurveySessionAnswers(ctx context.Context, uuid pgtype.UUID)
 	var items []GetSurveySessionAnswersRow
 	for rows.Next() {
 		var i GetSurveySessionAnswersRow
-		if err := rows.Scan(&i.QuestionID, &i.QuestionUuid, &i.Answer); err != nil {
+		if err := rows.Scan(
+			&i.QuestionID,
+			&i.QuestionUuid,
+			&i.Answer,
+			&i.Skipped,
+			&i.Prefilled,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -325,48 +723,31 @@ func (q *Queries) GetSurveySessionAnswers(ctx context.Context, uuid pgtype.UUID)
 	return items, nil
 }
 
-const getSurveySessionByIPAddress = `-- name: GetSurveySessionByIPAddress :one
+const getSurveySessionsCount = `-- name: GetSurveySessionsCount :one
 SELECT
-    ss.id,
-    ss.uuid,
-    ss.created_at,
-    ss.status,
-    s.uuid AS survey_uuid
+    COUNT(*)
 FROM
     surveys_sessions AS ss
     INNER JOIN surveys AS s ON s.id = ss.survey_id
 WHERE
     s.uuid = $1
-    AND ss.ip_addr = $2
+    AND ($2::jsonb IS NULL
+        OR ss.hidden_fields @> $2::jsonb)
 `
 
-type GetSurveySessionByIPAddressParams struct {
-	Uuid   pgtype.UUID
-	IpAddr pgtype.Text
-}
-
-type GetSurveySessionByIPAddressRow struct {
-	ID         int32
-	Uuid       pgtype.UUID
-	CreatedAt  pgtype.Timestamp
-	Status     NullSurveysSessionsStatus
-	SurveyUuid pgtype.UUID
+type GetSurveySessionsCountParams struct {
+	SurveyUuid   pgtype.UUID
+	HiddenFields []byte
 }
 
-func (q *Queries) GetSurveySessionByIPAddress(ctx context.Context, arg GetSurveySessionByIPAddressParams) (GetSurveySessionByIPAddressRow, error) {
-	row := q.db.QueryRow(ctx, getSurveySessionByIPAddress, arg.Uuid, arg.IpAddr)
-	var i GetSurveySessionByIPAddressRow
-	err := row.Scan(
-		&i.ID,
-		&i.Uuid,
-		&i.CreatedAt,
-		&i.Status,
-		&i.SurveyUuid,
-	)
-	return i, err
+func (q *Queries) GetSurveySessionsCount(ctx context.Context, arg GetSurveySessionsCountParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getSurveySessionsCount, arg.SurveyUuid, arg.HiddenFields)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-const getSurveySessionsCount = `-- name: GetSurveySessionsCount :one
+const getSurveySessionsCountByIPAddress = `-- name: GetSurveySessionsCountByIPAddress :one
 SELECT
     COUNT(*)
 FROM
@@ -374,10 +755,16 @@ FROM
     INNER JOIN surveys AS s ON s.id = ss.survey_id
 WHERE
     s.uuid = $1
+    AND ss.ip_addr = $2
 `
 
-func (q *Queries) GetSurveySessionsCount(ctx context.Context, uuid pgtype.UUID) (int64, error) {
-	row := q.db.QueryRow(ctx, getSurveySessionsCount, uuid)
+type GetSurveySessionsCountByIPAddressParams struct {
+	Uuid   pgtype.UUID
+	IpAddr pgtype.Text
+}
+
+func (q *Queries) GetSurveySessionsCountByIPAddress(ctx context.Context, arg GetSurveySessionsCountByIPAddressParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getSurveySessionsCountByIPAddress, arg.Uuid, arg.IpAddr)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -386,7 +773,38 @@ func (q *Queries) GetSurveySessionsCount(ctx context.Context, uuid pgtype.UUID)
 const getSurveySessionsWithAnswers = `-- name: GetSurveySessionsWithAnswers :many
 WITH limited_sessions AS (
     SELECT
-        ss.id, ss.uuid, ss.created_at, ss.completed_at, ss.status, ss.survey_id, ss.ip_addr
+        ss.id, ss.uuid, ss.created_at, ss.completed_at, ss.status, ss.survey_id, ss.ip_addr, ss.display_order, ss.locale, ss.score, ss.max_score, ss.variables, ss.hidden_fields, ss.quota_id,
+        ROW_NUMBER() OVER (ORDER BY CASE WHEN $1::text = 'uuid'
+                AND $2::text = 'asc' THEN
+                ss.uuid
+            END ASC, CASE WHEN $1::text = 'uuid'
+                AND $2::text = 'desc' THEN
+                ss.uuid
+            END DESC, CASE WHEN $1::text = 'created_at'
+                AND $2::text = 'asc' THEN
+                ss.created_at
+            END ASC, CASE WHEN $1::text = 'created_at'
+                AND $2::text = 'desc' THEN
+                ss.created_at
+            END DESC, CASE WHEN $1::text = 'completed_at'
+                AND $2::text = 'asc' THEN
+                ss.completed_at
+            END ASC NULLS LAST, CASE WHEN $1::text = 'completed_at'
+                AND $2::text = 'desc' THEN
+                ss.completed_at
+            END DESC NULLS LAST, CASE WHEN $1::text = 'status'
+                AND $2::text = 'asc' THEN
+                ss.status
+            END ASC, CASE WHEN $1::text = 'status'
+                AND $2::text = 'desc' THEN
+                ss.status
+            END DESC, CASE WHEN $1::text = 'score'
+                AND $2::text = 'asc' THEN
+                ss.score
+            END ASC NULLS LAST, CASE WHEN $1::text = 'score'
+                AND $2::text = 'desc' THEN
+                ss.score
+            END DESC NULLS LAST, ss.created_at DESC) AS position
     FROM
         surveys_sessions ss
     WHERE
@@ -396,10 +814,12 @@ WITH limited_sessions AS (
             FROM
                 surveys s
             WHERE
-                s.uuid = $1)
-        ORDER BY
-            ss.created_at DESC
-        LIMIT $2 OFFSET $3
+                s.uuid = $3)
+        AND ($4::jsonb IS NULL
+            OR ss.hidden_fields @> $4::jsonb)
+    ORDER BY
+        position
+    LIMIT $5 OFFSET $6
 )
 SELECT
     ss.id,
@@ -407,41 +827,92 @@ SELECT
     ss.created_at,
     ss.completed_at,
     ss.status,
+    ss.display_order,
+    ss.locale,
+    ss.score,
+    ss.max_score,
+    ss.variables,
+    ss.hidden_fields,
+    ss.quota_id,
     q.question_id,
     q.uuid AS question_uuid,
     sa.answer,
+    sa.skipped,
     w.response_status,
-    w.response
+    w.response,
+    wd.status AS webhook_status,
+    wd.attempts AS webhook_attempts
 FROM
     limited_sessions AS ss
     LEFT JOIN surveys_answers AS sa ON sa.session_id = ss.id
     LEFT JOIN surveys_questions AS q ON q.id = sa.question_id
-    LEFT JOIN surveys_webhook_responses AS w ON w.session_id = ss.id
+    LEFT JOIN LATERAL (
+        SELECT
+            wr.response_status,
+            wr.response
+        FROM
+            surveys_webhook_responses AS wr
+        WHERE
+            wr.session_id = ss.id
+        ORDER BY
+            wr.id DESC
+        LIMIT 1) AS w ON TRUE
+    LEFT JOIN LATERAL (
+        SELECT
+            d.status,
+            d.attempts
+        FROM
+            surveys_webhook_deliveries AS d
+        WHERE
+            d.session_id = ss.id
+        ORDER BY
+            d.id DESC
+        LIMIT 1) AS wd ON TRUE
 ORDER BY
-    ss.created_at DESC
+    ss.position
 `
 
 type GetSurveySessionsWithAnswersParams struct {
-	Uuid   pgtype.UUID
-	Limit  int32
-	Offset int32
+	SortBy       string
+	SortOrder    string
+	SurveyUuid   pgtype.UUID
+	HiddenFields []byte
+	PageLimit    int32
+	PageOffset   int32
 }
 
 type GetSurveySessionsWithAnswersRow struct {
-	ID             int32
-	Uuid           pgtype.UUID
-	CreatedAt      pgtype.Timestamp
-	CompletedAt    pgtype.Timestamp
-	Status         NullSurveysSessionsStatus
-	QuestionID     pgtype.Text
-	QuestionUuid   pgtype.UUID
-	Answer         []byte
-	ResponseStatus pgtype.Int4
-	Response       pgtype.Text
+	ID              int32
+	Uuid            pgtype.UUID
+	CreatedAt       pgtype.Timestamp
+	CompletedAt     pgtype.Timestamp
+	Status          NullSurveysSessionsStatus
+	DisplayOrder    []byte
+	Locale          pgtype.Text
+	Score           pgtype.Int4
+	MaxScore        pgtype.Int4
+	Variables       []byte
+	HiddenFields    []byte
+	QuotaID         pgtype.Text
+	QuestionID      pgtype.Text
+	QuestionUuid    pgtype.UUID
+	Answer          []byte
+	Skipped         pgtype.Bool
+	ResponseStatus  pgtype.Int4
+	Response        pgtype.Text
+	WebhookStatus   NullSurveysWebhookDeliveriesStatus
+	WebhookAttempts pgtype.Int4
 }
 
 func (q *Queries) GetSurveySessionsWithAnswers(ctx context.Context, arg GetSurveySessionsWithAnswersParams) ([]GetSurveySessionsWithAnswersRow, error) {
-	rows, err := q.db.Query(ctx, getSurveySessionsWithAnswers, arg.Uuid, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getSurveySessionsWithAnswers,
+		arg.SortBy,
+		arg.SortOrder,
+		arg.SurveyUuid,
+		arg.HiddenFields,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -455,11 +926,21 @@ func (q *Queries) GetSurveySessionsWithAnswers(ctx context.Context, arg GetSurve
 			&i.CreatedAt,
 			&i.CompletedAt,
 			&i.Status,
+			&i.DisplayOrder,
+			&i.Locale,
+			&i.Score,
+			&i.MaxScore,
+			&i.Variables,
+			&i.HiddenFields,
+			&i.QuotaID,
 			&i.QuestionID,
 			&i.QuestionUuid,
 			&i.Answer,
+			&i.Skipped,
 			&i.ResponseStatus,
 			&i.Response,
+			&i.WebhookStatus,
+			&i.WebhookAttempts,
 		); err != nil {
 			return nil, err
 		}
@@ -555,16 +1036,222 @@ func (q *Queries) GetSurveys(ctx context.Context, arg GetSurveysParams) ([]GetSu
 	return items, nil
 }
 
+const getWebhookDeliveries = `-- name: GetWebhookDeliveries :many
+SELECT
+    wd.id,
+    wd.uuid,
+    wd.created_at,
+    ss.uuid AS session_uuid,
+    wd.event_type,
+    wd.url,
+    wd.method,
+    wd.status,
+    wd.attempts,
+    wd.max_attempts,
+    wd.next_attempt_at,
+    wd.delivered_at
+FROM
+    surveys_webhook_deliveries AS wd
+    INNER JOIN surveys AS s ON s.id = wd.survey_id
+    LEFT JOIN surveys_sessions AS ss ON ss.id = wd.session_id
+    LEFT JOIN LATERAL (
+        SELECT
+            wr.response_status
+        FROM
+            surveys_webhook_responses AS wr
+        WHERE
+            wr.delivery_id = wd.id
+        ORDER BY
+            wr.id DESC
+        LIMIT 1) AS w ON TRUE
+WHERE
+    s.uuid = $1
+    AND ($2::text = ''
+        OR wd.uuid::text = $2::text)
+    AND ($3::text = ''
+        OR ss.uuid::text = $3::text)
+    AND ($4::text = ''
+        OR wd.status::text = $4::text)
+    AND ($5::int = 0
+        OR w.response_status = $5::int)
+ORDER BY
+    wd.id DESC
+LIMIT $6 OFFSET $7
+`
+
+type GetWebhookDeliveriesParams struct {
+	SurveyUuid     pgtype.UUID
+	DeliveryUuid   string
+	SessionUuid    string
+	Status         string
+	ResponseStatus int32
+	PageLimit      int32
+	PageOffset     int32
+}
+
+type GetWebhookDeliveriesRow struct {
+	ID            int32
+	Uuid          pgtype.UUID
+	CreatedAt     pgtype.Timestamp
+	SessionUuid   pgtype.UUID
+	EventType     string
+	Url           string
+	Method        string
+	Status        SurveysWebhookDeliveriesStatus
+	Attempts      int32
+	MaxAttempts   int32
+	NextAttemptAt pgtype.Timestamp
+	DeliveredAt   pgtype.Timestamp
+}
+
+func (q *Queries) GetWebhookDeliveries(ctx context.Context, arg GetWebhookDeliveriesParams) ([]GetWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, getWebhookDeliveries,
+		arg.SurveyUuid,
+		arg.DeliveryUuid,
+		arg.SessionUuid,
+		arg.Status,
+		arg.ResponseStatus,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWebhookDeliveriesRow
+	for rows.Next() {
+		var i GetWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.CreatedAt,
+			&i.SessionUuid,
+			&i.EventType,
+			&i.Url,
+			&i.Method,
+			&i.Status,
+			&i.Attempts,
+			&i.MaxAttempts,
+			&i.NextAttemptAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhookDeliveryResponses = `-- name: GetWebhookDeliveryResponses :many
+SELECT
+    delivery_id,
+    created_at,
+    attempt,
+    response_status,
+    response,
+    error
+FROM
+    surveys_webhook_responses
+WHERE
+    delivery_id = ANY ($1::int[])
+ORDER BY
+    id
+`
+
+type GetWebhookDeliveryResponsesRow struct {
+	DeliveryID     pgtype.Int4
+	CreatedAt      pgtype.Timestamp
+	Attempt        pgtype.Int4
+	ResponseStatus int32
+	Response       pgtype.Text
+	Error          pgtype.Text
+}
+
+func (q *Queries) GetWebhookDeliveryResponses(ctx context.Context, deliveryIds []int32) ([]GetWebhookDeliveryResponsesRow, error) {
+	rows, err := q.db.Query(ctx, getWebhookDeliveryResponses, deliveryIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWebhookDeliveryResponsesRow
+	for rows.Next() {
+		var i GetWebhookDeliveryResponsesRow
+		if err := rows.Scan(
+			&i.DeliveryID,
+			&i.CreatedAt,
+			&i.Attempt,
+			&i.ResponseStatus,
+			&i.Response,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockSurvey = `-- name: LockSurvey :exec
+SELECT
+    id
+FROM
+    surveys
+WHERE
+    uuid = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockSurvey(ctx context.Context, uuid pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, lockSurvey, uuid)
+	return err
+}
+
+const redeliverWebhookDelivery = `-- name: RedeliverWebhookDelivery :execrows
+UPDATE
+    surveys_webhook_deliveries
+SET
+    status = 'pending',
+    max_attempts = $1,
+    next_attempt_at = $2,
+    delivered_at = NULL
+WHERE
+    id = $3
+    AND status <> 'pending'
+`
+
+type RedeliverWebhookDeliveryParams struct {
+	MaxAttempts   int32
+	NextAttemptAt pgtype.Timestamp
+	ID            int32
+}
+
+func (q *Queries) RedeliverWebhookDelivery(ctx context.Context, arg RedeliverWebhookDeliveryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, redeliverWebhookDelivery, arg.MaxAttempts, arg.NextAttemptAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const storeWebhookResponse = `-- name: StoreWebhookResponse :exec
-INSERT INTO surveys_webhook_responses (created_at, session_id, response_status, response)
-    VALUES ($1, $2, $3, $4)
+INSERT INTO surveys_webhook_responses (created_at, session_id, response_status, response, delivery_id, attempt, error)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
 `
 
 type StoreWebhookResponseParams struct {
 	CreatedAt      pgtype.Timestamp
-	SessionID      int32
+	SessionID      pgtype.Int4
 	ResponseStatus int32
 	Response       pgtype.Text
+	DeliveryID     pgtype.Int4
+	Attempt        pgtype.Int4
+	Error          pgtype.Text
 }
 
 func (q *Queries) StoreWebhookResponse(ctx context.Context, arg StoreWebhookResponseParams) error {
@@ -573,6 +1260,9 @@ func (q *Queries) StoreWebhookResponse(ctx context.Context, arg StoreWebhookResp
 		arg.SessionID,
 		arg.ResponseStatus,
 		arg.Response,
+		arg.DeliveryID,
+		arg.Attempt,
+		arg.Error,
 	)
 	return err
 }
@@ -614,6 +1304,67 @@ func (q *Queries) UpdateSurvey(ctx context.Context, arg UpdateSurveyParams) erro
 	return err
 }
 
+const updateSurveySessionDisplayOrder = `-- name: UpdateSurveySessionDisplayOrder :exec
+UPDATE
+    surveys_sessions
+SET
+    display_order = $1
+WHERE
+    uuid = $2
+`
+
+type UpdateSurveySessionDisplayOrderParams struct {
+	DisplayOrder []byte
+	Uuid         pgtype.UUID
+}
+
+func (q *Queries) UpdateSurveySessionDisplayOrder(ctx context.Context, arg UpdateSurveySessionDisplayOrderParams) error {
+	_, err := q.db.Exec(ctx, updateSurveySessionDisplayOrder, arg.DisplayOrder, arg.Uuid)
+	return err
+}
+
+const updateSurveySessionScore = `-- name: UpdateSurveySessionScore :exec
+UPDATE
+    surveys_sessions
+SET
+    score = $1,
+    max_score = $2
+WHERE
+    uuid = $3
+`
+
+type UpdateSurveySessionScoreParams struct {
+	Score    pgtype.Int4
+	MaxScore pgtype.Int4
+	Uuid     pgtype.UUID
+}
+
+func (q *Queries) UpdateSurveySessionScore(ctx context.Context, arg UpdateSurveySessionScoreParams) error {
+	_, err := q.db.Exec(ctx, updateSurveySessionScore, arg.Score, arg.MaxScore, arg.Uuid)
+	return err
+}
+
+const updateSurveySessionScreenedOut = `-- name: UpdateSurveySessionScreenedOut :exec
+UPDATE
+    surveys_sessions
+SET
+    status = $1,
+    quota_id = $2
+WHERE
+    uuid = $3
+`
+
+type UpdateSurveySessionScreenedOutParams struct {
+	Status  NullSurveysSessionsStatus
+	QuotaID pgtype.Text
+	Uuid    pgtype.UUID
+}
+
+func (q *Queries) UpdateSurveySessionScreenedOut(ctx context.Context, arg UpdateSurveySessionScreenedOutParams) error {
+	_, err := q.db.Exec(ctx, updateSurveySessionScreenedOut, arg.Status, arg.QuotaID, arg.Uuid)
+	return err
+}
+
 const updateSurveySessionStatus = `-- name: UpdateSurveySessionStatus :exec
 UPDATE
     surveys_sessions
@@ -653,6 +1404,56 @@ func (q *Queries) UpdateSurveySessionStatusCompleted(ctx context.Context, arg Up
 	return err
 }
 
+const updateSurveySessionVariables = `-- name: UpdateSurveySessionVariables :exec
+UPDATE
+    surveys_sessions
+SET
+    variables = $1
+WHERE
+    uuid = $2
+`
+
+type UpdateSurveySessionVariablesParams struct {
+	Variables []byte
+	Uuid      pgtype.UUID
+}
+
+func (q *Queries) UpdateSurveySessionVariables(ctx context.Context, arg UpdateSurveySessionVariablesParams) error {
+	_, err := q.db.Exec(ctx, updateSurveySessionVariables, arg.Variables, arg.Uuid)
+	return err
+}
+
+const updateWebhookDelivery = `-- name: UpdateWebhookDelivery :exec
+UPDATE
+    surveys_webhook_deliveries
+SET
+    status = $1,
+    attempts = $2,
+    next_attempt_at = $3,
+    delivered_at = $4
+WHERE
+    id = $5
+`
+
+type UpdateWebhookDeliveryParams struct {
+	Status        SurveysWebhookDeliveriesStatus
+	Attempts      int32
+	NextAttemptAt pgtype.Timestamp
+	DeliveredAt   pgtype.Timestamp
+	ID            int32
+}
+
+func (q *Queries) UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) error {
+	_, err := q.db.Exec(ctx, updateWebhookDelivery,
+		arg.Status,
+		arg.Attempts,
+		arg.NextAttemptAt,
+		arg.DeliveredAt,
+		arg.ID,
+	)
+	return err
+}
+
 const upsertSurveyQuestion = `-- name: UpsertSurveyQuestion :exec
 INSERT INTO surveys_questions (survey_id, question_id)
     VALUES ($1, $2)
@@ -671,7 +1472,7 @@ func (q *Queries) UpsertSurveyQuestion(ctx context.Context, arg UpsertSurveyQues
 }
 
 const upsertSurveyQuestionAnswer = `-- name: UpsertSurveyQuestionAnswer :exec
-INSERT INTO surveys_answers (session_id, question_id, answer)
+INSERT INTO surveys_answers (session_id, question_id, answer, skipped, prefilled)
     VALUES ((
             SELECT
                 ss.id
@@ -684,20 +1485,62 @@ INSERT INTO surveys_answers (session_id, question_id, answer)
                 FROM
                     surveys_questions sq
                 WHERE
-                    sq.uuid = $2), $3)
+                    sq.uuid = $2), $3, $4, $5)
     ON CONFLICT (session_id,
         question_id)
     DO UPDATE SET
-        answer = EXCLUDED.answer
+        answer = EXCLUDED.answer,
+        skipped = EXCLUDED.skipped,
+        prefilled = EXCLUDED.prefilled
 `
 
 type UpsertSurveyQuestionAnswerParams struct {
-	Uuid   pgtype.UUID
-	Uuid_2 pgtype.UUID
-	Answer []byte
+	Uuid      pgtype.UUID
+	Uuid_2    pgtype.UUID
+	Answer    []byte
+	Skipped   bool
+	Prefilled bool
 }
 
 func (q *Queries) UpsertSurveyQuestionAnswer(ctx context.Context, arg UpsertSurveyQuestionAnswerParams) error {
-	_, err := q.db.Exec(ctx, upsertSurveyQuestionAnswer, arg.Uuid, arg.Uuid_2, arg.Answer)
+	_, err := q.db.Exec(ctx, upsertSurveyQuestionAnswer,
+		arg.Uuid,
+		arg.Uuid_2,
+		arg.Answer,
+		arg.Skipped,
+		arg.Prefilled,
+	)
 	return err
 }
+
+const useSurveyInvitation = `-- name: UseSurveyInvitation :execrows
+UPDATE
+    surveys_invitations
+SET
+    session_id = $1,
+    used_at = NOW()
+WHERE
+    survey_id = (
+        SELECT
+            s.id
+        FROM
+            surveys s
+        WHERE
+            s.uuid = $2)
+    AND token = $3
+    AND used_at IS NULL
+`
+
+type UseSurveyInvitationParams struct {
+	SessionID pgtype.Int4
+	Uuid      pgtype.UUID
+	Token     string
+}
+
+func (q *Queries) UseSurveyInvitation(ctx context.Context, arg UseSurveyInvitationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useSurveyInvitation, arg.SessionID, arg.Uuid, arg.Token)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,381 @@
+package expr
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type function struct {
+	MinArgs int
+	// -1 for variadic functions
+	MaxArgs int
+	Call    func(args []interface{}) (interface{}, error)
+}
+
+var functions = map[string]function{
+	"min":      {MinArgs: 1, MaxArgs: -1, Call: aggregate(slices.Min[[]float64])},
+	"max":      {MinArgs: 1, MaxArgs: -1, Call: aggregate(slices.Max[[]float64])},
+	"sum":      {MinArgs: 1, MaxArgs: -1, Call: aggregate(sum)},
+	"avg":      {MinArgs: 1, MaxArgs: -1, Call: aggregate(func(values []float64) float64 { return sum(values) / float64(len(values)) })},
+	"round":    {MinArgs: 1, MaxArgs: 2, Call: round},
+	"abs":      {MinArgs: 1, MaxArgs: 1, Call: abs},
+	"len":      {MinArgs: 1, MaxArgs: 1, Call: length},
+	"contains": {MinArgs: 2, MaxArgs: 2, Call: contains},
+	"coalesce": {MinArgs: 1, MaxArgs: -1, Call: coalesce},
+	// evaluated lazily, see evalCall
+	"if": {MinArgs: 3, MaxArgs: 3},
+}
+
+// Eval evaluates the expression with the given values, supported values are numbers, strings,
+// booleans, nil and lists of them. Operations with nil (e.g. unanswered questions) result in nil
+func (e *Expr) Eval(values map[string]interface{}) (interface{}, error) {
+	return eval(e.root, values)
+}
+
+func eval(n node, values map[string]interface{}) (interface{}, error) {
+	switch v := n.(type) {
+	case literalNode:
+		return v.Value, nil
+	case identNode:
+		value, ok := values[v.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown identifier: %s", v.Name)
+		}
+		return normalize(value)
+	case unaryNode:
+		x, err := eval(v.X, values)
+		if err != nil {
+			return nil, err
+		}
+		return evalUnary(v.Op, x)
+	case binaryNode:
+		return evalBinary(v, values)
+	case callNode:
+		return evalCall(v, values)
+	}
+
+	return nil, errors.New("invalid expression")
+}
+
+func evalUnary(op string, x interface{}) (interface{}, error) {
+	if op == "!" {
+		return !truthy(x), nil
+	}
+
+	if x == nil {
+		return nil, nil
+	}
+	num, ok := x.(float64)
+	if !ok {
+		return nil, fmt.Errorf("operator %s requires a number", op)
+	}
+
+	return -num, nil
+}
+
+func evalBinary(n binaryNode, values map[string]interface{}) (interface{}, error) {
+	left, err := eval(n.Left, values)
+	if err != nil {
+		return nil, err
+	}
+
+	// logical operators are short-circuited
+	switch n.Op {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+	}
+
+	right, err := eval(n.Right, values)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case "&&", "||":
+		return truthy(right), nil
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	}
+
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	if n.Op == "+" {
+		if l, ok := left.(string); ok {
+			if r, ok := right.(string); ok {
+				return l + r, nil
+			}
+		}
+	}
+
+	switch n.Op {
+	case "<", "<=", ">", ">=":
+		return compare(n.Op, left, right)
+	}
+
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("operator %s requires numbers", n.Op)
+	}
+
+	switch n.Op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return l / r, nil
+	case "%":
+		if r == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return math.Mod(l, r), nil
+	}
+
+	return nil, fmt.Errorf("unknown operator: %s", n.Op)
+}
+
+func evalCall(n callNode, values map[string]interface{}) (interface{}, error) {
+	if n.Name == "if" {
+		cond, err := eval(n.Args[0], values)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(cond) {
+			return eval(n.Args[1], values)
+		}
+		return eval(n.Args[2], values)
+	}
+
+	args := []interface{}{}
+	for _, arg := range n.Args {
+		value, err := eval(arg, values)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+
+	return functions[n.Name].Call(args)
+}
+
+// normalize converts Go values to the values supported by expressions
+func normalize(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil, float64, string, bool:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list, nil
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized, err := normalize(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = normalized
+		}
+		return list, nil
+	}
+
+	return nil, fmt.Errorf("unsupported value type: %T", value)
+}
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	}
+
+	return false
+}
+
+func equal(left interface{}, right interface{}) bool {
+	return reflect.DeepEqual(left, right)
+}
+
+func compare(op string, left interface{}, right interface{}) (interface{}, error) {
+	var c int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, fmt.Errorf("operator %s requires values of the same type", op)
+		}
+		c = cmp.Compare(l, r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("operator %s requires values of the same type", op)
+		}
+		c = cmp.Compare(l, r)
+	default:
+		return nil, fmt.Errorf("operator %s requires numbers or strings", op)
+	}
+
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	}
+
+	return c >= 0, nil
+}
+
+// aggregate calls fn with numbers from the arguments and lists, nil values are ignored
+func aggregate(fn func(values []float64) float64) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		numbers := []float64{}
+		var collect func(values []interface{}) error
+		collect = func(values []interface{}) error {
+			for _, value := range values {
+				switch v := value.(type) {
+				case nil:
+				case float64:
+					numbers = append(numbers, v)
+				case []interface{}:
+					if err := collect(v); err != nil {
+						return err
+					}
+				default:
+					return fmt.Errorf("numbers are required, got %T", value)
+				}
+			}
+			return nil
+		}
+		if err := collect(args); err != nil {
+			return nil, err
+		}
+
+		if len(numbers) == 0 {
+			return nil, nil
+		}
+
+		return fn(numbers), nil
+	}
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+
+	return total
+}
+
+func round(args []interface{}) (interface{}, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	x, ok := args[0].(float64)
+	if !ok {
+		return nil, errors.New("round requires a number")
+	}
+
+	digits := 0.0
+	if len(args) == 2 {
+		d, ok := args[1].(float64)
+		if !ok || d < 0 || d > 15 {
+			return nil, errors.New("round requires digits between 0 and 15")
+		}
+		digits = math.Trunc(d)
+	}
+
+	pow := math.Pow(10, digits)
+	return math.Round(x*pow) / pow, nil
+}
+
+func abs(args []interface{}) (interface{}, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	x, ok := args[0].(float64)
+	if !ok {
+		return nil, errors.New("abs requires a number")
+	}
+
+	return math.Abs(x), nil
+}
+
+func length(args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case nil:
+		return 0.0, nil
+	case string:
+		return float64(len([]rune(v))), nil
+	case []interface{}:
+		return float64(len(v)), nil
+	}
+
+	return nil, errors.New("len requires a string or a list")
+}
+
+func contains(args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case nil:
+		return false, nil
+	case string:
+		s, ok := args[1].(string)
+		if !ok {
+			return nil, errors.New("contains requires a string")
+		}
+		return strings.Contains(v, s), nil
+	case []interface{}:
+		for _, item := range v {
+			if equal(item, args[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return nil, errors.New("contains requires a string or a list")
+}
+
+func coalesce(args []interface{}) (interface{}, error) {
+	for _, arg := range args {
+		if arg != nil {
+			return arg, nil
+		}
+	}
+
+	return nil, nil
+}
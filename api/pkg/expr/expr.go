@@ -0,0 +1,286 @@
+package expr
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Expr is a parsed expression over named values, e.g. "(q1 + q2) / 2".
+// It supports numbers, strings, booleans, null, arithmetic, comparison and logical operators
+// and a fixed set of functions, there is no way to access anything outside of the given values.
+type Expr struct {
+	src  string
+	root node
+}
+
+type node interface{}
+
+type literalNode struct {
+	Value interface{}
+}
+
+type identNode struct {
+	Name string
+	Pos  int
+}
+
+type unaryNode struct {
+	Op string
+	X  node
+}
+
+type binaryNode struct {
+	Op    string
+	Left  node
+	Right node
+}
+
+type callNode struct {
+	Name string
+	Args []node
+}
+
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3,
+	"!=": 3,
+	"<":  4,
+	"<=": 4,
+	">":  4,
+	">=": 4,
+	"+":  5,
+	"-":  5,
+	"*":  6,
+	"/":  6,
+	"%":  6,
+}
+
+const unaryPrecedence = 7
+
+// maximum nesting of parentheses, unary operators and function calls
+const maxDepth = 64
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+// Parse parses an expression, unknown functions and wrong number of arguments are reported here
+func Parse(src string) (*Expr, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseExpr(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.Type != token_EOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.Value, t.Pos)
+	}
+
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns the source of the expression
+func (e *Expr) String() string {
+	return e.src
+}
+
+// UnknownIdentifierError is returned for a referenced value which is not known
+type UnknownIdentifierError struct {
+	Name string
+	// position of the identifier in runes
+	Pos int
+	// source of the expression from the identifier, e.g. "q-1 + 2" for q in "q-1 + 2"
+	Rest string
+}
+
+func (e *UnknownIdentifierError) Error() string {
+	return fmt.Sprintf("unknown identifier %s at position %d", e.Name, e.Pos)
+}
+
+// Identifiers returns unique names of the values referenced by the expression in order of appearance
+func (e *Expr) Identifiers() []string {
+	names := []string{}
+	for _, ident := range e.idents() {
+		if !slices.Contains(names, ident.Name) {
+			names = append(names, ident.Name)
+		}
+	}
+
+	return names
+}
+
+// CheckIdentifiers returns UnknownIdentifierError for the first referenced value which is not known
+func (e *Expr) CheckIdentifiers(known func(name string) bool) error {
+	for _, ident := range e.idents() {
+		if !known(ident.Name) {
+			return &UnknownIdentifierError{
+				Name: ident.Name,
+				Pos:  ident.Pos,
+				Rest: string([]rune(e.src)[ident.Pos:]),
+			}
+		}
+	}
+
+	return nil
+}
+
+// idents returns referenced values in order of appearance
+func (e *Expr) idents() []identNode {
+	idents := []identNode{}
+	var walk func(n node)
+	walk = func(n node) {
+		switch v := n.(type) {
+		case identNode:
+			idents = append(idents, v)
+		case unaryNode:
+			walk(v.X)
+		case binaryNode:
+			walk(v.Left)
+			walk(v.Right)
+		case callNode:
+			for _, arg := range v.Args {
+				walk(arg)
+			}
+		}
+	}
+	walk(e.root)
+
+	return idents
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.Type != token_EOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) parseExpr(minPrecedence int) (node, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, fmt.Errorf("expression is too deeply nested")
+	}
+
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		prec, ok := precedence[t.Value]
+		if t.Type != token_Operator || !ok || prec <= minPrecedence {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseExpr(prec)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{Op: t.Value, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.peek()
+	if t.Type == token_Operator && (t.Value == "-" || t.Value == "!") {
+		p.next()
+		x, err := p.parseExpr(unaryPrecedence)
+		if err != nil {
+			return nil, err
+		}
+
+		return unaryNode{Op: t.Value, X: x}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.Type {
+	case token_Number:
+		return literalNode{Value: t.Num}, nil
+	case token_String:
+		return literalNode{Value: t.Value}, nil
+	case token_LParen:
+		x, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.Type != token_RParen {
+			return nil, fmt.Errorf("expected ')' at position %d", closing.Pos)
+		}
+
+		return x, nil
+	case token_Ident:
+		switch t.Value {
+		case "true":
+			return literalNode{Value: true}, nil
+		case "false":
+			return literalNode{Value: false}, nil
+		case "null":
+			return literalNode{Value: nil}, nil
+		}
+
+		if p.peek().Type == token_LParen {
+			return p.parseCall(t)
+		}
+
+		return identNode{Name: t.Value, Pos: t.Pos}, nil
+	case token_QuotedIdent:
+		return identNode{Name: t.Value, Pos: t.Pos}, nil
+	case token_EOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", t.Value, t.Pos)
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	fn, ok := functions[name.Value]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s at position %d", name.Value, name.Pos)
+	}
+
+	p.next()
+	args := []node{}
+	if p.peek().Type != token_RParen {
+		for {
+			arg, err := p.parseExpr(0)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			if p.peek().Type != token_Comma {
+				break
+			}
+			p.next()
+		}
+	}
+	if closing := p.next(); closing.Type != token_RParen {
+		return nil, fmt.Errorf("expected ')' at position %d", closing.Pos)
+	}
+
+	if len(args) < fn.MinArgs || (fn.MaxArgs >= 0 && len(args) > fn.MaxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments for %s: %d", name.Value, len(args))
+	}
+
+	return callNode{Name: name.Value, Args: args}, nil
+}
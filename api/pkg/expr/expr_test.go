@@ -0,0 +1,194 @@
+package expr
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src   string
+		valid bool
+	}{
+		{"(q1 + q2) / 2", true},
+		{"round(avg(q1, q2, q3), 1)", true},
+		{"if(age >= 18, 'adult', 'minor')", true},
+		{"!agree && -score < 0", true},
+		{"q1 +", false},
+		{"(q1 + q2", false},
+		{"unknown(q1)", false},
+		{"round(q1, 1, 2)", false},
+		{"q1 $ q2", false},
+		{"'unterminated", false},
+		{"q1 q2", false},
+		{"`q-1` + `2nd`", true},
+		{"`q-1", false},
+		{"``", false},
+		{"`max`(q1)", false},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		if tt.valid && err != nil {
+			t.Errorf("Parse(%q): expected valid, got %v", tt.src, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("Parse(%q): expected error, got nil", tt.src)
+		}
+	}
+}
+
+func TestIdentifiers(t *testing.T) {
+	e, err := Parse("if(q1 > q2, q1, max(q3, q2)) + true")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"q1", "q2", "q3"}
+	if !reflect.DeepEqual(e.Identifiers(), expected) {
+		t.Errorf("expected %v, got %v", expected, e.Identifiers())
+	}
+}
+
+func TestCheckIdentifiers(t *testing.T) {
+	e, err := Parse("q1 + q-2 * 2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	known := map[string]bool{"q1": true}
+	err = e.CheckIdentifiers(func(name string) bool { return known[name] })
+
+	var unknown *UnknownIdentifierError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected UnknownIdentifierError, got %v", err)
+	}
+	if unknown.Name != "q" || unknown.Pos != 5 || unknown.Rest != "q-2 * 2" {
+		t.Errorf("expected q at position 5, got %+v", unknown)
+	}
+
+	known["q"] = true
+	if err := e.CheckIdentifiers(func(name string) bool { return known[name] }); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestTokenizeQuotedIdentifiers(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected []token
+	}{
+		{"q-1", []token{{Type: token_Ident, Value: "q"}, {Type: token_Operator, Value: "-"}, {Type: token_Number, Value: "1", Num: 1}}},
+		{"`q-1`", []token{{Type: token_QuotedIdent, Value: "q-1"}}},
+		{"`2nd-q` * 2", []token{{Type: token_QuotedIdent, Value: "2nd-q"}, {Type: token_Operator, Value: "*"}, {Type: token_Number, Value: "2", Num: 2}}},
+		{"`true`", []token{{Type: token_QuotedIdent, Value: "true"}}},
+	}
+
+	for _, tt := range tests {
+		tokens, err := tokenize(tt.src)
+		if err != nil {
+			t.Errorf("tokenize(%q): unexpected error: %v", tt.src, err)
+			continue
+		}
+
+		// positions and EOF are not compared
+		got := []token{}
+		for _, tok := range tokens[:len(tokens)-1] {
+			tok.Pos = 0
+			got = append(got, tok)
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("tokenize(%q): expected %v, got %v", tt.src, tt.expected, got)
+		}
+	}
+
+	e, err := Parse("`q-1` + `2nd`")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(e.Identifiers(), []string{"q-1", "2nd"}) {
+		t.Errorf("expected quoted identifiers, got %v", e.Identifiers())
+	}
+}
+
+func TestEval(t *testing.T) {
+	values := map[string]interface{}{
+		"q1":     int64(4),
+		"q2":     3.0,
+		"name":   "John",
+		"agree":  true,
+		"colors": []string{"red", "blue"},
+		"empty":  nil,
+	}
+
+	tests := []struct {
+		src      string
+		expected interface{}
+	}{
+		{"(q1 + q2) / 2", 3.5},
+		{"q1 + q2 * 2", 10.0},
+		{"(q1 + q2) * 2", 14.0},
+		{"q1 - q2 - 1", 0.0},
+		{"q1 % 3", 1.0},
+		{"-q1", -4.0},
+		{"q1 > q2 && agree", true},
+		{"q1 < q2 || !agree", false},
+		{"name == 'John'", true},
+		{"'Hello, ' + name", "Hello, John"},
+		{"empty + 1", nil},
+		{"empty == null", true},
+		{"avg(q1, q2, empty)", 3.5},
+		{"sum(empty)", nil},
+		{"min(q1, q2)", 3.0},
+		{"max(q1, q2)", 4.0},
+		{"round(10 / 3, 2)", 3.33},
+		{"abs(q2 - q1)", 1.0},
+		{"len(colors)", 2.0},
+		{"contains(colors, 'red')", true},
+		{"coalesce(empty, q2)", 3.0},
+		{"if(agree, 'yes', 1 / 0)", "yes"},
+	}
+
+	for _, tt := range tests {
+		e, err := Parse(tt.src)
+		if err != nil {
+			t.Fatalf("Parse(%q): unexpected error: %v", tt.src, err)
+		}
+
+		result, err := e.Eval(values)
+		if err != nil {
+			t.Errorf("Eval(%q): unexpected error: %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Eval(%q): expected %v, got %v", tt.src, tt.expected, result)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	values := map[string]interface{}{
+		"q1":   1.0,
+		"name": "John",
+	}
+
+	tests := []string{
+		"q1 / 0",
+		"q1 + name",
+		"q1 < name",
+		"unknown + 1",
+		"round(name)",
+	}
+
+	for _, src := range tests {
+		e, err := Parse(src)
+		if err != nil {
+			t.Fatalf("Parse(%q): unexpected error: %v", src, err)
+		}
+
+		if _, err := e.Eval(values); err == nil {
+			t.Errorf("Eval(%q): expected error, got nil", src)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenType int
+
+const (
+	token_EOF tokenType = iota
+	token_Number
+	token_String
+	token_Ident
+	// identifier in backticks, never a keyword or a function
+	token_QuotedIdent
+	token_Operator
+	token_LParen
+	token_RParen
+	token_Comma
+)
+
+type token struct {
+	Type  tokenType
+	Value string
+	Num   float64
+	Pos   int
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "+", "-", "*", "/", "%", "<", ">", "!"}
+
+func tokenize(src string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{Type: token_LParen, Value: "(", Pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{Type: token_RParen, Value: ")", Pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{Type: token_Comma, Value: ",", Pos: i})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			i++
+			var sb strings.Builder
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+			tokens = append(tokens, token{Type: token_String, Value: sb.String(), Pos: start})
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			value := string(runes[start:i])
+			num, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", value, start)
+			}
+			tokens = append(tokens, token{Type: token_Number, Value: value, Num: num, Pos: start})
+		case r == '`':
+			// quoted identifiers reference IDs which aren't valid identifiers, e.g. `q-1` or `2nd_question`
+			start := i
+			i++
+			for i < len(runes) && runes[i] != '`' {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated identifier at position %d", start)
+			}
+			if i == start+1 {
+				return nil, fmt.Errorf("empty identifier at position %d", start)
+			}
+			i++
+			tokens = append(tokens, token{Type: token_QuotedIdent, Value: string(runes[start+1 : i-1]), Pos: start})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{Type: token_Ident, Value: string(runes[start:i]), Pos: start})
+		default:
+			found := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{Type: token_Operator, Value: op, Pos: i})
+					i += len([]rune(op))
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+		}
+	}
+
+	return append(tokens, token{Type: token_EOF, Pos: len(runes)}), nil
+}
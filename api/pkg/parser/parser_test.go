@@ -46,3 +46,40 @@ func TestReadSurveys(t *testing.T) {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+func TestReadSurveyTranslations(t *testing.T) {
+	svc := services.Services{
+		Logger: slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+	p := NewParser(svc)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"metadata.yaml":     "title: Survey\n",
+		"security.yaml":     "duplicateProtection: cookie\n",
+		"questions.yaml":    "questions:\n  - id: name\n    type: short-text\n    label: Name\n",
+		"metadata.de.yaml":  "title: Umfrage\n",
+		"questions.de.yaml": "questions:\n  - id: name\n    label: Vorname\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(dir+"/"+name, []byte(content), 0644); err != nil {
+			t.Fatalf("unable to write file: %v", err)
+		}
+	}
+
+	surveyConfig, err := p.ReadSurvey(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.Equal(t, "en", surveyConfig.DefaultLocale)
+	assert.Equal(t, []string{"en", "de"}, surveyConfig.Locales())
+	assert.Equal(t, "Umfrage", surveyConfig.Translations[0].Title)
+	assert.Equal(t, "Vorname", surveyConfig.Translations[0].Questions[0].Label)
+
+	if err := os.Remove(dir + "/questions.de.yaml"); err != nil {
+		t.Fatalf("unable to remove file: %v", err)
+	}
+	_, err = p.ReadSurvey(dir)
+	assert.EqualError(t, err, "required file 'questions.de.yaml' not found")
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/plutov/formulosity/api/pkg/types"
@@ -26,6 +28,9 @@ const (
 	surveyFileType_Theme     surveyFileType = "theme.css"
 )
 
+// localized files, e.g. metadata.de.yaml or questions.de.yaml
+var translationFileRegexp = regexp.MustCompile(`^(metadata|questions)\.([a-z]{2,3}(?:-[a-z0-9]{2,8})*)\.yaml$`)
+
 var surveyFiles = []surveyFile{
 	{
 		Name:     surveyFileType_Metadata,
@@ -134,6 +139,11 @@ func (p *Parser) ReadSurvey(path string) (*types.SurveyConfig, error) {
 		}
 	}
 
+	surveyConfig.Translations, err = p.readTranslations(path, items)
+	if err != nil {
+		return nil, err
+	}
+
 	surveyConfig.GenerateHash()
 	if err := surveyConfig.Validate(); err != nil {
 		return nil, err
@@ -143,3 +153,56 @@ func (p *Parser) ReadSurvey(path string) (*types.SurveyConfig, error) {
 
 	return surveyConfig, nil
 }
+
+// readTranslations reads per-locale override files, questions.<locale>.yaml is required for every locale
+func (p *Parser) readTranslations(path string, items []os.DirEntry) ([]types.Translation, error) {
+	var translations []types.Translation
+	questionsFound := make(map[string]bool)
+	for _, item := range items {
+		if item.IsDir() {
+			continue
+		}
+
+		fileName := strings.ToLower(item.Name())
+		matches := translationFileRegexp.FindStringSubmatch(fileName)
+		if matches == nil {
+			continue
+		}
+
+		locale := matches[2]
+		index := slices.IndexFunc(translations, func(t types.Translation) bool {
+			return t.Locale == locale
+		})
+		if index == -1 {
+			translations = append(translations, types.Translation{
+				Locale: locale,
+			})
+			index = len(translations) - 1
+		}
+		if matches[1] == "questions" {
+			questionsFound[locale] = true
+		}
+
+		file, err := os.ReadFile(path + item.Name())
+		if err != nil {
+			p.svc.Logger.With("file", fileName).Error("unable to read survey file", "err", err)
+			return nil, fmt.Errorf("unable to read survey file '%s'", fileName)
+		}
+
+		if err := yaml.Unmarshal(file, &translations[index]); err != nil {
+			return nil, fmt.Errorf("unable to parse file '%s': %w", fileName, err)
+		}
+	}
+
+	for _, t := range translations {
+		if !questionsFound[t.Locale] {
+			return nil, fmt.Errorf("required file 'questions.%s.yaml' not found", t.Locale)
+		}
+	}
+
+	slices.SortFunc(translations, func(a, b types.Translation) int {
+		return strings.Compare(a.Locale, b.Locale)
+	})
+
+	return translations, nil
+}
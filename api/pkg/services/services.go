@@ -12,12 +12,15 @@ type Services struct {
 	Storage     storage.Interface
 	FileStorage storage.FileInterface
 	Logger      *slog.Logger
+	// salt of hashed IP addresses of respondents, required by surveys with ip duplicate protection
+	IPHashSalt string
 }
 
 func InitServices() (Services, error) {
 	svc := Services{
-		Logger: slog.New(slog.NewJSONHandler(os.Stdout, nil)),
-		Storage: new(storage.Postgres),
+		Logger:     slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+		Storage:    new(storage.Postgres),
+		IPHashSalt: os.Getenv("IP_HASH_SALT"),
 	}
 
 	if err := svc.Storage.Init(); err != nil {
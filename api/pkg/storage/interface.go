@@ -1,19 +1,33 @@
 package storage
 
-import "github.com/plutov/formulosity/api/pkg/types"
+import (
+	"time"
+
+	"github.com/plutov/formulosity/api/pkg/types"
+)
 
 type Interface interface {
 	Init() error
 	Ping() error
 	Close() error
 	Migrate() error
+	WithTx(fn func(tx Interface) error) error
 	CreateSurvey(survey *types.Survey) error
 	UpdateSurvey(survey *types.Survey) error
+	CreateSurveyDeliveryTransition(surveyID int64, status types.SurveyDeliveryStatus, scheduledAt time.Time) (bool, error)
 	GetSurveys() ([]*types.Survey, error)
 	GetSurveyByField(field string, value interface{}) (*types.Survey, error)
 	CreateSurveySession(session *types.SurveySession) error
+	CreateSurveyInvitations(surveyUUID string, invitations []types.Invitation) ([]types.Invitation, error)
+	GetSurveyInvitations(surveyUUID string) ([]types.Invitation, error)
 	UpdateSurveySessionStatus(sessionUUID string, newStatus types.SurveySessionStatus) error
-	GetSurveySessionByIPAddress(surveyUUID string, ipAddr string) (*types.SurveySession, error)
+	ScreenOutSurveySession(sessionUUID string, quotaID string) error
+	CompleteSurveySession(surveyUUID string, sessionUUID string, quotas []types.Quota) (*types.Quota, error)
+	GetSurveyQuotaCount(surveyUUID string, quota types.Quota) (int, error)
+	UpdateSurveySessionDisplayOrder(sessionUUID string, order *types.SessionOrder) error
+	UpdateSurveySessionScore(sessionUUID string, score int, maxScore int) error
+	UpdateSurveySessionVariables(sessionUUID string, variables map[string]interface{}) error
+	GetSurveySessionsCountByIPAddress(surveyUUID string, ipAddr string) (int, error)
 	GetSurveySession(surveyUUID string, sessionUUID string) (*types.SurveySession, error)
 	DeleteSurveySession(sessionUUID string) error
 	UpsertSurveyQuestions(survey *types.Survey) error
@@ -21,7 +35,16 @@ type Interface interface {
 	GetSurveySessionsWithAnswers(surveyUUID string, filter *types.SurveySessionsFilter) ([]types.SurveySession, int, error)
 	GetSurveySessionAnswers(sessionUUID string) ([]types.QuestionAnswer, error)
 	UpsertSurveyQuestionAnswer(sessionUUID string, questionUUID string, answer types.Answer) error
-	StoreWebhookResponse(sessionId int, responseStatus int, response string) error
+	UpsertSurveyQuestionAnswers(sessionUUID string, answers []types.QuestionAnswer) error
+	SkipSurveyQuestion(sessionUUID string, questionUUID string) error
+	GetSurveyAnswerValueCounts(surveyUUID string, questionIDs []string) ([]types.AnswerValueCount, error)
+	StoreWebhookResponse(delivery *types.WebhookDelivery, attempt types.WebhookAttempt) error
+	CreateWebhookDelivery(delivery *types.WebhookDelivery) error
+	ClaimWebhookDeliveries(now time.Time, leaseUntil time.Time, limit int) ([]types.WebhookDelivery, error)
+	UpdateWebhookDelivery(delivery *types.WebhookDelivery) error
+	GetWebhookDeliveries(surveyUUID string, filter *types.WebhookDeliveriesFilter) ([]types.WebhookDelivery, error)
+	GetWebhookDelivery(surveyUUID string, deliveryUUID string) (*types.WebhookDelivery, error)
+	RedeliverWebhookDelivery(delivery *types.WebhookDelivery) (bool, error)
 }
 
 type FileInterface interface {
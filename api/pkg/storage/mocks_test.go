@@ -5,6 +5,8 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/plutov/formulosity/api/pkg/types"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -36,6 +38,80 @@ func (_m *MockInterface) EXPECT() *MockInterface_Expecter {
 	return &MockInterface_Expecter{mock: &_m.Mock}
 }
 
+// ClaimWebhookDeliveries provides a mock function for the type MockInterface
+func (_mock *MockInterface) ClaimWebhookDeliveries(now time.Time, leaseUntil time.Time, limit int) ([]types.WebhookDelivery, error) {
+	ret := _mock.Called(now, leaseUntil, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimWebhookDeliveries")
+	}
+
+	var r0 []types.WebhookDelivery
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(time.Time, time.Time, int) ([]types.WebhookDelivery, error)); ok {
+		return returnFunc(now, leaseUntil, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(time.Time, time.Time, int) []types.WebhookDelivery); ok {
+		r0 = returnFunc(now, leaseUntil, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.WebhookDelivery)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(time.Time, time.Time, int) error); ok {
+		r1 = returnFunc(now, leaseUntil, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInterface_ClaimWebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimWebhookDeliveries'
+type MockInterface_ClaimWebhookDeliveries_Call struct {
+	*mock.Call
+}
+
+// ClaimWebhookDeliveries is a helper method to define mock.On call
+//   - now time.Time
+//   - leaseUntil time.Time
+//   - limit int
+func (_e *MockInterface_Expecter) ClaimWebhookDeliveries(now interface{}, leaseUntil interface{}, limit interface{}) *MockInterface_ClaimWebhookDeliveries_Call {
+	return &MockInterface_ClaimWebhookDeliveries_Call{Call: _e.mock.On("ClaimWebhookDeliveries", now, leaseUntil, limit)}
+}
+
+func (_c *MockInterface_ClaimWebhookDeliveries_Call) Run(run func(now time.Time, leaseUntil time.Time, limit int)) *MockInterface_ClaimWebhookDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 time.Time
+		if args[0] != nil {
+			arg0 = args[0].(time.Time)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_ClaimWebhookDeliveries_Call) Return(webhookDeliverys []types.WebhookDelivery, err error) *MockInterface_ClaimWebhookDeliveries_Call {
+	_c.Call.Return(webhookDeliverys, err)
+	return _c
+}
+
+func (_c *MockInterface_ClaimWebhookDeliveries_Call) RunAndReturn(run func(now time.Time, leaseUntil time.Time, limit int) ([]types.WebhookDelivery, error)) *MockInterface_ClaimWebhookDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Close provides a mock function for the type MockInterface
 func (_mock *MockInterface) Close() error {
 	ret := _mock.Called()
@@ -80,6 +156,80 @@ func (_c *MockInterface_Close_Call) RunAndReturn(run func() error) *MockInterfac
 	return _c
 }
 
+// CompleteSurveySession provides a mock function for the type MockInterface
+func (_mock *MockInterface) CompleteSurveySession(surveyUUID string, sessionUUID string, quotas []types.Quota) (*types.Quota, error) {
+	ret := _mock.Called(surveyUUID, sessionUUID, quotas)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteSurveySession")
+	}
+
+	var r0 *types.Quota
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, []types.Quota) (*types.Quota, error)); ok {
+		return returnFunc(surveyUUID, sessionUUID, quotas)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, []types.Quota) *types.Quota); ok {
+		r0 = returnFunc(surveyUUID, sessionUUID, quotas)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Quota)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, []types.Quota) error); ok {
+		r1 = returnFunc(surveyUUID, sessionUUID, quotas)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInterface_CompleteSurveySession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteSurveySession'
+type MockInterface_CompleteSurveySession_Call struct {
+	*mock.Call
+}
+
+// CompleteSurveySession is a helper method to define mock.On call
+//   - surveyUUID string
+//   - sessionUUID string
+//   - quotas []types.Quota
+func (_e *MockInterface_Expecter) CompleteSurveySession(surveyUUID interface{}, sessionUUID interface{}, quotas interface{}) *MockInterface_CompleteSurveySession_Call {
+	return &MockInterface_CompleteSurveySession_Call{Call: _e.mock.On("CompleteSurveySession", surveyUUID, sessionUUID, quotas)}
+}
+
+func (_c *MockInterface_CompleteSurveySession_Call) Run(run func(surveyUUID string, sessionUUID string, quotas []types.Quota)) *MockInterface_CompleteSurveySession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []types.Quota
+		if args[2] != nil {
+			arg2 = args[2].([]types.Quota)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_CompleteSurveySession_Call) Return(quota *types.Quota, err error) *MockInterface_CompleteSurveySession_Call {
+	_c.Call.Return(quota, err)
+	return _c
+}
+
+func (_c *MockInterface_CompleteSurveySession_Call) RunAndReturn(run func(surveyUUID string, sessionUUID string, quotas []types.Quota) (*types.Quota, error)) *MockInterface_CompleteSurveySession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateSurvey provides a mock function for the type MockInterface
 func (_mock *MockInterface) CreateSurvey(survey *types.Survey) error {
 	ret := _mock.Called(survey)
@@ -131,6 +281,146 @@ func (_c *MockInterface_CreateSurvey_Call) RunAndReturn(run func(survey *types.S
 	return _c
 }
 
+// CreateSurveyDeliveryTransition provides a mock function for the type MockInterface
+func (_mock *MockInterface) CreateSurveyDeliveryTransition(surveyID int64, status types.SurveyDeliveryStatus, scheduledAt time.Time) (bool, error) {
+	ret := _mock.Called(surveyID, status, scheduledAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSurveyDeliveryTransition")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(int64, types.SurveyDeliveryStatus, time.Time) (bool, error)); ok {
+		return returnFunc(surveyID, status, scheduledAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int64, types.SurveyDeliveryStatus, time.Time) bool); ok {
+		r0 = returnFunc(surveyID, status, scheduledAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(int64, types.SurveyDeliveryStatus, time.Time) error); ok {
+		r1 = returnFunc(surveyID, status, scheduledAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInterface_CreateSurveyDeliveryTransition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSurveyDeliveryTransition'
+type MockInterface_CreateSurveyDeliveryTransition_Call struct {
+	*mock.Call
+}
+
+// CreateSurveyDeliveryTransition is a helper method to define mock.On call
+//   - surveyID int64
+//   - status types.SurveyDeliveryStatus
+//   - scheduledAt time.Time
+func (_e *MockInterface_Expecter) CreateSurveyDeliveryTransition(surveyID interface{}, status interface{}, scheduledAt interface{}) *MockInterface_CreateSurveyDeliveryTransition_Call {
+	return &MockInterface_CreateSurveyDeliveryTransition_Call{Call: _e.mock.On("CreateSurveyDeliveryTransition", surveyID, status, scheduledAt)}
+}
+
+func (_c *MockInterface_CreateSurveyDeliveryTransition_Call) Run(run func(surveyID int64, status types.SurveyDeliveryStatus, scheduledAt time.Time)) *MockInterface_CreateSurveyDeliveryTransition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int64
+		if args[0] != nil {
+			arg0 = args[0].(int64)
+		}
+		var arg1 types.SurveyDeliveryStatus
+		if args[1] != nil {
+			arg1 = args[1].(types.SurveyDeliveryStatus)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_CreateSurveyDeliveryTransition_Call) Return(b bool, err error) *MockInterface_CreateSurveyDeliveryTransition_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockInterface_CreateSurveyDeliveryTransition_Call) RunAndReturn(run func(surveyID int64, status types.SurveyDeliveryStatus, scheduledAt time.Time) (bool, error)) *MockInterface_CreateSurveyDeliveryTransition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateSurveyInvitations provides a mock function for the type MockInterface
+func (_mock *MockInterface) CreateSurveyInvitations(surveyUUID string, invitations []types.Invitation) ([]types.Invitation, error) {
+	ret := _mock.Called(surveyUUID, invitations)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSurveyInvitations")
+	}
+
+	var r0 []types.Invitation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, []types.Invitation) ([]types.Invitation, error)); ok {
+		return returnFunc(surveyUUID, invitations)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, []types.Invitation) []types.Invitation); ok {
+		r0 = returnFunc(surveyUUID, invitations)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Invitation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, []types.Invitation) error); ok {
+		r1 = returnFunc(surveyUUID, invitations)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInterface_CreateSurveyInvitations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSurveyInvitations'
+type MockInterface_CreateSurveyInvitations_Call struct {
+	*mock.Call
+}
+
+// CreateSurveyInvitations is a helper method to define mock.On call
+//   - surveyUUID string
+//   - invitations []types.Invitation
+func (_e *MockInterface_Expecter) CreateSurveyInvitations(surveyUUID interface{}, invitations interface{}) *MockInterface_CreateSurveyInvitations_Call {
+	return &MockInterface_CreateSurveyInvitations_Call{Call: _e.mock.On("CreateSurveyInvitations", surveyUUID, invitations)}
+}
+
+func (_c *MockInterface_CreateSurveyInvitations_Call) Run(run func(surveyUUID string, invitations []types.Invitation)) *MockInterface_CreateSurveyInvitations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []types.Invitation
+		if args[1] != nil {
+			arg1 = args[1].([]types.Invitation)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_CreateSurveyInvitations_Call) Return(invitations []types.Invitation, err error) *MockInterface_CreateSurveyInvitations_Call {
+	_c.Call.Return(invitations, err)
+	return _c
+}
+
+func (_c *MockInterface_CreateSurveyInvitations_Call) RunAndReturn(run func(surveyUUID string, invitations []types.Invitation) ([]types.Invitation, error)) *MockInterface_CreateSurveyInvitations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateSurveySession provides a mock function for the type MockInterface
 func (_mock *MockInterface) CreateSurveySession(session *types.SurveySession) error {
 	ret := _mock.Called(session)
@@ -182,6 +472,57 @@ func (_c *MockInterface_CreateSurveySession_Call) RunAndReturn(run func(session
 	return _c
 }
 
+// CreateWebhookDelivery provides a mock function for the type MockInterface
+func (_mock *MockInterface) CreateWebhookDelivery(delivery *types.WebhookDelivery) error {
+	ret := _mock.Called(delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhookDelivery")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*types.WebhookDelivery) error); ok {
+		r0 = returnFunc(delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInterface_CreateWebhookDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWebhookDelivery'
+type MockInterface_CreateWebhookDelivery_Call struct {
+	*mock.Call
+}
+
+// CreateWebhookDelivery is a helper method to define mock.On call
+//   - delivery *types.WebhookDelivery
+func (_e *MockInterface_Expecter) CreateWebhookDelivery(delivery interface{}) *MockInterface_CreateWebhookDelivery_Call {
+	return &MockInterface_CreateWebhookDelivery_Call{Call: _e.mock.On("CreateWebhookDelivery", delivery)}
+}
+
+func (_c *MockInterface_CreateWebhookDelivery_Call) Run(run func(delivery *types.WebhookDelivery)) *MockInterface_CreateWebhookDelivery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *types.WebhookDelivery
+		if args[0] != nil {
+			arg0 = args[0].(*types.WebhookDelivery)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_CreateWebhookDelivery_Call) Return(err error) *MockInterface_CreateWebhookDelivery_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInterface_CreateWebhookDelivery_Call) RunAndReturn(run func(delivery *types.WebhookDelivery) error) *MockInterface_CreateWebhookDelivery_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteSurveySession provides a mock function for the type MockInterface
 func (_mock *MockInterface) DeleteSurveySession(sessionUUID string) error {
 	ret := _mock.Called(sessionUUID)
@@ -233,55 +574,55 @@ func (_c *MockInterface_DeleteSurveySession_Call) RunAndReturn(run func(sessionU
 	return _c
 }
 
-// GetSurveyByField provides a mock function for the type MockInterface
-func (_mock *MockInterface) GetSurveyByField(field string, value interface{}) (*types.Survey, error) {
-	ret := _mock.Called(field, value)
+// GetSurveyAnswerValueCounts provides a mock function for the type MockInterface
+func (_mock *MockInterface) GetSurveyAnswerValueCounts(surveyUUID string, questionIDs []string) ([]types.AnswerValueCount, error) {
+	ret := _mock.Called(surveyUUID, questionIDs)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSurveyByField")
+		panic("no return value specified for GetSurveyAnswerValueCounts")
 	}
 
-	var r0 *types.Survey
+	var r0 []types.AnswerValueCount
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string, interface{}) (*types.Survey, error)); ok {
-		return returnFunc(field, value)
+	if returnFunc, ok := ret.Get(0).(func(string, []string) ([]types.AnswerValueCount, error)); ok {
+		return returnFunc(surveyUUID, questionIDs)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string, interface{}) *types.Survey); ok {
-		r0 = returnFunc(field, value)
+	if returnFunc, ok := ret.Get(0).(func(string, []string) []types.AnswerValueCount); ok {
+		r0 = returnFunc(surveyUUID, questionIDs)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*types.Survey)
+			r0 = ret.Get(0).([]types.AnswerValueCount)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(string, interface{}) error); ok {
-		r1 = returnFunc(field, value)
+	if returnFunc, ok := ret.Get(1).(func(string, []string) error); ok {
+		r1 = returnFunc(surveyUUID, questionIDs)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockInterface_GetSurveyByField_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSurveyByField'
-type MockInterface_GetSurveyByField_Call struct {
+// MockInterface_GetSurveyAnswerValueCounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSurveyAnswerValueCounts'
+type MockInterface_GetSurveyAnswerValueCounts_Call struct {
 	*mock.Call
 }
 
-// GetSurveyByField is a helper method to define mock.On call
-//   - field string
-//   - value interface{}
-func (_e *MockInterface_Expecter) GetSurveyByField(field interface{}, value interface{}) *MockInterface_GetSurveyByField_Call {
-	return &MockInterface_GetSurveyByField_Call{Call: _e.mock.On("GetSurveyByField", field, value)}
+// GetSurveyAnswerValueCounts is a helper method to define mock.On call
+//   - surveyUUID string
+//   - questionIDs []string
+func (_e *MockInterface_Expecter) GetSurveyAnswerValueCounts(surveyUUID interface{}, questionIDs interface{}) *MockInterface_GetSurveyAnswerValueCounts_Call {
+	return &MockInterface_GetSurveyAnswerValueCounts_Call{Call: _e.mock.On("GetSurveyAnswerValueCounts", surveyUUID, questionIDs)}
 }
 
-func (_c *MockInterface_GetSurveyByField_Call) Run(run func(field string, value interface{})) *MockInterface_GetSurveyByField_Call {
+func (_c *MockInterface_GetSurveyAnswerValueCounts_Call) Run(run func(surveyUUID string, questionIDs []string)) *MockInterface_GetSurveyAnswerValueCounts_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
 			arg0 = args[0].(string)
 		}
-		var arg1 interface{}
+		var arg1 []string
 		if args[1] != nil {
-			arg1 = args[1].(interface{})
+			arg1 = args[1].([]string)
 		}
 		run(
 			arg0,
@@ -291,25 +632,155 @@ func (_c *MockInterface_GetSurveyByField_Call) Run(run func(field string, value
 	return _c
 }
 
-func (_c *MockInterface_GetSurveyByField_Call) Return(survey *types.Survey, err error) *MockInterface_GetSurveyByField_Call {
-	_c.Call.Return(survey, err)
+func (_c *MockInterface_GetSurveyAnswerValueCounts_Call) Return(answerValueCounts []types.AnswerValueCount, err error) *MockInterface_GetSurveyAnswerValueCounts_Call {
+	_c.Call.Return(answerValueCounts, err)
 	return _c
 }
 
-func (_c *MockInterface_GetSurveyByField_Call) RunAndReturn(run func(field string, value interface{}) (*types.Survey, error)) *MockInterface_GetSurveyByField_Call {
+func (_c *MockInterface_GetSurveyAnswerValueCounts_Call) RunAndReturn(run func(surveyUUID string, questionIDs []string) ([]types.AnswerValueCount, error)) *MockInterface_GetSurveyAnswerValueCounts_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetSurveyQuestions provides a mock function for the type MockInterface
-func (_mock *MockInterface) GetSurveyQuestions(surveyID int64) ([]types.Question, error) {
-	ret := _mock.Called(surveyID)
+// GetSurveyByField provides a mock function for the type MockInterface
+func (_mock *MockInterface) GetSurveyByField(field string, value interface{}) (*types.Survey, error) {
+	ret := _mock.Called(field, value)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSurveyQuestions")
+		panic("no return value specified for GetSurveyByField")
 	}
 
-	var r0 []types.Question
+	var r0 *types.Survey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, interface{}) (*types.Survey, error)); ok {
+		return returnFunc(field, value)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, interface{}) *types.Survey); ok {
+		r0 = returnFunc(field, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Survey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, interface{}) error); ok {
+		r1 = returnFunc(field, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInterface_GetSurveyByField_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSurveyByField'
+type MockInterface_GetSurveyByField_Call struct {
+	*mock.Call
+}
+
+// GetSurveyByField is a helper method to define mock.On call
+//   - field string
+//   - value interface{}
+func (_e *MockInterface_Expecter) GetSurveyByField(field interface{}, value interface{}) *MockInterface_GetSurveyByField_Call {
+	return &MockInterface_GetSurveyByField_Call{Call: _e.mock.On("GetSurveyByField", field, value)}
+}
+
+func (_c *MockInterface_GetSurveyByField_Call) Run(run func(field string, value interface{})) *MockInterface_GetSurveyByField_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 interface{}
+		if args[1] != nil {
+			arg1 = args[1].(interface{})
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_GetSurveyByField_Call) Return(survey *types.Survey, err error) *MockInterface_GetSurveyByField_Call {
+	_c.Call.Return(survey, err)
+	return _c
+}
+
+func (_c *MockInterface_GetSurveyByField_Call) RunAndReturn(run func(field string, value interface{}) (*types.Survey, error)) *MockInterface_GetSurveyByField_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSurveyInvitations provides a mock function for the type MockInterface
+func (_mock *MockInterface) GetSurveyInvitations(surveyUUID string) ([]types.Invitation, error) {
+	ret := _mock.Called(surveyUUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSurveyInvitations")
+	}
+
+	var r0 []types.Invitation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) ([]types.Invitation, error)); ok {
+		return returnFunc(surveyUUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) []types.Invitation); ok {
+		r0 = returnFunc(surveyUUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Invitation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(surveyUUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInterface_GetSurveyInvitations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSurveyInvitations'
+type MockInterface_GetSurveyInvitations_Call struct {
+	*mock.Call
+}
+
+// GetSurveyInvitations is a helper method to define mock.On call
+//   - surveyUUID string
+func (_e *MockInterface_Expecter) GetSurveyInvitations(surveyUUID interface{}) *MockInterface_GetSurveyInvitations_Call {
+	return &MockInterface_GetSurveyInvitations_Call{Call: _e.mock.On("GetSurveyInvitations", surveyUUID)}
+}
+
+func (_c *MockInterface_GetSurveyInvitations_Call) Run(run func(surveyUUID string)) *MockInterface_GetSurveyInvitations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_GetSurveyInvitations_Call) Return(invitations []types.Invitation, err error) *MockInterface_GetSurveyInvitations_Call {
+	_c.Call.Return(invitations, err)
+	return _c
+}
+
+func (_c *MockInterface_GetSurveyInvitations_Call) RunAndReturn(run func(surveyUUID string) ([]types.Invitation, error)) *MockInterface_GetSurveyInvitations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSurveyQuestions provides a mock function for the type MockInterface
+func (_mock *MockInterface) GetSurveyQuestions(surveyID int64) ([]types.Question, error) {
+	ret := _mock.Called(surveyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSurveyQuestions")
+	}
+
+	var r0 []types.Question
 	var r1 error
 	if returnFunc, ok := ret.Get(0).(func(int64) ([]types.Question, error)); ok {
 		return returnFunc(surveyID)
@@ -363,6 +834,72 @@ func (_c *MockInterface_GetSurveyQuestions_Call) RunAndReturn(run func(surveyID
 	return _c
 }
 
+// GetSurveyQuotaCount provides a mock function for the type MockInterface
+func (_mock *MockInterface) GetSurveyQuotaCount(surveyUUID string, quota types.Quota) (int, error) {
+	ret := _mock.Called(surveyUUID, quota)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSurveyQuotaCount")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, types.Quota) (int, error)); ok {
+		return returnFunc(surveyUUID, quota)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, types.Quota) int); ok {
+		r0 = returnFunc(surveyUUID, quota)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, types.Quota) error); ok {
+		r1 = returnFunc(surveyUUID, quota)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInterface_GetSurveyQuotaCount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSurveyQuotaCount'
+type MockInterface_GetSurveyQuotaCount_Call struct {
+	*mock.Call
+}
+
+// GetSurveyQuotaCount is a helper method to define mock.On call
+//   - surveyUUID string
+//   - quota types.Quota
+func (_e *MockInterface_Expecter) GetSurveyQuotaCount(surveyUUID interface{}, quota interface{}) *MockInterface_GetSurveyQuotaCount_Call {
+	return &MockInterface_GetSurveyQuotaCount_Call{Call: _e.mock.On("GetSurveyQuotaCount", surveyUUID, quota)}
+}
+
+func (_c *MockInterface_GetSurveyQuotaCount_Call) Run(run func(surveyUUID string, quota types.Quota)) *MockInterface_GetSurveyQuotaCount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 types.Quota
+		if args[1] != nil {
+			arg1 = args[1].(types.Quota)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_GetSurveyQuotaCount_Call) Return(n int, err error) *MockInterface_GetSurveyQuotaCount_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockInterface_GetSurveyQuotaCount_Call) RunAndReturn(run func(surveyUUID string, quota types.Quota) (int, error)) *MockInterface_GetSurveyQuotaCount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetSurveySession provides a mock function for the type MockInterface
 func (_mock *MockInterface) GetSurveySession(surveyUUID string, sessionUUID string) (*types.SurveySession, error) {
 	ret := _mock.Called(surveyUUID, sessionUUID)
@@ -493,25 +1030,23 @@ func (_c *MockInterface_GetSurveySessionAnswers_Call) RunAndReturn(run func(sess
 	return _c
 }
 
-// GetSurveySessionByIPAddress provides a mock function for the type MockInterface
-func (_mock *MockInterface) GetSurveySessionByIPAddress(surveyUUID string, ipAddr string) (*types.SurveySession, error) {
+// GetSurveySessionsCountByIPAddress provides a mock function for the type MockInterface
+func (_mock *MockInterface) GetSurveySessionsCountByIPAddress(surveyUUID string, ipAddr string) (int, error) {
 	ret := _mock.Called(surveyUUID, ipAddr)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSurveySessionByIPAddress")
+		panic("no return value specified for GetSurveySessionsCountByIPAddress")
 	}
 
-	var r0 *types.SurveySession
+	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string, string) (*types.SurveySession, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(string, string) (int, error)); ok {
 		return returnFunc(surveyUUID, ipAddr)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string, string) *types.SurveySession); ok {
+	if returnFunc, ok := ret.Get(0).(func(string, string) int); ok {
 		r0 = returnFunc(surveyUUID, ipAddr)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*types.SurveySession)
-		}
+		r0 = ret.Get(0).(int)
 	}
 	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
 		r1 = returnFunc(surveyUUID, ipAddr)
@@ -521,19 +1056,19 @@ func (_mock *MockInterface) GetSurveySessionByIPAddress(surveyUUID string, ipAdd
 	return r0, r1
 }
 
-// MockInterface_GetSurveySessionByIPAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSurveySessionByIPAddress'
-type MockInterface_GetSurveySessionByIPAddress_Call struct {
+// MockInterface_GetSurveySessionsCountByIPAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSurveySessionsCountByIPAddress'
+type MockInterface_GetSurveySessionsCountByIPAddress_Call struct {
 	*mock.Call
 }
 
-// GetSurveySessionByIPAddress is a helper method to define mock.On call
+// GetSurveySessionsCountByIPAddress is a helper method to define mock.On call
 //   - surveyUUID string
 //   - ipAddr string
-func (_e *MockInterface_Expecter) GetSurveySessionByIPAddress(surveyUUID interface{}, ipAddr interface{}) *MockInterface_GetSurveySessionByIPAddress_Call {
-	return &MockInterface_GetSurveySessionByIPAddress_Call{Call: _e.mock.On("GetSurveySessionByIPAddress", surveyUUID, ipAddr)}
+func (_e *MockInterface_Expecter) GetSurveySessionsCountByIPAddress(surveyUUID interface{}, ipAddr interface{}) *MockInterface_GetSurveySessionsCountByIPAddress_Call {
+	return &MockInterface_GetSurveySessionsCountByIPAddress_Call{Call: _e.mock.On("GetSurveySessionsCountByIPAddress", surveyUUID, ipAddr)}
 }
 
-func (_c *MockInterface_GetSurveySessionByIPAddress_Call) Run(run func(surveyUUID string, ipAddr string)) *MockInterface_GetSurveySessionByIPAddress_Call {
+func (_c *MockInterface_GetSurveySessionsCountByIPAddress_Call) Run(run func(surveyUUID string, ipAddr string)) *MockInterface_GetSurveySessionsCountByIPAddress_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
@@ -551,12 +1086,12 @@ func (_c *MockInterface_GetSurveySessionByIPAddress_Call) Run(run func(surveyUUI
 	return _c
 }
 
-func (_c *MockInterface_GetSurveySessionByIPAddress_Call) Return(surveySession *types.SurveySession, err error) *MockInterface_GetSurveySessionByIPAddress_Call {
-	_c.Call.Return(surveySession, err)
+func (_c *MockInterface_GetSurveySessionsCountByIPAddress_Call) Return(n int, err error) *MockInterface_GetSurveySessionsCountByIPAddress_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockInterface_GetSurveySessionByIPAddress_Call) RunAndReturn(run func(surveyUUID string, ipAddr string) (*types.SurveySession, error)) *MockInterface_GetSurveySessionByIPAddress_Call {
+func (_c *MockInterface_GetSurveySessionsCountByIPAddress_Call) RunAndReturn(run func(surveyUUID string, ipAddr string) (int, error)) *MockInterface_GetSurveySessionsCountByIPAddress_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -680,315 +1215,847 @@ func (_c *MockInterface_GetSurveys_Call) Run(run func()) *MockInterface_GetSurve
 	return _c
 }
 
-func (_c *MockInterface_GetSurveys_Call) Return(surveys []*types.Survey, err error) *MockInterface_GetSurveys_Call {
-	_c.Call.Return(surveys, err)
+func (_c *MockInterface_GetSurveys_Call) Return(surveys []*types.Survey, err error) *MockInterface_GetSurveys_Call {
+	_c.Call.Return(surveys, err)
+	return _c
+}
+
+func (_c *MockInterface_GetSurveys_Call) RunAndReturn(run func() ([]*types.Survey, error)) *MockInterface_GetSurveys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhookDeliveries provides a mock function for the type MockInterface
+func (_mock *MockInterface) GetWebhookDeliveries(surveyUUID string, filter *types.WebhookDeliveriesFilter) ([]types.WebhookDelivery, error) {
+	ret := _mock.Called(surveyUUID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDeliveries")
+	}
+
+	var r0 []types.WebhookDelivery
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, *types.WebhookDeliveriesFilter) ([]types.WebhookDelivery, error)); ok {
+		return returnFunc(surveyUUID, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, *types.WebhookDeliveriesFilter) []types.WebhookDelivery); ok {
+		r0 = returnFunc(surveyUUID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.WebhookDelivery)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, *types.WebhookDeliveriesFilter) error); ok {
+		r1 = returnFunc(surveyUUID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInterface_GetWebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhookDeliveries'
+type MockInterface_GetWebhookDeliveries_Call struct {
+	*mock.Call
+}
+
+// GetWebhookDeliveries is a helper method to define mock.On call
+//   - surveyUUID string
+//   - filter *types.WebhookDeliveriesFilter
+func (_e *MockInterface_Expecter) GetWebhookDeliveries(surveyUUID interface{}, filter interface{}) *MockInterface_GetWebhookDeliveries_Call {
+	return &MockInterface_GetWebhookDeliveries_Call{Call: _e.mock.On("GetWebhookDeliveries", surveyUUID, filter)}
+}
+
+func (_c *MockInterface_GetWebhookDeliveries_Call) Run(run func(surveyUUID string, filter *types.WebhookDeliveriesFilter)) *MockInterface_GetWebhookDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 *types.WebhookDeliveriesFilter
+		if args[1] != nil {
+			arg1 = args[1].(*types.WebhookDeliveriesFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_GetWebhookDeliveries_Call) Return(webhookDeliverys []types.WebhookDelivery, err error) *MockInterface_GetWebhookDeliveries_Call {
+	_c.Call.Return(webhookDeliverys, err)
+	return _c
+}
+
+func (_c *MockInterface_GetWebhookDeliveries_Call) RunAndReturn(run func(surveyUUID string, filter *types.WebhookDeliveriesFilter) ([]types.WebhookDelivery, error)) *MockInterface_GetWebhookDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhookDelivery provides a mock function for the type MockInterface
+func (_mock *MockInterface) GetWebhookDelivery(surveyUUID string, deliveryUUID string) (*types.WebhookDelivery, error) {
+	ret := _mock.Called(surveyUUID, deliveryUUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDelivery")
+	}
+
+	var r0 *types.WebhookDelivery
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) (*types.WebhookDelivery, error)); ok {
+		return returnFunc(surveyUUID, deliveryUUID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string) *types.WebhookDelivery); ok {
+		r0 = returnFunc(surveyUUID, deliveryUUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.WebhookDelivery)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = returnFunc(surveyUUID, deliveryUUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInterface_GetWebhookDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhookDelivery'
+type MockInterface_GetWebhookDelivery_Call struct {
+	*mock.Call
+}
+
+// GetWebhookDelivery is a helper method to define mock.On call
+//   - surveyUUID string
+//   - deliveryUUID string
+func (_e *MockInterface_Expecter) GetWebhookDelivery(surveyUUID interface{}, deliveryUUID interface{}) *MockInterface_GetWebhookDelivery_Call {
+	return &MockInterface_GetWebhookDelivery_Call{Call: _e.mock.On("GetWebhookDelivery", surveyUUID, deliveryUUID)}
+}
+
+func (_c *MockInterface_GetWebhookDelivery_Call) Run(run func(surveyUUID string, deliveryUUID string)) *MockInterface_GetWebhookDelivery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_GetWebhookDelivery_Call) Return(webhookDelivery *types.WebhookDelivery, err error) *MockInterface_GetWebhookDelivery_Call {
+	_c.Call.Return(webhookDelivery, err)
+	return _c
+}
+
+func (_c *MockInterface_GetWebhookDelivery_Call) RunAndReturn(run func(surveyUUID string, deliveryUUID string) (*types.WebhookDelivery, error)) *MockInterface_GetWebhookDelivery_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Init provides a mock function for the type MockInterface
+func (_mock *MockInterface) Init() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Init")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInterface_Init_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Init'
+type MockInterface_Init_Call struct {
+	*mock.Call
+}
+
+// Init is a helper method to define mock.On call
+func (_e *MockInterface_Expecter) Init() *MockInterface_Init_Call {
+	return &MockInterface_Init_Call{Call: _e.mock.On("Init")}
+}
+
+func (_c *MockInterface_Init_Call) Run(run func()) *MockInterface_Init_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockInterface_Init_Call) Return(err error) *MockInterface_Init_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInterface_Init_Call) RunAndReturn(run func() error) *MockInterface_Init_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Migrate provides a mock function for the type MockInterface
+func (_mock *MockInterface) Migrate() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Migrate")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInterface_Migrate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Migrate'
+type MockInterface_Migrate_Call struct {
+	*mock.Call
+}
+
+// Migrate is a helper method to define mock.On call
+func (_e *MockInterface_Expecter) Migrate() *MockInterface_Migrate_Call {
+	return &MockInterface_Migrate_Call{Call: _e.mock.On("Migrate")}
+}
+
+func (_c *MockInterface_Migrate_Call) Run(run func()) *MockInterface_Migrate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockInterface_Migrate_Call) Return(err error) *MockInterface_Migrate_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInterface_Migrate_Call) RunAndReturn(run func() error) *MockInterface_Migrate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ping provides a mock function for the type MockInterface
+func (_mock *MockInterface) Ping() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInterface_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
+type MockInterface_Ping_Call struct {
+	*mock.Call
+}
+
+// Ping is a helper method to define mock.On call
+func (_e *MockInterface_Expecter) Ping() *MockInterface_Ping_Call {
+	return &MockInterface_Ping_Call{Call: _e.mock.On("Ping")}
+}
+
+func (_c *MockInterface_Ping_Call) Run(run func()) *MockInterface_Ping_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockInterface_Ping_Call) Return(err error) *MockInterface_Ping_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInterface_Ping_Call) RunAndReturn(run func() error) *MockInterface_Ping_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RedeliverWebhookDelivery provides a mock function for the type MockInterface
+func (_mock *MockInterface) RedeliverWebhookDelivery(delivery *types.WebhookDelivery) (bool, error) {
+	ret := _mock.Called(delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedeliverWebhookDelivery")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*types.WebhookDelivery) (bool, error)); ok {
+		return returnFunc(delivery)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*types.WebhookDelivery) bool); ok {
+		r0 = returnFunc(delivery)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(*types.WebhookDelivery) error); ok {
+		r1 = returnFunc(delivery)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInterface_RedeliverWebhookDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedeliverWebhookDelivery'
+type MockInterface_RedeliverWebhookDelivery_Call struct {
+	*mock.Call
+}
+
+// RedeliverWebhookDelivery is a helper method to define mock.On call
+//   - delivery *types.WebhookDelivery
+func (_e *MockInterface_Expecter) RedeliverWebhookDelivery(delivery interface{}) *MockInterface_RedeliverWebhookDelivery_Call {
+	return &MockInterface_RedeliverWebhookDelivery_Call{Call: _e.mock.On("RedeliverWebhookDelivery", delivery)}
+}
+
+func (_c *MockInterface_RedeliverWebhookDelivery_Call) Run(run func(delivery *types.WebhookDelivery)) *MockInterface_RedeliverWebhookDelivery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *types.WebhookDelivery
+		if args[0] != nil {
+			arg0 = args[0].(*types.WebhookDelivery)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_RedeliverWebhookDelivery_Call) Return(b bool, err error) *MockInterface_RedeliverWebhookDelivery_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockInterface_RedeliverWebhookDelivery_Call) RunAndReturn(run func(delivery *types.WebhookDelivery) (bool, error)) *MockInterface_RedeliverWebhookDelivery_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ScreenOutSurveySession provides a mock function for the type MockInterface
+func (_mock *MockInterface) ScreenOutSurveySession(sessionUUID string, quotaID string) error {
+	ret := _mock.Called(sessionUUID, quotaID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScreenOutSurveySession")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(sessionUUID, quotaID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInterface_ScreenOutSurveySession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScreenOutSurveySession'
+type MockInterface_ScreenOutSurveySession_Call struct {
+	*mock.Call
+}
+
+// ScreenOutSurveySession is a helper method to define mock.On call
+//   - sessionUUID string
+//   - quotaID string
+func (_e *MockInterface_Expecter) ScreenOutSurveySession(sessionUUID interface{}, quotaID interface{}) *MockInterface_ScreenOutSurveySession_Call {
+	return &MockInterface_ScreenOutSurveySession_Call{Call: _e.mock.On("ScreenOutSurveySession", sessionUUID, quotaID)}
+}
+
+func (_c *MockInterface_ScreenOutSurveySession_Call) Run(run func(sessionUUID string, quotaID string)) *MockInterface_ScreenOutSurveySession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_ScreenOutSurveySession_Call) Return(err error) *MockInterface_ScreenOutSurveySession_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInterface_ScreenOutSurveySession_Call) RunAndReturn(run func(sessionUUID string, quotaID string) error) *MockInterface_ScreenOutSurveySession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SkipSurveyQuestion provides a mock function for the type MockInterface
+func (_mock *MockInterface) SkipSurveyQuestion(sessionUUID string, questionUUID string) error {
+	ret := _mock.Called(sessionUUID, questionUUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SkipSurveyQuestion")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(sessionUUID, questionUUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInterface_SkipSurveyQuestion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SkipSurveyQuestion'
+type MockInterface_SkipSurveyQuestion_Call struct {
+	*mock.Call
+}
+
+// SkipSurveyQuestion is a helper method to define mock.On call
+//   - sessionUUID string
+//   - questionUUID string
+func (_e *MockInterface_Expecter) SkipSurveyQuestion(sessionUUID interface{}, questionUUID interface{}) *MockInterface_SkipSurveyQuestion_Call {
+	return &MockInterface_SkipSurveyQuestion_Call{Call: _e.mock.On("SkipSurveyQuestion", sessionUUID, questionUUID)}
+}
+
+func (_c *MockInterface_SkipSurveyQuestion_Call) Run(run func(sessionUUID string, questionUUID string)) *MockInterface_SkipSurveyQuestion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_SkipSurveyQuestion_Call) Return(err error) *MockInterface_SkipSurveyQuestion_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInterface_SkipSurveyQuestion_Call) RunAndReturn(run func(sessionUUID string, questionUUID string) error) *MockInterface_SkipSurveyQuestion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreWebhookResponse provides a mock function for the type MockInterface
+func (_mock *MockInterface) StoreWebhookResponse(delivery *types.WebhookDelivery, attempt types.WebhookAttempt) error {
+	ret := _mock.Called(delivery, attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreWebhookResponse")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*types.WebhookDelivery, types.WebhookAttempt) error); ok {
+		r0 = returnFunc(delivery, attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInterface_StoreWebhookResponse_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreWebhookResponse'
+type MockInterface_StoreWebhookResponse_Call struct {
+	*mock.Call
+}
+
+// StoreWebhookResponse is a helper method to define mock.On call
+//   - delivery *types.WebhookDelivery
+//   - attempt types.WebhookAttempt
+func (_e *MockInterface_Expecter) StoreWebhookResponse(delivery interface{}, attempt interface{}) *MockInterface_StoreWebhookResponse_Call {
+	return &MockInterface_StoreWebhookResponse_Call{Call: _e.mock.On("StoreWebhookResponse", delivery, attempt)}
+}
+
+func (_c *MockInterface_StoreWebhookResponse_Call) Run(run func(delivery *types.WebhookDelivery, attempt types.WebhookAttempt)) *MockInterface_StoreWebhookResponse_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *types.WebhookDelivery
+		if args[0] != nil {
+			arg0 = args[0].(*types.WebhookDelivery)
+		}
+		var arg1 types.WebhookAttempt
+		if args[1] != nil {
+			arg1 = args[1].(types.WebhookAttempt)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_StoreWebhookResponse_Call) Return(err error) *MockInterface_StoreWebhookResponse_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockInterface_GetSurveys_Call) RunAndReturn(run func() ([]*types.Survey, error)) *MockInterface_GetSurveys_Call {
+func (_c *MockInterface_StoreWebhookResponse_Call) RunAndReturn(run func(delivery *types.WebhookDelivery, attempt types.WebhookAttempt) error) *MockInterface_StoreWebhookResponse_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Init provides a mock function for the type MockInterface
-func (_mock *MockInterface) Init() error {
-	ret := _mock.Called()
+// UpdateSurvey provides a mock function for the type MockInterface
+func (_mock *MockInterface) UpdateSurvey(survey *types.Survey) error {
+	ret := _mock.Called(survey)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Init")
+		panic("no return value specified for UpdateSurvey")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func() error); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(*types.Survey) error); ok {
+		r0 = returnFunc(survey)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockInterface_Init_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Init'
-type MockInterface_Init_Call struct {
+// MockInterface_UpdateSurvey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSurvey'
+type MockInterface_UpdateSurvey_Call struct {
 	*mock.Call
 }
 
-// Init is a helper method to define mock.On call
-func (_e *MockInterface_Expecter) Init() *MockInterface_Init_Call {
-	return &MockInterface_Init_Call{Call: _e.mock.On("Init")}
+// UpdateSurvey is a helper method to define mock.On call
+//   - survey *types.Survey
+func (_e *MockInterface_Expecter) UpdateSurvey(survey interface{}) *MockInterface_UpdateSurvey_Call {
+	return &MockInterface_UpdateSurvey_Call{Call: _e.mock.On("UpdateSurvey", survey)}
 }
 
-func (_c *MockInterface_Init_Call) Run(run func()) *MockInterface_Init_Call {
+func (_c *MockInterface_UpdateSurvey_Call) Run(run func(survey *types.Survey)) *MockInterface_UpdateSurvey_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 *types.Survey
+		if args[0] != nil {
+			arg0 = args[0].(*types.Survey)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
 
-func (_c *MockInterface_Init_Call) Return(err error) *MockInterface_Init_Call {
+func (_c *MockInterface_UpdateSurvey_Call) Return(err error) *MockInterface_UpdateSurvey_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockInterface_Init_Call) RunAndReturn(run func() error) *MockInterface_Init_Call {
+func (_c *MockInterface_UpdateSurvey_Call) RunAndReturn(run func(survey *types.Survey) error) *MockInterface_UpdateSurvey_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Migrate provides a mock function for the type MockInterface
-func (_mock *MockInterface) Migrate() error {
-	ret := _mock.Called()
+// UpdateSurveySessionDisplayOrder provides a mock function for the type MockInterface
+func (_mock *MockInterface) UpdateSurveySessionDisplayOrder(sessionUUID string, order *types.SessionOrder) error {
+	ret := _mock.Called(sessionUUID, order)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Migrate")
+		panic("no return value specified for UpdateSurveySessionDisplayOrder")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func() error); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(string, *types.SessionOrder) error); ok {
+		r0 = returnFunc(sessionUUID, order)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockInterface_Migrate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Migrate'
-type MockInterface_Migrate_Call struct {
+// MockInterface_UpdateSurveySessionDisplayOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSurveySessionDisplayOrder'
+type MockInterface_UpdateSurveySessionDisplayOrder_Call struct {
 	*mock.Call
 }
 
-// Migrate is a helper method to define mock.On call
-func (_e *MockInterface_Expecter) Migrate() *MockInterface_Migrate_Call {
-	return &MockInterface_Migrate_Call{Call: _e.mock.On("Migrate")}
+// UpdateSurveySessionDisplayOrder is a helper method to define mock.On call
+//   - sessionUUID string
+//   - order *types.SessionOrder
+func (_e *MockInterface_Expecter) UpdateSurveySessionDisplayOrder(sessionUUID interface{}, order interface{}) *MockInterface_UpdateSurveySessionDisplayOrder_Call {
+	return &MockInterface_UpdateSurveySessionDisplayOrder_Call{Call: _e.mock.On("UpdateSurveySessionDisplayOrder", sessionUUID, order)}
 }
 
-func (_c *MockInterface_Migrate_Call) Run(run func()) *MockInterface_Migrate_Call {
+func (_c *MockInterface_UpdateSurveySessionDisplayOrder_Call) Run(run func(sessionUUID string, order *types.SessionOrder)) *MockInterface_UpdateSurveySessionDisplayOrder_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 *types.SessionOrder
+		if args[1] != nil {
+			arg1 = args[1].(*types.SessionOrder)
+		}
+		run(
+			arg0,
+			arg1,
+		)
 	})
 	return _c
 }
 
-func (_c *MockInterface_Migrate_Call) Return(err error) *MockInterface_Migrate_Call {
+func (_c *MockInterface_UpdateSurveySessionDisplayOrder_Call) Return(err error) *MockInterface_UpdateSurveySessionDisplayOrder_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockInterface_Migrate_Call) RunAndReturn(run func() error) *MockInterface_Migrate_Call {
+func (_c *MockInterface_UpdateSurveySessionDisplayOrder_Call) RunAndReturn(run func(sessionUUID string, order *types.SessionOrder) error) *MockInterface_UpdateSurveySessionDisplayOrder_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Ping provides a mock function for the type MockInterface
-func (_mock *MockInterface) Ping() error {
-	ret := _mock.Called()
+// UpdateSurveySessionScore provides a mock function for the type MockInterface
+func (_mock *MockInterface) UpdateSurveySessionScore(sessionUUID string, score int, maxScore int) error {
+	ret := _mock.Called(sessionUUID, score, maxScore)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Ping")
+		panic("no return value specified for UpdateSurveySessionScore")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func() error); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) error); ok {
+		r0 = returnFunc(sessionUUID, score, maxScore)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockInterface_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
-type MockInterface_Ping_Call struct {
+// MockInterface_UpdateSurveySessionScore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSurveySessionScore'
+type MockInterface_UpdateSurveySessionScore_Call struct {
 	*mock.Call
 }
 
-// Ping is a helper method to define mock.On call
-func (_e *MockInterface_Expecter) Ping() *MockInterface_Ping_Call {
-	return &MockInterface_Ping_Call{Call: _e.mock.On("Ping")}
+// UpdateSurveySessionScore is a helper method to define mock.On call
+//   - sessionUUID string
+//   - score int
+//   - maxScore int
+func (_e *MockInterface_Expecter) UpdateSurveySessionScore(sessionUUID interface{}, score interface{}, maxScore interface{}) *MockInterface_UpdateSurveySessionScore_Call {
+	return &MockInterface_UpdateSurveySessionScore_Call{Call: _e.mock.On("UpdateSurveySessionScore", sessionUUID, score, maxScore)}
 }
 
-func (_c *MockInterface_Ping_Call) Run(run func()) *MockInterface_Ping_Call {
+func (_c *MockInterface_UpdateSurveySessionScore_Call) Run(run func(sessionUUID string, score int, maxScore int)) *MockInterface_UpdateSurveySessionScore_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
 	})
 	return _c
 }
 
-func (_c *MockInterface_Ping_Call) Return(err error) *MockInterface_Ping_Call {
+func (_c *MockInterface_UpdateSurveySessionScore_Call) Return(err error) *MockInterface_UpdateSurveySessionScore_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockInterface_Ping_Call) RunAndReturn(run func() error) *MockInterface_Ping_Call {
+func (_c *MockInterface_UpdateSurveySessionScore_Call) RunAndReturn(run func(sessionUUID string, score int, maxScore int) error) *MockInterface_UpdateSurveySessionScore_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// StoreWebhookResponse provides a mock function for the type MockInterface
-func (_mock *MockInterface) StoreWebhookResponse(sessionId int, responseStatus int, response string) error {
-	ret := _mock.Called(sessionId, responseStatus, response)
+// UpdateSurveySessionStatus provides a mock function for the type MockInterface
+func (_mock *MockInterface) UpdateSurveySessionStatus(sessionUUID string, newStatus types.SurveySessionStatus) error {
+	ret := _mock.Called(sessionUUID, newStatus)
 
 	if len(ret) == 0 {
-		panic("no return value specified for StoreWebhookResponse")
+		panic("no return value specified for UpdateSurveySessionStatus")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(int, int, string) error); ok {
-		r0 = returnFunc(sessionId, responseStatus, response)
+	if returnFunc, ok := ret.Get(0).(func(string, types.SurveySessionStatus) error); ok {
+		r0 = returnFunc(sessionUUID, newStatus)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockInterface_StoreWebhookResponse_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreWebhookResponse'
-type MockInterface_StoreWebhookResponse_Call struct {
+// MockInterface_UpdateSurveySessionStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSurveySessionStatus'
+type MockInterface_UpdateSurveySessionStatus_Call struct {
 	*mock.Call
 }
 
-// StoreWebhookResponse is a helper method to define mock.On call
-//   - sessionId int
-//   - responseStatus int
-//   - response string
-func (_e *MockInterface_Expecter) StoreWebhookResponse(sessionId interface{}, responseStatus interface{}, response interface{}) *MockInterface_StoreWebhookResponse_Call {
-	return &MockInterface_StoreWebhookResponse_Call{Call: _e.mock.On("StoreWebhookResponse", sessionId, responseStatus, response)}
+// UpdateSurveySessionStatus is a helper method to define mock.On call
+//   - sessionUUID string
+//   - newStatus types.SurveySessionStatus
+func (_e *MockInterface_Expecter) UpdateSurveySessionStatus(sessionUUID interface{}, newStatus interface{}) *MockInterface_UpdateSurveySessionStatus_Call {
+	return &MockInterface_UpdateSurveySessionStatus_Call{Call: _e.mock.On("UpdateSurveySessionStatus", sessionUUID, newStatus)}
 }
 
-func (_c *MockInterface_StoreWebhookResponse_Call) Run(run func(sessionId int, responseStatus int, response string)) *MockInterface_StoreWebhookResponse_Call {
+func (_c *MockInterface_UpdateSurveySessionStatus_Call) Run(run func(sessionUUID string, newStatus types.SurveySessionStatus)) *MockInterface_UpdateSurveySessionStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 int
+		var arg0 string
 		if args[0] != nil {
-			arg0 = args[0].(int)
+			arg0 = args[0].(string)
 		}
-		var arg1 int
+		var arg1 types.SurveySessionStatus
 		if args[1] != nil {
-			arg1 = args[1].(int)
-		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
+			arg1 = args[1].(types.SurveySessionStatus)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockInterface_StoreWebhookResponse_Call) Return(err error) *MockInterface_StoreWebhookResponse_Call {
+func (_c *MockInterface_UpdateSurveySessionStatus_Call) Return(err error) *MockInterface_UpdateSurveySessionStatus_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockInterface_StoreWebhookResponse_Call) RunAndReturn(run func(sessionId int, responseStatus int, response string) error) *MockInterface_StoreWebhookResponse_Call {
+func (_c *MockInterface_UpdateSurveySessionStatus_Call) RunAndReturn(run func(sessionUUID string, newStatus types.SurveySessionStatus) error) *MockInterface_UpdateSurveySessionStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateSurvey provides a mock function for the type MockInterface
-func (_mock *MockInterface) UpdateSurvey(survey *types.Survey) error {
-	ret := _mock.Called(survey)
+// UpdateSurveySessionVariables provides a mock function for the type MockInterface
+func (_mock *MockInterface) UpdateSurveySessionVariables(sessionUUID string, variables map[string]interface{}) error {
+	ret := _mock.Called(sessionUUID, variables)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateSurvey")
+		panic("no return value specified for UpdateSurveySessionVariables")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*types.Survey) error); ok {
-		r0 = returnFunc(survey)
+	if returnFunc, ok := ret.Get(0).(func(string, map[string]interface{}) error); ok {
+		r0 = returnFunc(sessionUUID, variables)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockInterface_UpdateSurvey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSurvey'
-type MockInterface_UpdateSurvey_Call struct {
+// MockInterface_UpdateSurveySessionVariables_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSurveySessionVariables'
+type MockInterface_UpdateSurveySessionVariables_Call struct {
 	*mock.Call
 }
 
-// UpdateSurvey is a helper method to define mock.On call
-//   - survey *types.Survey
-func (_e *MockInterface_Expecter) UpdateSurvey(survey interface{}) *MockInterface_UpdateSurvey_Call {
-	return &MockInterface_UpdateSurvey_Call{Call: _e.mock.On("UpdateSurvey", survey)}
+// UpdateSurveySessionVariables is a helper method to define mock.On call
+//   - sessionUUID string
+//   - variables map[string]interface{}
+func (_e *MockInterface_Expecter) UpdateSurveySessionVariables(sessionUUID interface{}, variables interface{}) *MockInterface_UpdateSurveySessionVariables_Call {
+	return &MockInterface_UpdateSurveySessionVariables_Call{Call: _e.mock.On("UpdateSurveySessionVariables", sessionUUID, variables)}
 }
 
-func (_c *MockInterface_UpdateSurvey_Call) Run(run func(survey *types.Survey)) *MockInterface_UpdateSurvey_Call {
+func (_c *MockInterface_UpdateSurveySessionVariables_Call) Run(run func(sessionUUID string, variables map[string]interface{})) *MockInterface_UpdateSurveySessionVariables_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *types.Survey
+		var arg0 string
 		if args[0] != nil {
-			arg0 = args[0].(*types.Survey)
+			arg0 = args[0].(string)
+		}
+		var arg1 map[string]interface{}
+		if args[1] != nil {
+			arg1 = args[1].(map[string]interface{})
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockInterface_UpdateSurvey_Call) Return(err error) *MockInterface_UpdateSurvey_Call {
+func (_c *MockInterface_UpdateSurveySessionVariables_Call) Return(err error) *MockInterface_UpdateSurveySessionVariables_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockInterface_UpdateSurvey_Call) RunAndReturn(run func(survey *types.Survey) error) *MockInterface_UpdateSurvey_Call {
+func (_c *MockInterface_UpdateSurveySessionVariables_Call) RunAndReturn(run func(sessionUUID string, variables map[string]interface{}) error) *MockInterface_UpdateSurveySessionVariables_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateSurveySessionStatus provides a mock function for the type MockInterface
-func (_mock *MockInterface) UpdateSurveySessionStatus(sessionUUID string, newStatus types.SurveySessionStatus) error {
-	ret := _mock.Called(sessionUUID, newStatus)
+// UpdateWebhookDelivery provides a mock function for the type MockInterface
+func (_mock *MockInterface) UpdateWebhookDelivery(delivery *types.WebhookDelivery) error {
+	ret := _mock.Called(delivery)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateSurveySessionStatus")
+		panic("no return value specified for UpdateWebhookDelivery")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string, types.SurveySessionStatus) error); ok {
-		r0 = returnFunc(sessionUUID, newStatus)
+	if returnFunc, ok := ret.Get(0).(func(*types.WebhookDelivery) error); ok {
+		r0 = returnFunc(delivery)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockInterface_UpdateSurveySessionStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSurveySessionStatus'
-type MockInterface_UpdateSurveySessionStatus_Call struct {
+// MockInterface_UpdateWebhookDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateWebhookDelivery'
+type MockInterface_UpdateWebhookDelivery_Call struct {
 	*mock.Call
 }
 
-// UpdateSurveySessionStatus is a helper method to define mock.On call
-//   - sessionUUID string
-//   - newStatus types.SurveySessionStatus
-func (_e *MockInterface_Expecter) UpdateSurveySessionStatus(sessionUUID interface{}, newStatus interface{}) *MockInterface_UpdateSurveySessionStatus_Call {
-	return &MockInterface_UpdateSurveySessionStatus_Call{Call: _e.mock.On("UpdateSurveySessionStatus", sessionUUID, newStatus)}
+// UpdateWebhookDelivery is a helper method to define mock.On call
+//   - delivery *types.WebhookDelivery
+func (_e *MockInterface_Expecter) UpdateWebhookDelivery(delivery interface{}) *MockInterface_UpdateWebhookDelivery_Call {
+	return &MockInterface_UpdateWebhookDelivery_Call{Call: _e.mock.On("UpdateWebhookDelivery", delivery)}
 }
 
-func (_c *MockInterface_UpdateSurveySessionStatus_Call) Run(run func(sessionUUID string, newStatus types.SurveySessionStatus)) *MockInterface_UpdateSurveySessionStatus_Call {
+func (_c *MockInterface_UpdateWebhookDelivery_Call) Run(run func(delivery *types.WebhookDelivery)) *MockInterface_UpdateWebhookDelivery_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 *types.WebhookDelivery
 		if args[0] != nil {
-			arg0 = args[0].(string)
-		}
-		var arg1 types.SurveySessionStatus
-		if args[1] != nil {
-			arg1 = args[1].(types.SurveySessionStatus)
+			arg0 = args[0].(*types.WebhookDelivery)
 		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockInterface_UpdateSurveySessionStatus_Call) Return(err error) *MockInterface_UpdateSurveySessionStatus_Call {
+func (_c *MockInterface_UpdateWebhookDelivery_Call) Return(err error) *MockInterface_UpdateWebhookDelivery_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockInterface_UpdateSurveySessionStatus_Call) RunAndReturn(run func(sessionUUID string, newStatus types.SurveySessionStatus) error) *MockInterface_UpdateSurveySessionStatus_Call {
+func (_c *MockInterface_UpdateWebhookDelivery_Call) RunAndReturn(run func(delivery *types.WebhookDelivery) error) *MockInterface_UpdateWebhookDelivery_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1056,6 +2123,63 @@ func (_c *MockInterface_UpsertSurveyQuestionAnswer_Call) RunAndReturn(run func(s
 	return _c
 }
 
+// UpsertSurveyQuestionAnswers provides a mock function for the type MockInterface
+func (_mock *MockInterface) UpsertSurveyQuestionAnswers(sessionUUID string, answers []types.QuestionAnswer) error {
+	ret := _mock.Called(sessionUUID, answers)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertSurveyQuestionAnswers")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, []types.QuestionAnswer) error); ok {
+		r0 = returnFunc(sessionUUID, answers)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInterface_UpsertSurveyQuestionAnswers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertSurveyQuestionAnswers'
+type MockInterface_UpsertSurveyQuestionAnswers_Call struct {
+	*mock.Call
+}
+
+// UpsertSurveyQuestionAnswers is a helper method to define mock.On call
+//   - sessionUUID string
+//   - answers []types.QuestionAnswer
+func (_e *MockInterface_Expecter) UpsertSurveyQuestionAnswers(sessionUUID interface{}, answers interface{}) *MockInterface_UpsertSurveyQuestionAnswers_Call {
+	return &MockInterface_UpsertSurveyQuestionAnswers_Call{Call: _e.mock.On("UpsertSurveyQuestionAnswers", sessionUUID, answers)}
+}
+
+func (_c *MockInterface_UpsertSurveyQuestionAnswers_Call) Run(run func(sessionUUID string, answers []types.QuestionAnswer)) *MockInterface_UpsertSurveyQuestionAnswers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []types.QuestionAnswer
+		if args[1] != nil {
+			arg1 = args[1].([]types.QuestionAnswer)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_UpsertSurveyQuestionAnswers_Call) Return(err error) *MockInterface_UpsertSurveyQuestionAnswers_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInterface_UpsertSurveyQuestionAnswers_Call) RunAndReturn(run func(sessionUUID string, answers []types.QuestionAnswer) error) *MockInterface_UpsertSurveyQuestionAnswers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpsertSurveyQuestions provides a mock function for the type MockInterface
 func (_mock *MockInterface) UpsertSurveyQuestions(survey *types.Survey) error {
 	ret := _mock.Called(survey)
@@ -1107,6 +2231,57 @@ func (_c *MockInterface_UpsertSurveyQuestions_Call) RunAndReturn(run func(survey
 	return _c
 }
 
+// WithTx provides a mock function for the type MockInterface
+func (_mock *MockInterface) WithTx(fn func(tx Interface) error) error {
+	ret := _mock.Called(fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(func(tx Interface) error) error); ok {
+		r0 = returnFunc(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInterface_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type MockInterface_WithTx_Call struct {
+	*mock.Call
+}
+
+// WithTx is a helper method to define mock.On call
+//   - fn func(tx Interface) error
+func (_e *MockInterface_Expecter) WithTx(fn interface{}) *MockInterface_WithTx_Call {
+	return &MockInterface_WithTx_Call{Call: _e.mock.On("WithTx", fn)}
+}
+
+func (_c *MockInterface_WithTx_Call) Run(run func(fn func(tx Interface) error)) *MockInterface_WithTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 func(tx Interface) error
+		if args[0] != nil {
+			arg0 = args[0].(func(tx Interface) error)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockInterface_WithTx_Call) Return(err error) *MockInterface_WithTx_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInterface_WithTx_Call) RunAndReturn(run func(fn func(tx Interface) error) error) *MockInterface_WithTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockFileInterface creates a new instance of MockFileInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockFileInterface(t interface {
@@ -15,15 +15,18 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/plutov/formulosity/api/pkg/db"
 	"github.com/plutov/formulosity/api/pkg/types"
 )
 
 type Postgres struct {
-	conn    *pgx.Conn
+	conn    *pgxpool.Pool
 	queries *db.Queries
 	addr    string
 	ctx     context.Context
+	// set for the storage returned to WithTx
+	tx pgx.Tx
 }
 
 func (p *Postgres) Init() error {
@@ -35,7 +38,7 @@ func (p *Postgres) Init() error {
 	}
 
 	var err error
-	p.conn, err = pgx.Connect(context.Background(), p.addr)
+	p.conn, err = pgxpool.New(context.Background(), p.addr)
 	if err != nil {
 		log.Fatalf("cannot connect to postgres: %v", err)
 	}
@@ -57,7 +60,8 @@ func (p *Postgres) Ping() error {
 }
 
 func (p *Postgres) Close() error {
-	return p.conn.Close(context.Background())
+	p.conn.Close()
+	return nil
 }
 
 func (p *Postgres) Migrate() error {
@@ -76,6 +80,39 @@ func (p *Postgres) Migrate() error {
 	return nil
 }
 
+// WithTx calls fn with the storage bound to a transaction, which is committed if fn returns no error,
+// transactions started by the storage methods within fn are nested in it
+func (p *Postgres) WithTx(fn func(tx Interface) error) error {
+	tx, err := p.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(p.ctx)
+	}()
+
+	if err := fn(&Postgres{
+		conn:    p.conn,
+		queries: p.queries.WithTx(tx),
+		addr:    p.addr,
+		ctx:     p.ctx,
+		tx:      tx,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(p.ctx)
+}
+
+// begin starts a transaction, or a savepoint within the transaction of WithTx
+func (p *Postgres) begin() (pgx.Tx, error) {
+	if p.tx != nil {
+		return p.tx.Begin(p.ctx)
+	}
+
+	return p.conn.Begin(p.ctx)
+}
+
 func (p *Postgres) CreateSurvey(survey *types.Survey) error {
 	configBytes, err := json.Marshal(survey.Config)
 	if err != nil {
@@ -130,6 +167,21 @@ func (p *Postgres) UpdateSurvey(survey *types.Survey) error {
 	})
 }
 
+// CreateSurveyDeliveryTransition records a scheduled delivery status transition,
+// returns false if it was already recorded
+func (p *Postgres) CreateSurveyDeliveryTransition(surveyID int64, status types.SurveyDeliveryStatus, scheduledAt time.Time) (bool, error) {
+	rows, err := p.queries.CreateSurveyDeliveryTransition(p.ctx, db.CreateSurveyDeliveryTransitionParams{
+		SurveyID:       int32(surveyID),
+		DeliveryStatus: db.SurveyDeliveryStatuses(status),
+		ScheduledAt:    pgtype.Timestamp{Valid: true, Time: scheduledAt.UTC()},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
 func (p *Postgres) GetSurveys() ([]*types.Survey, error) {
 	rows, err := p.queries.GetSurveys(p.ctx, db.GetSurveysParams{
 		Status: db.NullSurveysSessionsStatus{
@@ -291,20 +343,132 @@ func (p *Postgres) CreateSurveySession(session *types.SurveySession) error {
 		return fmt.Errorf("failed to decode survey UUID: %w", err)
 	}
 
-	row, err := p.queries.CreateSurveySession(p.ctx, db.CreateSurveySessionParams{
-		Status: db.NullSurveysSessionsStatus{Valid: true, SurveysSessionsStatus: db.SurveysSessionsStatus(session.Status)},
-		Uuid:   surveyUUID,
-		IpAddr: pgtype.Text{Valid: true, String: session.IPAddr},
+	var hiddenFieldsBytes []byte
+	if len(session.HiddenFields) > 0 {
+		hiddenFieldsBytes, err = json.Marshal(session.HiddenFields)
+		if err != nil {
+			return fmt.Errorf("failed to marshal hidden fields: %w", err)
+		}
+	}
+
+	tx, err := p.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(p.ctx)
+	}()
+
+	queries := p.queries.WithTx(tx)
+	row, err := queries.CreateSurveySession(p.ctx, db.CreateSurveySessionParams{
+		Status:       db.NullSurveysSessionsStatus{Valid: true, SurveysSessionsStatus: db.SurveysSessionsStatus(session.Status)},
+		Uuid:         surveyUUID,
+		IpAddr:       pgtype.Text{Valid: true, String: session.IPAddr},
+		Locale:       pgtype.Text{Valid: session.Locale != "", String: session.Locale},
+		HiddenFields: hiddenFieldsBytes,
 	})
 	if err != nil {
 		return err
 	}
 
+	// the invitation is used in the same transaction, so the token can't be used twice
+	if session.InvitationToken != "" {
+		rows, err := queries.UseSurveyInvitation(p.ctx, db.UseSurveyInvitationParams{
+			SessionID: pgtype.Int4{Valid: true, Int32: row.ID},
+			Uuid:      surveyUUID,
+			Token:     session.InvitationToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to use invitation: %w", err)
+		}
+		if rows == 0 {
+			return types.ErrInvitationNotFound
+		}
+	}
+
+	if err := tx.Commit(p.ctx); err != nil {
+		return err
+	}
+
 	session.ID = int64(row.ID)
 	session.UUID = db.EncodeUUID(row.Uuid)
 	return nil
 }
 
+// CreateSurveyInvitations creates invitations with their tokens,
+// returns only created ones as respondents with the same email are invited once
+func (p *Postgres) CreateSurveyInvitations(surveyUUID string, invitations []types.Invitation) ([]types.Invitation, error) {
+	surveyUUIDPg, err := db.DecodeUUID(surveyUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode survey UUID: %w", err)
+	}
+
+	tx, err := p.begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(p.ctx)
+	}()
+
+	queries := p.queries.WithTx(tx)
+	created := []types.Invitation{}
+	for _, invitation := range invitations {
+		row, err := queries.CreateSurveyInvitation(p.ctx, db.CreateSurveyInvitationParams{
+			Uuid:  surveyUUIDPg,
+			Token: invitation.Token,
+			Email: invitation.Email,
+			Name:  pgtype.Text{Valid: invitation.Name != "", String: invitation.Name},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create invitation: %w", err)
+		}
+
+		invitation.UUID = db.EncodeUUID(row.Uuid)
+		invitation.CreatedAt = row.CreatedAt.Time
+		created = append(created, invitation)
+	}
+
+	return created, tx.Commit(p.ctx)
+}
+
+func (p *Postgres) GetSurveyInvitations(surveyUUID string) ([]types.Invitation, error) {
+	surveyUUIDPg, err := db.DecodeUUID(surveyUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode survey UUID: %w", err)
+	}
+
+	rows, err := p.queries.GetSurveyInvitations(p.ctx, surveyUUIDPg)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := []types.Invitation{}
+	for _, row := range rows {
+		invitation := types.Invitation{
+			UUID:      db.EncodeUUID(row.Uuid),
+			CreatedAt: row.CreatedAt.Time,
+			Email:     row.Email,
+			Name:      row.Name.String,
+			Token:     row.Token,
+		}
+		if row.UsedAt.Valid {
+			invitation.UsedAt = &row.UsedAt.Time
+		}
+		if row.SessionUuid.Valid {
+			invitation.SessionUUID = db.EncodeUUID(row.SessionUuid)
+			invitation.SessionStatus = types.SurveySessionStatus(row.SessionStatus.SurveysSessionsStatus)
+		}
+
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, nil
+}
+
 func (p *Postgres) UpdateSurveySessionStatus(sessionUUID string, newStatus types.SurveySessionStatus) error {
 	uuid, err := db.DecodeUUID(sessionUUID)
 	if err != nil {
@@ -324,6 +488,140 @@ func (p *Postgres) UpdateSurveySessionStatus(sessionUUID string, newStatus types
 	}
 }
 
+func (p *Postgres) ScreenOutSurveySession(sessionUUID string, quotaID string) error {
+	uuid, err := db.DecodeUUID(sessionUUID)
+	if err != nil {
+		return fmt.Errorf("failed to decode session UUID: %w", err)
+	}
+
+	return p.queries.UpdateSurveySessionScreenedOut(p.ctx, db.UpdateSurveySessionScreenedOutParams{
+		Status:  db.NullSurveysSessionsStatus{Valid: true, SurveysSessionsStatus: db.SurveysSessionsStatusScreenedOut},
+		QuotaID: pgtype.Text{Valid: true, String: quotaID},
+		Uuid:    uuid,
+	})
+}
+
+// CompleteSurveySession marks the session as completed or screens it out if one of the given quotas is full,
+// quotas are checked while the survey is locked, so concurrent sessions can't exceed them
+func (p *Postgres) CompleteSurveySession(surveyUUID string, sessionUUID string, quotas []types.Quota) (*types.Quota, error) {
+	surveyUUIDPg, err := db.DecodeUUID(surveyUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode survey UUID: %w", err)
+	}
+
+	sessionUUIDPg, err := db.DecodeUUID(sessionUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode session UUID: %w", err)
+	}
+
+	tx, err := p.begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(p.ctx)
+	}()
+
+	queries := p.queries.WithTx(tx)
+	if err := queries.LockSurvey(p.ctx, surveyUUIDPg); err != nil {
+		return nil, fmt.Errorf("failed to lock survey: %w", err)
+	}
+
+	for _, quota := range quotas {
+		count, err := queries.GetSurveyQuotaCount(p.ctx, db.GetSurveyQuotaCountParams{
+			SurveyUuid:   surveyUUIDPg,
+			QuestionID:   quota.QuestionID,
+			AnswerValues: quota.Values,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count quota %s: %w", quota.ID, err)
+		}
+
+		if int(count) >= quota.Limit {
+			if err := queries.UpdateSurveySessionScreenedOut(p.ctx, db.UpdateSurveySessionScreenedOutParams{
+				Status:  db.NullSurveysSessionsStatus{Valid: true, SurveysSessionsStatus: db.SurveysSessionsStatusScreenedOut},
+				QuotaID: pgtype.Text{Valid: true, String: quota.ID},
+				Uuid:    sessionUUIDPg,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to screen out session: %w", err)
+			}
+
+			return &quota, tx.Commit(p.ctx)
+		}
+	}
+
+	if err := queries.UpdateSurveySessionStatusCompleted(p.ctx, db.UpdateSurveySessionStatusCompletedParams{
+		Status: db.NullSurveysSessionsStatus{Valid: true, SurveysSessionsStatus: db.SurveysSessionsStatusCompleted},
+		Uuid:   sessionUUIDPg,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to complete session: %w", err)
+	}
+
+	return nil, tx.Commit(p.ctx)
+}
+
+// GetSurveyQuotaCount returns the number of completed sessions which count towards the quota
+func (p *Postgres) GetSurveyQuotaCount(surveyUUID string, quota types.Quota) (int, error) {
+	surveyUUIDPg, err := db.DecodeUUID(surveyUUID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode survey UUID: %w", err)
+	}
+
+	count, err := p.queries.GetSurveyQuotaCount(p.ctx, db.GetSurveyQuotaCountParams{
+		SurveyUuid:   surveyUUIDPg,
+		QuestionID:   quota.QuestionID,
+		AnswerValues: quota.Values,
+	})
+	return int(count), err
+}
+
+func (p *Postgres) UpdateSurveySessionDisplayOrder(sessionUUID string, order *types.SessionOrder) error {
+	sessionUUIDPg, err := db.DecodeUUID(sessionUUID)
+	if err != nil {
+		return fmt.Errorf("failed to decode session UUID: %w", err)
+	}
+
+	orderBytes, err := json.Marshal(order)
+	if err != nil {
+		return fmt.Errorf("failed to marshal display order: %w", err)
+	}
+
+	return p.queries.UpdateSurveySessionDisplayOrder(p.ctx, db.UpdateSurveySessionDisplayOrderParams{
+		DisplayOrder: orderBytes,
+		Uuid:         sessionUUIDPg,
+	})
+}
+
+func (p *Postgres) UpdateSurveySessionScore(sessionUUID string, score int, maxScore int) error {
+	sessionUUIDPg, err := db.DecodeUUID(sessionUUID)
+	if err != nil {
+		return fmt.Errorf("failed to decode session UUID: %w", err)
+	}
+
+	return p.queries.UpdateSurveySessionScore(p.ctx, db.UpdateSurveySessionScoreParams{
+		Score:    pgtype.Int4{Valid: true, Int32: int32(score)},
+		MaxScore: pgtype.Int4{Valid: true, Int32: int32(maxScore)},
+		Uuid:     sessionUUIDPg,
+	})
+}
+
+func (p *Postgres) UpdateSurveySessionVariables(sessionUUID string, variables map[string]interface{}) error {
+	sessionUUIDPg, err := db.DecodeUUID(sessionUUID)
+	if err != nil {
+		return fmt.Errorf("failed to decode session UUID: %w", err)
+	}
+
+	variablesBytes, err := json.Marshal(variables)
+	if err != nil {
+		return fmt.Errorf("failed to marshal variables: %w", err)
+	}
+
+	return p.queries.UpdateSurveySessionVariables(p.ctx, db.UpdateSurveySessionVariablesParams{
+		Variables: variablesBytes,
+		Uuid:      sessionUUIDPg,
+	})
+}
+
 func (p *Postgres) GetSurveySession(surveyUUID string, sessionUUID string) (*types.SurveySession, error) {
 	sessionUUIDPg, err := db.DecodeUUID(sessionUUID)
 	if err != nil {
@@ -340,7 +638,7 @@ func (p *Postgres) GetSurveySession(surveyUUID string, sessionUUID string) (*typ
 		Uuid_2: surveyUUIDPg,
 	})
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
@@ -352,6 +650,28 @@ func (p *Postgres) GetSurveySession(surveyUUID string, sessionUUID string) (*typ
 		CreatedAt:  row.CreatedAt.Time,
 		Status:     types.SurveySessionStatus(row.Status.SurveysSessionsStatus),
 		SurveyUUID: db.EncodeUUID(row.SurveyUuid),
+		Locale:     row.Locale.String,
+		Score:      db.EncodeInt4(row.Score),
+		MaxScore:   db.EncodeInt4(row.MaxScore),
+		QuotaID:    row.QuotaID.String,
+	}
+
+	if row.Variables != nil {
+		if err := json.Unmarshal(row.Variables, &session.Variables); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal variables: %w", err)
+		}
+	}
+
+	if row.HiddenFields != nil {
+		if err := json.Unmarshal(row.HiddenFields, &session.HiddenFields); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal hidden fields: %w", err)
+		}
+	}
+
+	if row.DisplayOrder != nil {
+		if err := json.Unmarshal(row.DisplayOrder, &session.DisplayOrder); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal display order: %w", err)
+		}
 	}
 
 	return session, nil
@@ -366,32 +686,22 @@ func (p *Postgres) DeleteSurveySession(sessionUUID string) error {
 	return p.queries.DeleteSurveySession(p.ctx, uuid)
 }
 
-func (p *Postgres) GetSurveySessionByIPAddress(surveyUUID string, ipAddr string) (*types.SurveySession, error) {
+// GetSurveySessionsCountByIPAddress counts sessions of the survey by the stored IP address hash
+func (p *Postgres) GetSurveySessionsCountByIPAddress(surveyUUID string, ipAddr string) (int, error) {
 	surveyUUIDPg, err := db.DecodeUUID(surveyUUID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode survey UUID: %w", err)
+		return 0, fmt.Errorf("failed to decode survey UUID: %w", err)
 	}
 
-	row, err := p.queries.GetSurveySessionByIPAddress(p.ctx, db.GetSurveySessionByIPAddressParams{
+	count, err := p.queries.GetSurveySessionsCountByIPAddress(p.ctx, db.GetSurveySessionsCountByIPAddressParams{
 		Uuid:   surveyUUIDPg,
 		IpAddr: pgtype.Text{Valid: true, String: ipAddr},
 	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
+		return 0, err
 	}
 
-	session := &types.SurveySession{
-		ID:         int64(row.ID),
-		UUID:       db.EncodeUUID(row.Uuid),
-		CreatedAt:  row.CreatedAt.Time,
-		Status:     types.SurveySessionStatus(row.Status.SurveysSessionsStatus),
-		SurveyUUID: db.EncodeUUID(row.SurveyUuid),
-	}
-
-	return session, nil
+	return int(count), nil
 }
 
 func (p *Postgres) GetSurveySessionAnswers(sessionUUID string) ([]types.QuestionAnswer, error) {
@@ -411,6 +721,8 @@ func (p *Postgres) GetSurveySessionAnswers(sessionUUID string) ([]types.Question
 			QuestionID:   row.QuestionID.String,
 			QuestionUUID: db.EncodeUUID(row.QuestionUuid),
 			AnswerBytes:  row.Answer,
+			Skipped:      row.Skipped,
+			Prefilled:    row.Prefilled,
 		}
 		answers = append(answers, answer)
 	}
@@ -442,16 +754,89 @@ func (p *Postgres) UpsertSurveyQuestionAnswer(sessionUUID string, questionUUID s
 	})
 }
 
+// UpsertSurveyQuestionAnswers stores answers and skips of multiple questions in a single transaction
+func (p *Postgres) UpsertSurveyQuestionAnswers(sessionUUID string, answers []types.QuestionAnswer) error {
+	sessionUUIDPg, err := db.DecodeUUID(sessionUUID)
+	if err != nil {
+		return fmt.Errorf("failed to decode session UUID: %w", err)
+	}
+
+	tx, err := p.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(p.ctx)
+	}()
+
+	queries := p.queries.WithTx(tx)
+	for _, a := range answers {
+		questionUUIDPg, err := db.DecodeUUID(a.QuestionUUID)
+		if err != nil {
+			return fmt.Errorf("failed to decode question UUID: %w", err)
+		}
+
+		var answerBytes []byte
+		if !a.Skipped {
+			answerBytes, err = json.Marshal(a.Answer)
+			if err != nil {
+				return fmt.Errorf("failed to marshal answer: %w", err)
+			}
+		}
+
+		if err := queries.UpsertSurveyQuestionAnswer(p.ctx, db.UpsertSurveyQuestionAnswerParams{
+			Uuid:      sessionUUIDPg,
+			Uuid_2:    questionUUIDPg,
+			Answer:    answerBytes,
+			Skipped:   a.Skipped,
+			Prefilled: a.Prefilled,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(p.ctx)
+}
+
+func (p *Postgres) SkipSurveyQuestion(sessionUUID string, questionUUID string) error {
+	sessionUUIDPg, err := db.DecodeUUID(sessionUUID)
+	if err != nil {
+		return fmt.Errorf("failed to decode session UUID: %w", err)
+	}
+
+	questionUUIDPg, err := db.DecodeUUID(questionUUID)
+	if err != nil {
+		return fmt.Errorf("failed to decode question UUID: %w", err)
+	}
+
+	return p.queries.UpsertSurveyQuestionAnswer(p.ctx, db.UpsertSurveyQuestionAnswerParams{
+		Uuid:    sessionUUIDPg,
+		Uuid_2:  questionUUIDPg,
+		Skipped: true,
+	})
+}
+
 func (p *Postgres) GetSurveySessionsWithAnswers(surveyUUID string, filter *types.SurveySessionsFilter) ([]types.SurveySession, int, error) {
 	surveyUUIDPg, err := db.DecodeUUID(surveyUUID)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to decode survey UUID: %w", err)
 	}
 
+	var hiddenFieldsBytes []byte
+	if len(filter.HiddenFields) > 0 {
+		hiddenFieldsBytes, err = json.Marshal(filter.HiddenFields)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to marshal hidden fields filter: %w", err)
+		}
+	}
+
 	rows, err := p.queries.GetSurveySessionsWithAnswers(p.ctx, db.GetSurveySessionsWithAnswersParams{
-		Uuid:   surveyUUIDPg,
-		Limit:  int32(filter.Limit),
-		Offset: int32(filter.Offset),
+		SortBy:       filter.SortBy,
+		SortOrder:    filter.Order,
+		SurveyUuid:   surveyUUIDPg,
+		HiddenFields: hiddenFieldsBytes,
+		PageLimit:    int32(filter.Limit),
+		PageOffset:   int32(filter.Offset),
 	})
 	if err != nil {
 		return nil, 0, err
@@ -475,12 +860,33 @@ func (p *Postgres) GetSurveySessionsWithAnswers(surveyUUID string, filter *types
 				CreatedAt:       row.CreatedAt.Time,
 				CompletedAt:     completedAt,
 				Status:          types.SurveySessionStatus(row.Status.SurveysSessionsStatus),
+				Locale:          row.Locale.String,
+				Score:           db.EncodeInt4(row.Score),
+				MaxScore:        db.EncodeInt4(row.MaxScore),
+				QuotaID:         row.QuotaID.String,
 				QuestionAnswers: []types.QuestionAnswer{},
 				WebhookData: types.WebhookData{
 					StatusCode: int16(row.ResponseStatus.Int32),
 					Response:   row.Response.String,
+					Status:     types.WebhookDeliveryStatus(row.WebhookStatus.SurveysWebhookDeliveriesStatus),
+					Attempts:   int(row.WebhookAttempts.Int32),
 				},
 			}
+			if row.DisplayOrder != nil {
+				if err := json.Unmarshal(row.DisplayOrder, &session.DisplayOrder); err != nil {
+					return nil, 0, fmt.Errorf("failed to unmarshal display order: %w", err)
+				}
+			}
+			if row.Variables != nil {
+				if err := json.Unmarshal(row.Variables, &session.Variables); err != nil {
+					return nil, 0, fmt.Errorf("failed to unmarshal variables: %w", err)
+				}
+			}
+			if row.HiddenFields != nil {
+				if err := json.Unmarshal(row.HiddenFields, &session.HiddenFields); err != nil {
+					return nil, 0, fmt.Errorf("failed to unmarshal hidden fields: %w", err)
+				}
+			}
 			sessionsMap[sessionUUID] = session
 			sessions = append(sessions, session)
 		}
@@ -490,6 +896,7 @@ func (p *Postgres) GetSurveySessionsWithAnswers(surveyUUID string, filter *types
 				QuestionID:   row.QuestionID.String,
 				QuestionUUID: db.EncodeUUID(row.QuestionUuid),
 				AnswerBytes:  row.Answer,
+				Skipped:      row.Skipped.Bool,
 			}
 
 			sessionCopy := sessionsMap[sessionUUID]
@@ -498,7 +905,7 @@ func (p *Postgres) GetSurveySessionsWithAnswers(surveyUUID string, filter *types
 		}
 	}
 
-	totalCount, err := p.getSurveySessionsCount(surveyUUID)
+	totalCount, err := p.getSurveySessionsCount(surveyUUIDPg, hiddenFieldsBytes)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -512,22 +919,252 @@ func (p *Postgres) GetSurveySessionsWithAnswers(surveyUUID string, filter *types
 	return sessions, totalCount, nil
 }
 
-func (p *Postgres) getSurveySessionsCount(surveyUUID string) (int, error) {
+func (p *Postgres) getSurveySessionsCount(surveyUUID pgtype.UUID, hiddenFields []byte) (int, error) {
+	count, err := p.queries.GetSurveySessionsCount(p.ctx, db.GetSurveySessionsCountParams{
+		SurveyUuid:   surveyUUID,
+		HiddenFields: hiddenFields,
+	})
+	return int(count), err
+}
+
+func (p *Postgres) GetSurveyAnswerValueCounts(surveyUUID string, questionIDs []string) ([]types.AnswerValueCount, error) {
 	surveyUUIDPg, err := db.DecodeUUID(surveyUUID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to decode survey UUID: %w", err)
+		return nil, fmt.Errorf("failed to decode survey UUID: %w", err)
 	}
 
-	count, err := p.queries.GetSurveySessionsCount(p.ctx, surveyUUIDPg)
-	return int(count), err
+	rows, err := p.queries.GetSurveyAnswerValueCounts(p.ctx, db.GetSurveyAnswerValueCountsParams{
+		Uuid:    surveyUUIDPg,
+		Column2: questionIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := []types.AnswerValueCount{}
+	for _, row := range rows {
+		counts = append(counts, types.AnswerValueCount{
+			QuestionID: row.QuestionID,
+			Value:      row.AnswerValue,
+			Count:      int(row.AnswersCount),
+		})
+	}
+
+	return counts, nil
 }
 
-func (p *Postgres) StoreWebhookResponse(sessionId int, responseStatus int, response string) error {
-	now := time.Now().UTC()
+// StoreWebhookResponse records the attempt of the delivery
+func (p *Postgres) StoreWebhookResponse(delivery *types.WebhookDelivery, attempt types.WebhookAttempt) error {
 	return p.queries.StoreWebhookResponse(p.ctx, db.StoreWebhookResponseParams{
-		CreatedAt:      pgtype.Timestamp{Time: now, Valid: true},
-		SessionID:      int32(sessionId),
-		ResponseStatus: int32(responseStatus),
-		Response:       pgtype.Text{String: response, Valid: true},
+		CreatedAt:      pgtype.Timestamp{Time: attempt.CreatedAt.UTC(), Valid: true},
+		SessionID:      pgtype.Int4{Int32: int32(delivery.SessionID), Valid: delivery.SessionID != 0},
+		ResponseStatus: int32(attempt.ResponseStatus),
+		Response:       pgtype.Text{String: attempt.Response, Valid: true},
+		DeliveryID:     pgtype.Int4{Int32: int32(delivery.ID), Valid: true},
+		Attempt:        pgtype.Int4{Int32: int32(attempt.Attempt), Valid: true},
+		Error:          pgtype.Text{String: attempt.Error, Valid: attempt.Error != ""},
+	})
+}
+
+func (p *Postgres) CreateWebhookDelivery(delivery *types.WebhookDelivery) error {
+	surveyUUID, err := db.DecodeUUID(delivery.SurveyUUID)
+	if err != nil {
+		return fmt.Errorf("failed to decode survey UUID: %w", err)
+	}
+
+	var headersBytes []byte
+	if len(delivery.Headers) > 0 {
+		headersBytes, err = json.Marshal(delivery.Headers)
+		if err != nil {
+			return fmt.Errorf("failed to marshal headers: %w", err)
+		}
+	}
+
+	// session is empty for survey events
+	row, err := p.queries.CreateWebhookDelivery(p.ctx, db.CreateWebhookDeliveryParams{
+		Uuid:        surveyUUID,
+		SessionID:   pgtype.Int4{Int32: int32(delivery.SessionID), Valid: delivery.SessionID != 0},
+		EventType:   string(delivery.EventType),
+		Url:         delivery.URL,
+		Method:      delivery.Method,
+		Payload:     delivery.Payload,
+		MaxAttempts: int32(delivery.MaxAttempts),
+		SecretEnv:   pgtype.Text{String: delivery.SecretEnv, Valid: delivery.SecretEnv != ""},
+		Headers:     headersBytes,
+	})
+	if err != nil {
+		return err
+	}
+
+	delivery.ID = int64(row.ID)
+	delivery.UUID = db.EncodeUUID(row.Uuid)
+	delivery.CreatedAt = row.CreatedAt.Time
+	delivery.NextAttemptAt = row.NextAttemptAt.Time
+	delivery.Status = types.WebhookDeliveryStatus_Pending
+	return nil
+}
+
+// ClaimWebhookDeliveries returns pending deliveries due at now and postpones them until leaseUntil,
+// so they are not picked up by another worker while being delivered
+func (p *Postgres) ClaimWebhookDeliveries(now time.Time, leaseUntil time.Time, limit int) ([]types.WebhookDelivery, error) {
+	rows, err := p.queries.ClaimWebhookDeliveries(p.ctx, db.ClaimWebhookDeliveriesParams{
+		NextAttemptAt: pgtype.Timestamp{Time: leaseUntil.UTC(), Valid: true},
+		Now:           pgtype.Timestamp{Time: now.UTC(), Valid: true},
+		ClaimLimit:    int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []types.WebhookDelivery{}
+	for _, row := range rows {
+		delivery := types.WebhookDelivery{
+			ID:            int64(row.ID),
+			UUID:          db.EncodeUUID(row.Uuid),
+			CreatedAt:     row.CreatedAt.Time,
+			SessionID:     int64(row.SessionID.Int32),
+			EventType:     types.WebhookEventType(row.EventType),
+			URL:           row.Url,
+			Method:        row.Method,
+			Payload:       row.Payload,
+			Status:        types.WebhookDeliveryStatus(row.Status),
+			Attempts:      int(row.Attempts),
+			MaxAttempts:   int(row.MaxAttempts),
+			SecretEnv:     row.SecretEnv.String,
+			NextAttemptAt: leaseUntil,
+		}
+		if row.Headers != nil {
+			if err := json.Unmarshal(row.Headers, &delivery.Headers); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal headers: %w", err)
+			}
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (p *Postgres) UpdateWebhookDelivery(delivery *types.WebhookDelivery) error {
+	deliveredAt := pgtype.Timestamp{}
+	if delivery.DeliveredAt != nil {
+		deliveredAt = pgtype.Timestamp{Time: delivery.DeliveredAt.UTC(), Valid: true}
+	}
+
+	return p.queries.UpdateWebhookDelivery(p.ctx, db.UpdateWebhookDeliveryParams{
+		Status:        db.SurveysWebhookDeliveriesStatus(delivery.Status),
+		Attempts:      int32(delivery.Attempts),
+		NextAttemptAt: pgtype.Timestamp{Time: delivery.NextAttemptAt.UTC(), Valid: true},
+		DeliveredAt:   deliveredAt,
+		ID:            int32(delivery.ID),
+	})
+}
+
+func (p *Postgres) GetWebhookDeliveries(surveyUUID string, filter *types.WebhookDeliveriesFilter) ([]types.WebhookDelivery, error) {
+	return p.getWebhookDeliveries(surveyUUID, "", filter)
+}
+
+// GetWebhookDelivery returns nil if the delivery doesn't exist
+func (p *Postgres) GetWebhookDelivery(surveyUUID string, deliveryUUID string) (*types.WebhookDelivery, error) {
+	deliveries, err := p.getWebhookDeliveries(surveyUUID, deliveryUUID, &types.WebhookDeliveriesFilter{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	return &deliveries[0], nil
+}
+
+// getWebhookDeliveries returns deliveries with their attempts log, newest first
+func (p *Postgres) getWebhookDeliveries(surveyUUID string, deliveryUUID string, filter *types.WebhookDeliveriesFilter) ([]types.WebhookDelivery, error) {
+	surveyUUIDPg, err := db.DecodeUUID(surveyUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode survey UUID: %w", err)
+	}
+
+	rows, err := p.queries.GetWebhookDeliveries(p.ctx, db.GetWebhookDeliveriesParams{
+		SurveyUuid:     surveyUUIDPg,
+		DeliveryUuid:   deliveryUUID,
+		SessionUuid:    filter.SessionUUID,
+		Status:         string(filter.Status),
+		ResponseStatus: int32(filter.ResponseStatus),
+		PageLimit:      int32(filter.Limit),
+		PageOffset:     int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []types.WebhookDelivery{}
+	ids := []int32{}
+	for _, row := range rows {
+		delivery := types.WebhookDelivery{
+			ID:            int64(row.ID),
+			UUID:          db.EncodeUUID(row.Uuid),
+			CreatedAt:     row.CreatedAt.Time,
+			SurveyUUID:    surveyUUID,
+			EventType:     types.WebhookEventType(row.EventType),
+			URL:           row.Url,
+			Method:        row.Method,
+			Status:        types.WebhookDeliveryStatus(row.Status),
+			Attempts:      int(row.Attempts),
+			MaxAttempts:   int(row.MaxAttempts),
+			NextAttemptAt: row.NextAttemptAt.Time,
+			Responses:     []types.WebhookAttempt{},
+		}
+		if row.SessionUuid.Valid {
+			delivery.SessionUUID = db.EncodeUUID(row.SessionUuid)
+		}
+		if row.DeliveredAt.Valid {
+			deliveredAt := row.DeliveredAt.Time
+			delivery.DeliveredAt = &deliveredAt
+		}
+
+		deliveries = append(deliveries, delivery)
+		ids = append(ids, row.ID)
+	}
+
+	if len(ids) == 0 {
+		return deliveries, nil
+	}
+
+	responses, err := p.queries.GetWebhookDeliveryResponses(p.ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, response := range responses {
+		for i := range deliveries {
+			if deliveries[i].ID != int64(response.DeliveryID.Int32) {
+				continue
+			}
+
+			deliveries[i].Responses = append(deliveries[i].Responses, types.WebhookAttempt{
+				CreatedAt:      response.CreatedAt.Time,
+				Attempt:        int(response.Attempt.Int32),
+				ResponseStatus: int(response.ResponseStatus),
+				Response:       response.Response.String,
+				Error:          response.Error.String,
+			})
+			break
+		}
+	}
+
+	return deliveries, nil
+}
+
+// RedeliverWebhookDelivery queues the delivery again, returns false if it's pending already
+func (p *Postgres) RedeliverWebhookDelivery(delivery *types.WebhookDelivery) (bool, error) {
+	rows, err := p.queries.RedeliverWebhookDelivery(p.ctx, db.RedeliverWebhookDeliveryParams{
+		MaxAttempts:   int32(delivery.MaxAttempts),
+		NextAttemptAt: pgtype.Timestamp{Time: delivery.NextAttemptAt.UTC(), Valid: true},
+		ID:            int32(delivery.ID),
 	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
 }
@@ -1,8 +1,10 @@
 package surveys
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/plutov/formulosity/api/pkg/services"
 	"github.com/plutov/formulosity/api/pkg/types"
@@ -13,11 +15,349 @@ func SubmitAnswer(svc services.Services, session *types.SurveySession, survey *t
 	logCtx := svc.Logger.With("session_uuid", session.UUID)
 	logCtx.Info("submitting answer")
 
-	answer, err := question.GetAnswerType()
+	if !isQuestionVisible(survey, session, question) {
+		return errors.New("question is not available"), nil
+	}
+
+	// validate against the question with piped answers, e.g. options from a previous question
+	pipedQuestion := question.PipeAnswers(session.AnswersByQuestionID(), survey.Config)
+	question = &pipedQuestion
+
+	var answer types.Answer
+	skip := file == nil && isSkipRequest(req)
+	if skip {
+		if question.IsRequired() {
+			return errors.New("question is required"), nil
+		}
+	} else {
+		var mainErr, detailsErr error
+		answer, mainErr, detailsErr = parseAnswer(svc, question, req, file)
+		if mainErr != nil {
+			return mainErr, detailsErr
+		}
+	}
+
+	if locked, ok := lockedAnswer(survey, session, question.UUID); ok {
+		if !sameAnswer(answer, locked.Answer) {
+			return errors.New("prefilled answer can't be changed"), nil
+		}
+		return nil, nil
+	}
+
+	// the answer, the session state and webhook events are stored in one transaction
+	err := withTx(svc, func(svc services.Services) error {
+		if skip {
+			if err := svc.Storage.SkipSurveyQuestion(session.UUID, question.UUID); err != nil {
+				msg := "unable to skip question"
+				logCtx.Error(msg, "err", err)
+				return errors.New(msg)
+			}
+
+			logCtx.Info("question skipped")
+		} else {
+			if err := svc.Storage.UpsertSurveyQuestionAnswer(session.UUID, question.UUID, answer); err != nil {
+				msg := "unable to insert answer"
+				logCtx.Error(msg, "err", err)
+				return errors.New(msg)
+			}
+
+			logCtx.Info("answer submitted")
+		}
+
+		session.SetAnswer(*question, answer)
+
+		return answersSubmitted(svc, survey, session, []string{question.ID})
+	})
 	if err != nil {
 		return err, nil
 	}
 
+	return nil, nil
+}
+
+// returns 2 errors: general and error details
+// all answers of the page are validated before any of them is stored
+func SubmitPageAnswers(svc services.Services, session *types.SurveySession, survey *types.Survey, page *types.Page, req map[string]json.RawMessage) (error, error) {
+	logCtx := svc.Logger.With("session_uuid", session.UUID, "page_id", page.ID)
+	logCtx.Info("submitting page answers")
+
+	answers := []types.QuestionAnswer{}
+	submitted := make(map[string]bool)
+	for _, questionID := range page.Questions {
+		question, err := survey.Config.FindQuestionByID(questionID)
+		if err != nil {
+			return err, nil
+		}
+
+		questionReq, ok := req[question.UUID]
+		submitted[question.UUID] = true
+
+		// answers are applied one by one, so conditions within the same page are respected
+		if !isQuestionVisible(survey, session, question) {
+			if ok {
+				return errors.New("invalid answers"), fmt.Errorf("%s: question is not available", question.UUID)
+			}
+			continue
+		}
+
+		pipedQuestion := question.PipeAnswers(session.AnswersByQuestionID(), survey.Config)
+		question = &pipedQuestion
+
+		var answer types.Answer
+		if !ok || isSkipRequest(questionReq) {
+			if question.IsRequired() {
+				return errors.New("invalid answers"), fmt.Errorf("%s: question is required", question.UUID)
+			}
+		} else {
+			var mainErr, detailsErr error
+			answer, mainErr, detailsErr = parseAnswer(svc, question, questionReq, nil)
+			if mainErr != nil {
+				if detailsErr != nil {
+					return errors.New("invalid answers"), fmt.Errorf("%s: %s: %w", question.UUID, mainErr.Error(), detailsErr)
+				}
+				return errors.New("invalid answers"), fmt.Errorf("%s: %w", question.UUID, mainErr)
+			}
+		}
+
+		// the prefilled answer is kept if it's not submitted or submitted with the same value
+		if locked, isLocked := lockedAnswer(survey, session, question.UUID); isLocked {
+			if ok && !sameAnswer(answer, locked.Answer) {
+				return errors.New("invalid answers"), fmt.Errorf("%s: prefilled answer can't be changed", question.UUID)
+			}
+			continue
+		}
+
+		session.SetAnswer(*question, answer)
+		answers = append(answers, types.QuestionAnswer{
+			QuestionID:   question.ID,
+			QuestionUUID: question.UUID,
+			Answer:       answer,
+			Skipped:      answer == nil,
+		})
+	}
+
+	for questionUUID := range req {
+		if !submitted[questionUUID] {
+			return errors.New("invalid answers"), fmt.Errorf("%s: question is not on this page", questionUUID)
+		}
+	}
+
+	err := withTx(svc, func(svc services.Services) error {
+		if err := svc.Storage.UpsertSurveyQuestionAnswers(session.UUID, answers); err != nil {
+			msg := "unable to insert answers"
+			logCtx.Error(msg, "err", err)
+			return errors.New(msg)
+		}
+
+		logCtx.Info("page answers submitted")
+
+		questionIDs := []string{}
+		for _, a := range answers {
+			questionIDs = append(questionIDs, a.QuestionID)
+		}
+
+		return answersSubmitted(svc, survey, session, questionIDs)
+	})
+	if err != nil {
+		return err, nil
+	}
+
+	return nil, nil
+}
+
+// answersSubmitted updates the session state after answers to given questions are stored,
+// the session is screened out or completed if it's finished
+func answersSubmitted(svc services.Services, survey *types.Survey, session *types.SurveySession, questionIDs []string) error {
+	if err := updateSessionVariables(svc, survey, session); err != nil {
+		return err
+	}
+
+	if err := emitSessionEvent(svc, survey, types.WebhookEvent_AnswerSubmitted, session.UUID, questionIDs); err != nil {
+		return err
+	}
+
+	if screenedOut, err := screenOutSession(svc, survey, session); err != nil || screenedOut {
+		return err
+	}
+
+	return completeSession(svc, survey, session)
+}
+
+// lockedAnswer returns the answer prefilled from a signed survey URL, respondents can't change it
+func lockedAnswer(survey *types.Survey, session *types.SurveySession, questionUUID string) (types.QuestionAnswer, bool) {
+	if survey.Config.Prefill() != types.PrefillType_Signed {
+		return types.QuestionAnswer{}, false
+	}
+
+	for _, qa := range session.QuestionAnswers {
+		if qa.QuestionUUID == questionUUID && qa.Prefilled {
+			return qa, true
+		}
+	}
+
+	return types.QuestionAnswer{}, false
+}
+
+// sameAnswer reports whether answers have the same value, nil answers are skipped questions
+func sameAnswer(a types.Answer, b types.Answer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	aBytes, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bBytes, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(aBytes, bBytes)
+}
+
+// updateSessionVariables evaluates computed variables with the current answers of the session
+func updateSessionVariables(svc services.Services, survey *types.Survey, session *types.SurveySession) error {
+	variables := survey.Config.ComputeVariables(session.AnswersByQuestionID())
+	if variables == nil {
+		return nil
+	}
+
+	if err := svc.Storage.UpdateSurveySessionVariables(session.UUID, variables); err != nil {
+		msg := "unable to update session variables"
+		svc.Logger.With("session_uuid", session.UUID).Error(msg, "err", err)
+		return errors.New(msg)
+	}
+
+	session.Variables = variables
+
+	return nil
+}
+
+// completeSession marks session as completed if there are no more unanswered or skipped questions
+func completeSession(svc services.Services, survey *types.Survey, session *types.SurveySession) error {
+	if !isSessionCompleted(survey, session) {
+		return nil
+	}
+
+	logCtx := svc.Logger.With("session_uuid", session.UUID)
+
+	if survey.Config.Questions.IsQuiz() {
+		score, maxScore := survey.Config.Questions.Score(session.AnswersByQuestionID())
+		if err := svc.Storage.UpdateSurveySessionScore(session.UUID, score, maxScore); err != nil {
+			msg := "unable to update session score"
+			logCtx.Error(msg, "err", err)
+			return errors.New(msg)
+		}
+
+		session.Score = &score
+		session.MaxScore = &maxScore
+		logCtx.Info("session scored", "score", score, "max_score", maxScore)
+	}
+
+	// quotas are checked again with the completion, as other sessions may have completed meanwhile
+	quota, err := svc.Storage.CompleteSurveySession(survey.UUID, session.UUID, survey.Config.MatchingQuotas(session.AnswersByQuestionID()))
+	if err != nil {
+		msg := "unable to update session status"
+		logCtx.Error(msg, "err", err)
+		return errors.New(msg)
+	}
+
+	if quota != nil {
+		session.Status = types.SurveySessionStatus_ScreenedOut
+		session.QuotaID = quota.ID
+		logCtx.Info("session screened out", "quota_id", quota.ID)
+		return nil
+	}
+
+	session.Status = types.SurveySessionStatus_Completed
+	logCtx.Info("session completed")
+
+	if err := emitSessionEvent(svc, survey, types.WebhookEvent_SessionCompleted, session.UUID, nil); err != nil {
+		return err
+	}
+
+	return stopSurveyOnMaxResponses(svc, survey)
+}
+
+// screenOutSession ends the session if one of the quotas matching its answers is already full
+func screenOutSession(svc services.Services, survey *types.Survey, session *types.SurveySession) (bool, error) {
+	logCtx := svc.Logger.With("session_uuid", session.UUID)
+
+	for _, quota := range survey.Config.MatchingQuotas(session.AnswersByQuestionID()) {
+		count, err := svc.Storage.GetSurveyQuotaCount(survey.UUID, quota)
+		if err != nil {
+			msg := "unable to check quotas"
+			logCtx.Error(msg, "err", err)
+			return false, errors.New(msg)
+		}
+		if count < quota.Limit {
+			continue
+		}
+
+		if err := svc.Storage.ScreenOutSurveySession(session.UUID, quota.ID); err != nil {
+			msg := "unable to update session status"
+			logCtx.Error(msg, "err", err)
+			return false, errors.New(msg)
+		}
+
+		session.Status = types.SurveySessionStatus_ScreenedOut
+		session.QuotaID = quota.ID
+		logCtx.Info("session screened out", "quota_id", quota.ID)
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// stopSurveyOnMaxResponses stops the survey once it has the maximum number of completed responses
+func stopSurveyOnMaxResponses(svc services.Services, survey *types.Survey) error {
+	if survey.Config.MaxResponses == 0 {
+		return nil
+	}
+
+	count, err := svc.Storage.GetSurveyQuotaCount(survey.UUID, types.Quota{ID: types.QuotaID_MaxResponses})
+	if err != nil {
+		msg := "unable to count survey responses"
+		svc.Logger.With("survey_uuid", survey.UUID).Error(msg, "err", err)
+		return errors.New(msg)
+	}
+	if count < survey.Config.MaxResponses {
+		return nil
+	}
+
+	survey.DeliveryStatus = types.SurveyDeliveryStatus_Stopped
+	if err := UpdateSurvey(svc, survey); err != nil {
+		return err
+	}
+
+	svc.Logger.With("survey_uuid", survey.UUID).Info("survey stopped on maximum number of responses")
+
+	return nil
+}
+
+type skipAnswerReq struct {
+	Skip bool `json:"skip"`
+}
+
+// isSkipRequest reports whether the request explicitly skips an optional question
+func isSkipRequest(req []byte) bool {
+	skipReq := skipAnswerReq{}
+	if err := json.Unmarshal(req, &skipReq); err != nil {
+		return false
+	}
+
+	return skipReq.Skip
+}
+
+// returns answer and 2 errors: general and error details
+func parseAnswer(svc services.Services, question *types.Question, req []byte, file *types.File) (types.Answer, error, error) {
+	answer, err := question.GetAnswerType()
+	if err != nil {
+		return nil, err, nil
+	}
+
 	switch a := answer.(type) {
 	case *types.FileAnswer:
 		if file != nil {
@@ -25,70 +365,91 @@ func SubmitAnswer(svc services.Services, session *types.SurveySession, survey *t
 			a.FileFormat = file.Format
 
 			if err := answer.Validate(*question); err != nil {
-				return errors.New("invalid answer"), err
+				return nil, errors.New("invalid answer"), err
 			}
 
 			filePath, err := svc.FileStorage.SaveFile(file)
 			if err != nil {
-				return errors.New("unable to save file"), nil
+				return nil, errors.New("unable to save file"), nil
 			}
 			a.AnswerValue = filePath
 		} else {
-			return errors.New("file is required for this question type"), nil
+			return nil, errors.New("file is required for this question type"), nil
 		}
 	default:
 		if err := json.Unmarshal(req, &answer); err != nil {
-			return errors.New("invalid request format"), nil
+			return nil, errors.New("invalid request format"), nil
 		}
 
 		if err := answer.Validate(*question); err != nil {
-			return errors.New("invalid answer"), err
+			return nil, errors.New("invalid answer"), err
 		}
 	}
 
-	if err := svc.Storage.UpsertSurveyQuestionAnswer(session.UUID, question.UUID, answer); err != nil {
-		msg := "unable to insert answer"
-		logCtx.Error(msg, "err", err)
-		return errors.New(msg), nil
+	return answer, nil, nil
+}
+
+func isSessionCompleted(survey *types.Survey, session *types.SurveySession) bool {
+	if session.Status == types.SurveySessionStatus_Completed {
+		return true
 	}
 
-	logCtx.Info("answer submitted")
+	answers := session.AnswersByQuestionID()
+	for _, q := range survey.Config.Questions.VisibleQuestions(answers) {
+		if _, ok := answers[q.ID]; !ok {
+			return false
+		}
+	}
 
-	// mark session as completed if there are no more unanswered questions
-	isCompleted := isSessionCompleted(survey, session, question)
+	return true
+}
 
-	if isCompleted {
-		session.Status = types.SurveySessionStatus_Completed
-		if err := svc.Storage.UpdateSurveySessionStatus(session.UUID, session.Status); err != nil {
-			msg := "unable to update session status"
-			logCtx.Error(msg, "err", err)
-			return nil, errors.New(msg)
+func isQuestionVisible(survey *types.Survey, session *types.SurveySession, question *types.Question) bool {
+	for _, q := range survey.Config.Questions.VisibleQuestions(session.AnswersByQuestionID()) {
+		if q.UUID == question.UUID {
+			return true
 		}
-
-		logCtx.Info("session completed")
 	}
 
-	return nil, nil
+	return false
 }
 
-func isSessionCompleted(survey *types.Survey, session *types.SurveySession, question *types.Question) bool {
-	if session.Status == types.SurveySessionStatus_Completed {
-		return true
-	}
+// setSessionProgress exposes the questions visible for the current answers with piped answers,
+// and the first unanswered one
+func setSessionProgress(survey *types.Survey, session *types.SurveySession) {
+	answers := session.AnswersByQuestionID()
+
+	session.VisibleQuestions = []string{}
+	session.NextQuestionUUID = ""
+	session.NextPageID = ""
+	session.Questions = []types.Question{}
+
+	// questions are returned in the locale chosen when the session was created
+	config := survey.Config.Localize(session.Locale)
+
+	// conditions are evaluated in the order of questions.yaml, then questions are shown in the session order
+	visibleQuestions := config.Questions.VisibleQuestions(answers)
+	for _, q := range session.DisplayOrder.OrderQuestions(visibleQuestions) {
+		session.VisibleQuestions = append(session.VisibleQuestions, q.UUID)
+		question := session.DisplayOrder.OrderOptions(q.PipeAnswers(answers, config))
+		session.Questions = append(session.Questions, question.HideCorrect())
+
+		if _, ok := answers[q.ID]; !ok && session.NextQuestionUUID == "" {
+			session.NextQuestionUUID = q.UUID
 
-	for _, q := range survey.Config.Questions.Questions {
-		hasAnswer := q.UUID == question.UUID
-		for _, a := range session.QuestionAnswers {
-			if q.UUID == a.QuestionUUID {
-				hasAnswer = true
-				break
+			if page, err := config.Questions.FindPageByQuestionID(q.ID); err == nil {
+				session.NextPageID = page.ID
 			}
 		}
+	}
 
-		if !hasAnswer {
-			return false
-		}
+	if session.Status == types.SurveySessionStatus_Completed && session.Score != nil && len(config.ScoreOutros) > 0 {
+		session.Outro = config.OutroForScore(*session.Score)
 	}
 
-	return true
+	if session.Status == types.SurveySessionStatus_ScreenedOut {
+		session.NextQuestionUUID = ""
+		session.NextPageID = ""
+		session.Outro = config.QuotaMessage(session.QuotaID)
+	}
 }
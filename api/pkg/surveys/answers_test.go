@@ -0,0 +1,70 @@
+package surveys
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/plutov/formulosity/api/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSubmitAnswerPrefilled(t *testing.T) {
+	optional := false
+
+	cases := []struct {
+		name      string
+		prefill   types.PrefillType
+		req       string
+		expect    func(m *MockInterface)
+		expectErr error
+	}{
+		{
+			name:      "should not change answer prefilled from signed URL",
+			prefill:   types.PrefillType_Signed,
+			req:       `{"value":"b"}`,
+			expectErr: errors.New("prefilled answer can't be changed"),
+		},
+		{
+			name:      "should not skip answer prefilled from signed URL",
+			prefill:   types.PrefillType_Signed,
+			req:       `{"skip":true}`,
+			expectErr: errors.New("prefilled answer can't be changed"),
+		},
+		{
+			name:    "should accept the same answer prefilled from signed URL",
+			prefill: types.PrefillType_Signed,
+			req:     `{"value":"a"}`,
+		},
+		{
+			name:    "should change answer prefilled from unsigned URL",
+			prefill: types.PrefillType_Unsigned,
+			req:     `{"value":"b"}`,
+			expect: func(m *MockInterface) {
+				m.EXPECT().UpsertSurveyQuestionAnswer("session-uuid", "q1-uuid", mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, m := newTestServices(t)
+			survey := newTestSurvey()
+			survey.Config.Security = &types.Security{Prefill: c.prefill}
+			survey.Config.Questions.Questions[0].Required = &optional
+			if c.expect != nil {
+				c.expect(m)
+			}
+
+			session := &types.SurveySession{
+				UUID:            "session-uuid",
+				Status:          types.SurveySessionStatus_InProgress,
+				QuestionAnswers: []types.QuestionAnswer{prefilledAnswer("q1", "a")},
+			}
+			question := survey.Config.Questions.Questions[0]
+
+			err, _ := SubmitAnswer(svc, session, survey, &question, []byte(c.req), nil)
+			assert.Equal(t, c.expectErr, err)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package surveys
+
+import (
+	"errors"
+
+	"github.com/plutov/formulosity/api/pkg/services"
+	"github.com/plutov/formulosity/api/pkg/types"
+)
+
+// CreateSurveyInvitations generates a token for each respondent,
+// respondents who are already invited are skipped
+func CreateSurveyInvitations(svc services.Services, survey *types.Survey, invitations []types.Invitation) ([]types.Invitation, error) {
+	logCtx := svc.Logger.With("survey_uuid", survey.UUID, "invitations", len(invitations))
+	logCtx.Info("creating survey invitations")
+
+	for i := range invitations {
+		token, err := types.NewInvitationToken()
+		if err != nil {
+			msg := "unable to generate invitation token"
+			logCtx.Error(msg, "err", err)
+			return nil, errors.New(msg)
+		}
+		invitations[i].Token = token
+	}
+
+	created, err := svc.Storage.CreateSurveyInvitations(survey.UUID, invitations)
+	if err != nil {
+		msg := "unable to create survey invitations"
+		logCtx.Error(msg, "err", err)
+		return nil, errors.New(msg)
+	}
+
+	logCtx.Info("survey invitations created", "created", len(created))
+
+	return created, nil
+}
+
+func GetSurveyInvitations(svc services.Services, survey *types.Survey) ([]types.Invitation, error) {
+	logCtx := svc.Logger.With("survey_uuid", survey.UUID)
+
+	invitations, err := svc.Storage.GetSurveyInvitations(survey.UUID)
+	if err != nil {
+		msg := "unable to get survey invitations"
+		logCtx.Error(msg, "err", err)
+		return nil, errors.New(msg)
+	}
+
+	return invitations, nil
+}
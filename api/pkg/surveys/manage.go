@@ -5,11 +5,20 @@ import (
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"github.com/plutov/formulosity/api/pkg/services"
+	"github.com/plutov/formulosity/api/pkg/storage"
 	"github.com/plutov/formulosity/api/pkg/types"
 )
 
 const URL_SLUG_LENGTH = 12
 
+// withTx calls fn with services using a storage transaction, so its changes are stored together
+func withTx(svc services.Services, fn func(svc services.Services) error) error {
+	return svc.Storage.WithTx(func(tx storage.Interface) error {
+		svc.Storage = tx
+		return fn(svc)
+	})
+}
+
 func CreateSurvey(svc services.Services, survey *types.Survey) error {
 	logCtx := svc.Logger.With("survey", *survey)
 	logCtx.Info("creating survey")
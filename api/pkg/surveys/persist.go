@@ -2,6 +2,7 @@ package surveys
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/plutov/formulosity/api/pkg/services"
 	"github.com/plutov/formulosity/api/pkg/types"
@@ -76,6 +77,10 @@ func PersistSurveysSyncResult(svc services.Services, syncResult *types.SurveysSy
 
 		surveyCopy.ParseStatus = types.SurveyParseStatus_Success
 		surveyCopy.DeliveryStatus = types.SurveyDeliveryStatus_Launched
+		// launched by the scheduler at opensAt
+		if surveyCopy.Config.ScheduleStatus(time.Now()) == types.SurveyScheduleStatus_NotYetOpen {
+			surveyCopy.DeliveryStatus = types.SurveyDeliveryStatus_Stopped
+		}
 		surveyCopy.ErrorLog = ""
 		surveysToCreate = append(surveysToCreate, &surveyCopy)
 	}
@@ -103,9 +108,16 @@ func PersistSurveysSyncResult(svc services.Services, syncResult *types.SurveysSy
 	}
 
 	// create surveys
+	// surveys are stored together with their webhook events
 	for _, survey := range surveysToCreate {
 		surveyToCreate := *survey
-		if err := CreateSurvey(svc, &surveyToCreate); err != nil {
+		err := withTx(svc, func(svc services.Services) error {
+			if err := CreateSurvey(svc, &surveyToCreate); err != nil {
+				return err
+			}
+			return emitSurveyEvent(svc, nil, &surveyToCreate)
+		})
+		if err != nil {
 			return err
 		}
 	}
@@ -113,7 +125,18 @@ func PersistSurveysSyncResult(svc services.Services, syncResult *types.SurveysSy
 	// update surveys
 	for _, survey := range surveysToUpdate {
 		surveyToUpdate := *survey
-		if err := UpdateSurvey(svc, &surveyToUpdate); err != nil {
+		err := withTx(svc, func(svc services.Services) error {
+			if err := UpdateSurvey(svc, &surveyToUpdate); err != nil {
+				return err
+			}
+			for _, currSurvey := range currSurveys {
+				if currSurvey.UUID == surveyToUpdate.UUID {
+					return emitSurveyEvent(svc, currSurvey, &surveyToUpdate)
+				}
+			}
+			return nil
+		})
+		if err != nil {
 			return err
 		}
 	}
@@ -122,3 +145,32 @@ func PersistSurveysSyncResult(svc services.Services, syncResult *types.SurveysSy
 
 	return nil
 }
+
+// emitSurveyEvent notifies subscribed endpoints when the survey is synced with changes or fails to parse,
+// so the same sync result doesn't emit events on every restart, prev is nil for new surveys
+func emitSurveyEvent(svc services.Services, prev *types.Survey, survey *types.Survey) error {
+	data := types.SurveyEventData{
+		ParseStatus:    survey.ParseStatus,
+		DeliveryStatus: survey.DeliveryStatus,
+		ErrorLog:       survey.ErrorLog,
+	}
+	if survey.Config != nil {
+		data.Hash = survey.Config.Hash
+	}
+
+	switch survey.ParseStatus {
+	case types.SurveyParseStatus_Success:
+		if prev != nil && prev.ParseStatus == types.SurveyParseStatus_Success && prev.Config != nil && prev.Config.Hash == data.Hash {
+			return nil
+		}
+		return EmitWebhookEvent(svc, survey, types.WebhookEvent_SurveySynced, 0, data)
+	case types.SurveyParseStatus_Error:
+		if prev != nil && prev.ParseStatus == types.SurveyParseStatus_Error && prev.ErrorLog == survey.ErrorLog {
+			return nil
+		}
+		// endpoints of the last valid config are used, as the new one can't be parsed
+		return EmitWebhookEvent(svc, survey, types.WebhookEvent_SurveyParseFailed, 0, data)
+	}
+
+	return nil
+}
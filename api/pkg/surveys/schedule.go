@@ -0,0 +1,77 @@
+package surveys
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/plutov/formulosity/api/pkg/services"
+	"github.com/plutov/formulosity/api/pkg/types"
+)
+
+const scheduleInterval = time.Minute
+
+// RunScheduler switches delivery status of surveys when their opensAt or closesAt time has passed
+func RunScheduler(svc services.Services) {
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		if err := ApplySchedule(svc, time.Now()); err != nil {
+			svc.Logger.Error("unable to apply surveys schedule", "err", err)
+		}
+	}
+}
+
+// ApplySchedule applies every scheduled transition once, so a survey stopped or launched manually
+// afterwards is not switched back until the next transition
+func ApplySchedule(svc services.Services, now time.Time) error {
+	logCtx := svc.Logger.With("func", "ApplySchedule")
+
+	surveys, err := svc.Storage.GetSurveys()
+	if err != nil {
+		msg := "unable to get surveys"
+		logCtx.Error(msg, "err", err)
+		return errors.New(msg)
+	}
+
+	for _, survey := range surveys {
+		if survey.ParseStatus != types.SurveyParseStatus_Success || survey.Config == nil {
+			continue
+		}
+
+		status, scheduledAt, ok := survey.Config.ScheduledTransition(now)
+		if !ok {
+			continue
+		}
+
+		surveyLogCtx := logCtx.With("survey_uuid", survey.UUID, "delivery_status", status, "scheduled_at", scheduledAt)
+
+		// the transition is recorded together with the status, so it's retried if the update fails
+		changed := false
+		err := withTx(svc, func(svc services.Services) error {
+			created, err := svc.Storage.CreateSurveyDeliveryTransition(survey.ID, status, scheduledAt)
+			if err != nil {
+				return fmt.Errorf("unable to record delivery transition: %w", err)
+			}
+			if !created || survey.DeliveryStatus == status {
+				return nil
+			}
+
+			survey.DeliveryStatus = status
+			changed = true
+			return UpdateSurvey(svc, survey)
+		})
+		if err != nil {
+			surveyLogCtx.Error("unable to apply scheduled transition", "err", err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		surveyLogCtx.Info("survey delivery status changed by schedule")
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package surveys
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plutov/formulosity/api/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestApplySchedule(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	opensAt := now.Add(-48 * time.Hour)
+	closesAt := now.Add(-time.Hour)
+	// delivery status constants are untyped, the mock matches arguments by type
+	stopped := types.SurveyDeliveryStatus(types.SurveyDeliveryStatus_Stopped)
+
+	isStopped := mock.MatchedBy(func(s *types.Survey) bool {
+		return s.DeliveryStatus == types.SurveyDeliveryStatus_Stopped
+	})
+
+	cases := []struct {
+		name         string
+		status       types.SurveyDeliveryStatus
+		expect       func(m *MockInterface)
+		expectStatus types.SurveyDeliveryStatus
+	}{
+		{
+			name:   "should stop survey when closesAt has passed",
+			status: types.SurveyDeliveryStatus_Launched,
+			expect: func(m *MockInterface) {
+				m.EXPECT().CreateSurveyDeliveryTransition(int64(1), stopped, closesAt).Return(true, nil)
+				m.EXPECT().UpdateSurvey(isStopped).Return(nil)
+				m.EXPECT().UpsertSurveyQuestions(isStopped).Return(nil)
+			},
+			expectStatus: types.SurveyDeliveryStatus_Stopped,
+		},
+		{
+			name:   "should not switch survey back if transition was already applied",
+			status: types.SurveyDeliveryStatus_Launched,
+			expect: func(m *MockInterface) {
+				m.EXPECT().CreateSurveyDeliveryTransition(int64(1), stopped, closesAt).Return(false, nil)
+			},
+			expectStatus: types.SurveyDeliveryStatus_Launched,
+		},
+		{
+			name:   "should not update survey which is already stopped",
+			status: types.SurveyDeliveryStatus_Stopped,
+			expect: func(m *MockInterface) {
+				m.EXPECT().CreateSurveyDeliveryTransition(int64(1), stopped, closesAt).Return(true, nil)
+			},
+			expectStatus: types.SurveyDeliveryStatus_Stopped,
+		},
+		{
+			name:   "should not update survey if transition can't be recorded",
+			status: types.SurveyDeliveryStatus_Launched,
+			expect: func(m *MockInterface) {
+				m.EXPECT().CreateSurveyDeliveryTransition(int64(1), stopped, closesAt).Return(false, errors.New("db error"))
+			},
+			expectStatus: types.SurveyDeliveryStatus_Launched,
+		},
+		{
+			name:   "should roll back transition if survey can't be updated",
+			status: types.SurveyDeliveryStatus_Launched,
+			expect: func(m *MockInterface) {
+				m.EXPECT().CreateSurveyDeliveryTransition(int64(1), stopped, closesAt).Return(true, nil)
+				m.EXPECT().UpdateSurvey(isStopped).Return(errors.New("db error"))
+			},
+			expectStatus: types.SurveyDeliveryStatus_Stopped,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, m := newTestServices(t)
+
+			survey := newTestSurvey()
+			survey.DeliveryStatus = c.status
+			survey.Config.OpensAt = &opensAt
+			survey.Config.ClosesAt = &closesAt
+
+			// surveys which failed to parse or have no schedule are skipped
+			failed := newTestSurvey()
+			failed.ID = 2
+			failed.ParseStatus = types.SurveyParseStatus_Error
+			unscheduled := newTestSurvey()
+			unscheduled.ID = 3
+
+			m.EXPECT().GetSurveys().Return([]*types.Survey{survey, failed, unscheduled}, nil)
+			c.expect(m)
+
+			assert.NoError(t, ApplySchedule(svc, now))
+			assert.Equal(t, c.expectStatus, survey.DeliveryStatus)
+		})
+	}
+}
+
+func TestApplyScheduleError(t *testing.T) {
+	svc, m := newTestServices(t)
+	m.EXPECT().GetSurveys().Return(nil, errors.New("db error"))
+
+	assert.EqualError(t, ApplySchedule(svc, time.Now()), "unable to get surveys")
+}
@@ -1,47 +1,209 @@
 package surveys
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"time"
 
 	"github.com/plutov/formulosity/api/pkg/services"
 	"github.com/plutov/formulosity/api/pkg/types"
 )
 
-func CreateSurveySession(svc services.Services, survey *types.Survey, ipAddr string) (*types.SurveySession, error) {
+// prefilled answers must be validated with ParsePrefilledAnswers
+func CreateSurveySession(svc services.Services, survey *types.Survey, ipAddr string, locale string, hiddenFields map[string]string, prefilled []types.QuestionAnswer, invitationToken string) (*types.SurveySession, error) {
+	// salted hash of the address is stored, so sessions can be counted by address without keeping it
+	ipAddrHash := ""
+	if ipAddr != "" && svc.IPHashSalt != "" {
+		ipAddrHash = types.HashIPAddress(survey.UUID, survey.Config.IPAddressKey(ipAddr), svc.IPHashSalt)
+	}
+
 	session := &types.SurveySession{
-		Status:     types.SurveySessionStatus_InProgress,
-		SurveyUUID: survey.UUID,
-		IPAddr:     ipAddr,
+		Status:       types.SurveySessionStatus_InProgress,
+		SurveyUUID:   survey.UUID,
+		IPAddr:       ipAddrHash,
+		Locale:       locale,
+		HiddenFields: hiddenFields,
 	}
 
 	logCtx := svc.Logger.With("session", *session)
 	logCtx.Info("creating survey session")
 
-	if ipAddr != "" && survey.Config.Security.DuplicateProtection == types.DuplicateProtectionType_Ip {
-		if ipAddrSession, _ := svc.Storage.GetSurveySessionByIPAddress(survey.UUID, ipAddr); ipAddrSession != nil {
+	if survey.Config.DuplicateProtection() == types.DuplicateProtectionType_Invite {
+		if invitationToken == "" {
+			msg := "invitation token is required"
+			logCtx.Error(msg)
+			return nil, errors.New(msg)
+		}
+		session.InvitationToken = invitationToken
+	}
+
+	if ipAddr != "" && survey.Config.DuplicateProtection() == types.DuplicateProtectionType_Ip && !survey.Config.IsIPAddressAllowlisted(ipAddr) {
+		if ipAddrHash == "" {
+			msg := "ip hash salt is not set"
+			logCtx.Error(msg)
+			return nil, errors.New(msg)
+		}
+
+		count, err := svc.Storage.GetSurveySessionsCountByIPAddress(survey.UUID, session.IPAddr)
+		if err != nil {
+			msg := "unable to count sessions for ip address"
+			logCtx.Error(msg, "err", err)
+			return nil, errors.New(msg)
+		}
+		if count >= survey.Config.MaxSessionsPerIPAddress() {
 			msg := "duplicate session for ip address"
 			logCtx.Error(msg)
 			return nil, errors.New(msg)
 		}
 	}
 
-	if err := svc.Storage.CreateSurveySession(session); err != nil {
-		msg := "unable to create survey session"
+	if survey.Config.MaxResponses > 0 {
+		count, err := svc.Storage.GetSurveyQuotaCount(survey.UUID, types.Quota{ID: types.QuotaID_MaxResponses})
+		if err != nil {
+			msg := "unable to count survey responses"
+			logCtx.Error(msg, "err", err)
+			return nil, errors.New(msg)
+		}
+		if count >= survey.Config.MaxResponses {
+			logCtx.Info("survey has maximum number of responses")
+			return nil, errors.New(survey.Config.QuotaMessage(types.QuotaID_MaxResponses))
+		}
+	}
+
+	// the session, the invitation, prefilled answers and webhook events are stored in one transaction,
+	// so a failure doesn't leave a session without answers or use up the invitation
+	err := withTx(svc, func(svc services.Services) error {
+		if err := svc.Storage.CreateSurveySession(session); err != nil {
+			if errors.Is(err, types.ErrInvitationNotFound) {
+				logCtx.Error(err.Error())
+				return err
+			}
+
+			msg := "unable to create survey session"
+			logCtx.Error(msg, "err", err)
+			return errors.New(msg)
+		}
+
+		// order is seeded by the session UUID, so it's stable when the session is resumed
+		if order := types.NewSessionOrder(survey.Config.Questions, session.UUID); order != nil {
+			if err := svc.Storage.UpdateSurveySessionDisplayOrder(session.UUID, order); err != nil {
+				msg := "unable to store session display order"
+				logCtx.Error(msg, "err", err)
+				return errors.New(msg)
+			}
+			session.DisplayOrder = order
+		}
+
+		// emitted before prefilled answers, which may complete the session
+		if err := emitSessionEvent(svc, survey, types.WebhookEvent_SessionStarted, session.UUID, nil); err != nil {
+			return err
+		}
+
+		if len(prefilled) > 0 {
+			if err := svc.Storage.UpsertSurveyQuestionAnswers(session.UUID, prefilled); err != nil {
+				msg := "unable to store prefilled answers"
+				logCtx.Error(msg, "err", err)
+				return errors.New(msg)
+			}
+			session.QuestionAnswers = append(session.QuestionAnswers, prefilled...)
+
+			if err := updateSessionVariables(svc, survey, session); err != nil {
+				return err
+			}
+			screenedOut, err := screenOutSession(svc, survey, session)
+			if err != nil {
+				return err
+			}
+			if !screenedOut {
+				if err := completeSession(svc, survey, session); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logCtx.With("session_uuid", session.UUID).Info("survey session created")
+
+	return session, nil
+}
+
+// ResumeSurveySession returns the session the respondent has already started,
+// it's an error if the session is not in progress anymore and nil if the session was deleted
+func ResumeSurveySession(svc services.Services, survey *types.Survey, sessionUUID string) (*types.SurveySession, error) {
+	logCtx := svc.Logger.With("survey_uuid", survey.UUID, "session_uuid", sessionUUID)
+	logCtx.Info("resuming survey session")
+
+	existing, err := svc.Storage.GetSurveySession(survey.UUID, sessionUUID)
+	if err != nil {
+		msg := "unable to get survey session"
 		logCtx.Error(msg, "err", err)
 		return nil, errors.New(msg)
 	}
 
-	logCtx.With("session_uuid", session.UUID).Info("survey session created")
+	if existing == nil {
+		return nil, nil
+	}
+
+	session, err := GetSurveySession(svc, *survey, sessionUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Status != types.SurveySessionStatus_InProgress {
+		logCtx.Error(types.ErrDuplicateSession.Error())
+		return nil, types.ErrDuplicateSession
+	}
 
 	return session, nil
 }
 
+// ParsePrefilledAnswers validates prefilled values keyed by question ID the same way as submitted answers,
+// returns answers and 2 errors: general and error details
+func ParsePrefilledAnswers(svc services.Services, survey *types.Survey, values map[string][]string) ([]types.QuestionAnswer, error, error) {
+	answers := []types.QuestionAnswer{}
+	if len(values) == 0 {
+		return answers, nil, nil
+	}
+
+	// answers are applied in the order of questions, so conditions and piping are respected
+	session := &types.SurveySession{}
+	for _, q := range survey.Config.Questions.Questions {
+		questionValues, ok := values[q.ID]
+		if !ok {
+			continue
+		}
+
+		if !isQuestionVisible(survey, session, &q) {
+			return nil, errors.New("invalid prefilled answers"), fmt.Errorf("%s: question is not available", q.ID)
+		}
+
+		question := q.PipeAnswers(session.AnswersByQuestionID(), survey.Config)
+		answer, err := question.PrefillAnswer(questionValues)
+		if err != nil {
+			return nil, errors.New("invalid prefilled answers"), fmt.Errorf("%s: %w", q.ID, err)
+		}
+		if err := answer.Validate(question); err != nil {
+			return nil, errors.New("invalid prefilled answers"), fmt.Errorf("%s: %w", q.ID, err)
+		}
+
+		qa := types.QuestionAnswer{
+			QuestionID:   question.ID,
+			QuestionUUID: question.UUID,
+			Answer:       answer,
+			Prefilled:    true,
+		}
+		session.QuestionAnswers = append(session.QuestionAnswers, qa)
+		answers = append(answers, qa)
+	}
+
+	return answers, nil, nil
+}
+
 func GetSurveySession(svc services.Services, survey types.Survey, sessionUUID string) (*types.SurveySession, error) {
 	logCtx := svc.Logger.With("survey_uuid", survey.UUID, "session_uuid", sessionUUID)
 	logCtx.Info("getting survey session")
@@ -66,6 +228,9 @@ func GetSurveySession(svc services.Services, survey types.Survey, sessionUUID st
 	session.QuestionAnswers = answers
 
 	session.QuestionAnswers = convertAnswerBytesToAnswerType(svc, &survey, session.QuestionAnswers)
+	session.SetFlatAnswers(survey.Config.Questions.Questions)
+
+	setSessionProgress(&survey, session)
 
 	return session, nil
 }
@@ -85,9 +250,18 @@ func DeleteSurveySession(svc services.Services, survey types.Survey, sessionUUID
 		return nil, errors.New("session not found")
 	}
 
-	if err := svc.Storage.DeleteSurveySession(session.UUID); err != nil {
-		msg := "unable to delete session"
-		logCtx.Error(msg, "err", err)
+	err = withTx(svc, func(svc services.Services) error {
+		if err := svc.Storage.DeleteSurveySession(session.UUID); err != nil {
+			msg := "unable to delete session"
+			logCtx.Error(msg, "err", err)
+			return errors.New(msg)
+		}
+
+		// session doesn't exist anymore, so the delivery is not linked to it
+		return EmitWebhookEvent(svc, &survey, types.WebhookEvent_SessionDeleted, 0, types.NewWebhookSession(survey.Config, session))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return session, nil
@@ -95,6 +269,11 @@ func DeleteSurveySession(svc services.Services, survey types.Survey, sessionUUID
 
 func convertAnswerBytesToAnswerType(svc services.Services, survey *types.Survey, answers []types.QuestionAnswer) []types.QuestionAnswer {
 	for i, a := range answers {
+		// skipped questions have no answer
+		if a.Skipped {
+			continue
+		}
+
 		for _, q := range survey.Config.Questions.Questions {
 			if q.UUID == a.QuestionUUID {
 				answerType, err := q.GetAnswerType()
@@ -128,6 +307,7 @@ func GetSurveySessions(svc services.Services, survey types.Survey, filter *types
 
 	for i, s := range sessions {
 		sessions[i].QuestionAnswers = convertAnswerBytesToAnswerType(svc, &survey, s.QuestionAnswers)
+		sessions[i].SetFlatAnswers(survey.Config.Questions.Questions)
 	}
 
 	pagesCount := totalCount / filter.Limit
@@ -135,38 +315,31 @@ func GetSurveySessions(svc services.Services, survey types.Survey, filter *types
 	return sessions, pagesCount, nil
 }
 
-func CallWebhook(svc services.Services, survey *types.Survey, session *types.SurveySession) error {
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+// GetSurveyNPSStats returns scores of every nps question of the survey
+func GetSurveyNPSStats(svc services.Services, survey types.Survey) ([]types.NPSStats, error) {
+	logCtx := svc.Logger.With("survey_uuid", survey.UUID)
 
-	data, err := json.Marshal(session)
-	if err != nil {
-		return fmt.Errorf("invalid post data, err: %v", err)
+	questionIDs := []string{}
+	for _, q := range survey.Config.Questions.Questions {
+		if q.Type == types.QuestionType_NPS {
+			questionIDs = append(questionIDs, q.ID)
+		}
 	}
-
-	req, err := http.NewRequest(survey.Config.Webhook.Method, survey.Config.Webhook.URL, bytes.NewBuffer(data))
-	if err != nil {
-		return fmt.Errorf("invalid http request, err: %v", err)
+	if len(questionIDs) == 0 {
+		return nil, nil
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
+	counts, err := svc.Storage.GetSurveyAnswerValueCounts(survey.UUID, questionIDs)
 	if err != nil {
-		return fmt.Errorf("error making request, err: %v", err)
+		msg := "unable to get nps stats"
+		logCtx.Error(msg, "err", err)
+		return nil, errors.New(msg)
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			svc.Logger.Error("unable to close body", "err", err)
-		}
-	}()
 
-	statusCode := resp.StatusCode
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		responseBody = []byte{}
+	stats := []types.NPSStats{}
+	for _, questionID := range questionIDs {
+		stats = append(stats, types.NewNPSStats(questionID, counts))
 	}
 
-	return svc.Storage.StoreWebhookResponse(int(session.ID), statusCode, string(responseBody))
+	return stats, nil
 }
@@ -0,0 +1,304 @@
+package surveys
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/plutov/formulosity/api/pkg/services"
+	"github.com/plutov/formulosity/api/pkg/storage"
+	"github.com/plutov/formulosity/api/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestServices returns services with the storage mock, transactions run fn with the same mock
+func newTestServices(t *testing.T) (services.Services, *MockInterface) {
+	m := NewMockInterface(t)
+	m.EXPECT().WithTx(mock.Anything).RunAndReturn(func(fn func(tx storage.Interface) error) error {
+		return fn(m)
+	}).Maybe()
+
+	return services.Services{
+		Storage: m,
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}, m
+}
+
+func newTestSurvey() *types.Survey {
+	return &types.Survey{
+		ID:          1,
+		UUID:        "survey-uuid",
+		ParseStatus: types.SurveyParseStatus_Success,
+		Config: &types.SurveyConfig{
+			Title: "Survey",
+			Questions: &types.Questions{
+				Questions: []types.Question{
+					{ID: "q1", UUID: "q1-uuid", Type: types.QuestionType_DropdownSingle, Options: []string{"a", "b"}},
+					{ID: "q2", UUID: "q2-uuid", Type: types.QuestionType_ShortText},
+				},
+			},
+		},
+	}
+}
+
+func prefilledAnswer(questionID string, value string) types.QuestionAnswer {
+	return types.QuestionAnswer{
+		QuestionID:   questionID,
+		QuestionUUID: questionID + "-uuid",
+		Answer:       &types.SingleOptionAnswer{AnswerValue: value},
+		Prefilled:    true,
+	}
+}
+
+func expectCreateSession(m *MockInterface, err error) {
+	m.EXPECT().CreateSurveySession(mock.Anything).RunAndReturn(func(session *types.SurveySession) error {
+		if err != nil {
+			return err
+		}
+		session.ID = 1
+		session.UUID = "session-uuid"
+		return nil
+	})
+}
+
+func TestCreateSurveySession(t *testing.T) {
+	cases := []struct {
+		name            string
+		survey          func(s *types.Survey)
+		prefilled       []types.QuestionAnswer
+		invitationToken string
+		expect          func(m *MockInterface)
+		expectErr       error
+		expectStatus    types.SurveySessionStatus
+	}{
+		{
+			name: "should create session",
+			expect: func(m *MockInterface) {
+				expectCreateSession(m, nil)
+			},
+			expectStatus: types.SurveySessionStatus_InProgress,
+		},
+		{
+			name: "should store prefilled answers with the session",
+			prefilled: []types.QuestionAnswer{
+				prefilledAnswer("q1", "a"),
+			},
+			expect: func(m *MockInterface) {
+				expectCreateSession(m, nil)
+				m.EXPECT().UpsertSurveyQuestionAnswers("session-uuid", []types.QuestionAnswer{prefilledAnswer("q1", "a")}).Return(nil)
+			},
+			expectStatus: types.SurveySessionStatus_InProgress,
+		},
+		{
+			name: "should fail if prefilled answers can't be stored",
+			prefilled: []types.QuestionAnswer{
+				prefilledAnswer("q1", "a"),
+			},
+			expect: func(m *MockInterface) {
+				expectCreateSession(m, nil)
+				m.EXPECT().UpsertSurveyQuestionAnswers("session-uuid", mock.Anything).Return(errors.New("db error"))
+			},
+			expectErr: errors.New("unable to store prefilled answers"),
+		},
+		{
+			name: "should complete session if all questions are prefilled",
+			prefilled: []types.QuestionAnswer{
+				prefilledAnswer("q1", "a"),
+				{QuestionID: "q2", QuestionUUID: "q2-uuid", Answer: &types.TextAnswer{AnswerValue: "text"}, Prefilled: true},
+			},
+			expect: func(m *MockInterface) {
+				expectCreateSession(m, nil)
+				m.EXPECT().UpsertSurveyQuestionAnswers("session-uuid", mock.Anything).Return(nil)
+				m.EXPECT().CompleteSurveySession("survey-uuid", "session-uuid", []types.Quota{}).Return(nil, nil)
+			},
+			expectStatus: types.SurveySessionStatus_Completed,
+		},
+		{
+			name: "should screen out session if prefilled answer hits a full quota",
+			survey: func(s *types.Survey) {
+				s.Config.Quotas = []types.Quota{{ID: "quota-a", QuestionID: "q1", Values: []string{"a"}, Limit: 10}}
+			},
+			prefilled: []types.QuestionAnswer{
+				prefilledAnswer("q1", "a"),
+			},
+			expect: func(m *MockInterface) {
+				expectCreateSession(m, nil)
+				m.EXPECT().UpsertSurveyQuestionAnswers("session-uuid", mock.Anything).Return(nil)
+				m.EXPECT().GetSurveyQuotaCount("survey-uuid", mock.MatchedBy(func(q types.Quota) bool { return q.ID == "quota-a" })).Return(10, nil)
+				m.EXPECT().ScreenOutSurveySession("session-uuid", "quota-a").Return(nil)
+			},
+			expectStatus: types.SurveySessionStatus_ScreenedOut,
+		},
+		{
+			name: "should not screen out session if quota is not full",
+			survey: func(s *types.Survey) {
+				s.Config.Quotas = []types.Quota{{ID: "quota-a", QuestionID: "q1", Values: []string{"a"}, Limit: 10}}
+			},
+			prefilled: []types.QuestionAnswer{
+				prefilledAnswer("q1", "a"),
+			},
+			expect: func(m *MockInterface) {
+				expectCreateSession(m, nil)
+				m.EXPECT().UpsertSurveyQuestionAnswers("session-uuid", mock.Anything).Return(nil)
+				m.EXPECT().GetSurveyQuotaCount("survey-uuid", mock.MatchedBy(func(q types.Quota) bool { return q.ID == "quota-a" })).Return(9, nil)
+			},
+			expectStatus: types.SurveySessionStatus_InProgress,
+		},
+		{
+			name: "should not create session if survey has maximum number of responses",
+			survey: func(s *types.Survey) {
+				s.Config.MaxResponses = 5
+			},
+			expect: func(m *MockInterface) {
+				m.EXPECT().GetSurveyQuotaCount("survey-uuid", types.Quota{ID: types.QuotaID_MaxResponses}).Return(5, nil)
+			},
+			expectErr: errors.New("Thank you for your interest, we have already collected enough responses."),
+		},
+		{
+			name: "should create session with invitation",
+			survey: func(s *types.Survey) {
+				s.Config.Security = &types.Security{DuplicateProtection: types.DuplicateProtectionType_Invite}
+			},
+			invitationToken: "token",
+			expect: func(m *MockInterface) {
+				m.EXPECT().CreateSurveySession(mock.MatchedBy(func(s *types.SurveySession) bool {
+					return s.InvitationToken == "token"
+				})).RunAndReturn(func(session *types.SurveySession) error {
+					session.UUID = "session-uuid"
+					return nil
+				})
+			},
+			expectStatus: types.SurveySessionStatus_InProgress,
+		},
+		{
+			name: "should require invitation token",
+			survey: func(s *types.Survey) {
+				s.Config.Security = &types.Security{DuplicateProtection: types.DuplicateProtectionType_Invite}
+			},
+			expect:    func(m *MockInterface) {},
+			expectErr: errors.New("invitation token is required"),
+		},
+		{
+			name: "should not store prefilled answers if invitation is already used",
+			survey: func(s *types.Survey) {
+				s.Config.Security = &types.Security{DuplicateProtection: types.DuplicateProtectionType_Invite}
+			},
+			prefilled: []types.QuestionAnswer{
+				prefilledAnswer("q1", "a"),
+			},
+			invitationToken: "token",
+			expect: func(m *MockInterface) {
+				expectCreateSession(m, types.ErrInvitationNotFound)
+			},
+			expectErr: types.ErrInvitationNotFound,
+		},
+		{
+			name: "should fail if webhook delivery can't be stored",
+			survey: func(s *types.Survey) {
+				s.Config.Webhooks = []types.WebhookConfig{
+					{URL: "https://example.com/hook", Method: "POST", Events: []types.WebhookEventType{types.WebhookEvent_SessionStarted}},
+				}
+			},
+			expect: func(m *MockInterface) {
+				expectCreateSession(m, nil)
+				m.EXPECT().GetSurveySession("survey-uuid", "session-uuid").Return(&types.SurveySession{ID: 1, UUID: "session-uuid"}, nil)
+				m.EXPECT().GetSurveySessionAnswers("session-uuid").Return(nil, nil)
+				m.EXPECT().CreateWebhookDelivery(mock.Anything).Return(errors.New("db error"))
+			},
+			expectErr: errors.New("unable to create webhook delivery"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, m := newTestServices(t)
+			survey := newTestSurvey()
+			if c.survey != nil {
+				c.survey(survey)
+			}
+			c.expect(m)
+
+			session, err := CreateSurveySession(svc, survey, "", "", nil, c.prefilled, c.invitationToken)
+			if c.expectErr != nil {
+				assert.Equal(t, c.expectErr, err)
+				assert.Nil(t, session)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "session-uuid", session.UUID)
+			assert.Equal(t, c.expectStatus, session.Status)
+		})
+	}
+}
+
+func TestCreateSurveySessionIPProtection(t *testing.T) {
+	svc, m := newTestServices(t)
+	survey := newTestSurvey()
+	survey.Config.Security = &types.Security{DuplicateProtection: types.DuplicateProtectionType_Ip}
+
+	_, err := CreateSurveySession(svc, survey, "127.0.0.1", "", nil, nil, "")
+	assert.EqualError(t, err, "ip hash salt is not set")
+
+	svc.IPHashSalt = "salt"
+	m.EXPECT().GetSurveySessionsCountByIPAddress("survey-uuid", mock.MatchedBy(func(ipAddr string) bool {
+		return ipAddr != "" && ipAddr != "127.0.0.1"
+	})).Return(1, nil)
+
+	_, err = CreateSurveySession(svc, survey, "127.0.0.1", "", nil, nil, "")
+	assert.EqualError(t, err, "duplicate session for ip address")
+}
+
+func TestResumeSurveySession(t *testing.T) {
+	cases := []struct {
+		name          string
+		expect        func(m *MockInterface)
+		expectErr     error
+		expectSession bool
+	}{
+		{
+			name: "should resume session in progress",
+			expect: func(m *MockInterface) {
+				session := &types.SurveySession{UUID: "session-uuid", Status: types.SurveySessionStatus_InProgress}
+				m.EXPECT().GetSurveySession("survey-uuid", "session-uuid").Return(session, nil)
+				m.EXPECT().GetSurveySessionAnswers("session-uuid").Return(nil, nil)
+			},
+			expectSession: true,
+		},
+		{
+			name: "should return nil if session was deleted",
+			expect: func(m *MockInterface) {
+				m.EXPECT().GetSurveySession("survey-uuid", "session-uuid").Return(nil, nil)
+			},
+		},
+		{
+			name: "should return storage error",
+			expect: func(m *MockInterface) {
+				m.EXPECT().GetSurveySession("survey-uuid", "session-uuid").Return(nil, errors.New("db error"))
+			},
+			expectErr: errors.New("unable to get survey session"),
+		},
+		{
+			name: "should not resume completed session",
+			expect: func(m *MockInterface) {
+				session := &types.SurveySession{UUID: "session-uuid", Status: types.SurveySessionStatus_Completed}
+				m.EXPECT().GetSurveySession("survey-uuid", "session-uuid").Return(session, nil)
+				m.EXPECT().GetSurveySessionAnswers("session-uuid").Return(nil, nil)
+			},
+			expectErr: types.ErrDuplicateSession,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, m := newTestServices(t)
+			c.expect(m)
+
+			session, err := ResumeSurveySession(svc, newTestSurvey(), "session-uuid")
+			assert.Equal(t, c.expectErr, err)
+			assert.Equal(t, c.expectSession, session != nil)
+		})
+	}
+}
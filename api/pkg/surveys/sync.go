@@ -7,6 +7,7 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/plutov/formulosity/api/pkg/parser"
 	"github.com/plutov/formulosity/api/pkg/services"
+	"github.com/plutov/formulosity/api/pkg/types"
 )
 
 func SyncSurveysOnChange(svc services.Services) {
@@ -49,6 +50,16 @@ func SyncSurveys(svc services.Services) error {
 	}
 
 	logCtx.With("surveys_count", len(syncResult.Surveys)).With("errors", len(syncResult.Errors)).Info("synced")
+
+	// hashes of IP addresses with a known or empty salt can be reversed by brute force
+	if svc.IPHashSalt == "" {
+		for _, survey := range syncResult.Surveys {
+			if survey.Config.DuplicateProtection() == types.DuplicateProtectionType_Ip {
+				logCtx.Error("IP_HASH_SALT is not set", "survey", survey.Name)
+				return fmt.Errorf("IP_HASH_SALT is required by survey %s with ip duplicate protection", survey.Name)
+			}
+		}
+	}
 	logCtx.Info("persisting sync result")
 
 	err = PersistSurveysSyncResult(svc, syncResult)
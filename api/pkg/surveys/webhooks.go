@@ -0,0 +1,267 @@
+package surveys
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/plutov/formulosity/api/pkg/services"
+	"github.com/plutov/formulosity/api/pkg/types"
+	"github.com/plutov/formulosity/api/pkg/webhooks"
+)
+
+const (
+	webhookWorkerInterval = 5 * time.Second
+	webhookTimeout        = 10 * time.Second
+	webhookClaimLimit     = 10
+	// claimed deliveries are retried after the lease if the worker stops while delivering them
+	webhookClaimLease  = 5 * time.Minute
+	maxWebhookResponse = 64 << 10
+)
+
+// EmitWebhookEvent stores a delivery of the event for every endpoint subscribed to it in the outbox,
+// deliveries are sent by the webhook worker, session ID is empty for survey events.
+// It must be called within the transaction of the change, so the event is stored only together with it.
+// Endpoints with a payload which can't be rendered are skipped and logged, they don't stop other endpoints.
+func EmitWebhookEvent(svc services.Services, survey *types.Survey, eventType types.WebhookEventType, sessionID int64, data interface{}) error {
+	endpoints := survey.Config.WebhookEndpoints(eventType)
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	logCtx := svc.Logger.With("survey_uuid", survey.UUID, "event_type", eventType)
+
+	eventID, err := gonanoid.New()
+	if err != nil {
+		msg := "unable to generate webhook event id"
+		logCtx.Error(msg, "err", err)
+		return errors.New(msg)
+	}
+
+	event := types.NewWebhookEvent(eventID, eventType, survey, data, time.Now())
+
+	for _, endpoint := range endpoints {
+		endpointLogCtx := logCtx.With("url", endpoint.URL)
+
+		// payload is rendered once, so all attempts send the same body
+		payload, err := endpoint.RenderPayload(event)
+		if err != nil {
+			endpointLogCtx.Error("unable to render webhook payload", "err", err)
+			continue
+		}
+
+		delivery := &types.WebhookDelivery{
+			SurveyUUID:  survey.UUID,
+			SessionID:   sessionID,
+			EventType:   eventType,
+			URL:         endpoint.URL,
+			Method:      endpoint.Method,
+			Payload:     payload,
+			MaxAttempts: endpoint.GetMaxAttempts(),
+			SecretEnv:   endpoint.SecretEnv(),
+			Headers:     endpoint.Headers,
+		}
+		if err := svc.Storage.CreateWebhookDelivery(delivery); err != nil {
+			msg := "unable to create webhook delivery"
+			endpointLogCtx.Error(msg, "err", err)
+			return errors.New(msg)
+		}
+
+		endpointLogCtx.Info("webhook delivery created", "delivery_uuid", delivery.UUID, "event_id", eventID)
+	}
+
+	return nil
+}
+
+// emitSessionEvent stores the current state of the session for subscribed endpoints,
+// it's called within the transaction which changes the session
+func emitSessionEvent(svc services.Services, survey *types.Survey, eventType types.WebhookEventType, sessionUUID string, questionIDs []string) error {
+	if len(survey.Config.WebhookEndpoints(eventType)) == 0 {
+		return nil
+	}
+
+	session, err := GetSurveySession(svc, *survey, sessionUUID)
+	if err != nil {
+		return err
+	}
+
+	var data interface{} = types.NewWebhookSession(survey.Config, session)
+	if eventType == types.WebhookEvent_AnswerSubmitted {
+		data = types.AnswerSubmittedData{
+			Session:     types.NewWebhookSession(survey.Config, session),
+			QuestionIDs: questionIDs,
+		}
+	}
+
+	return EmitWebhookEvent(svc, survey, eventType, session.ID, data)
+}
+
+// RunWebhookWorker delivers pending webhooks from the outbox
+func RunWebhookWorker(svc services.Services) {
+	ticker := time.NewTicker(webhookWorkerInterval)
+	defer ticker.Stop()
+
+	client := &http.Client{
+		Timeout: webhookTimeout,
+	}
+
+	for ; true; <-ticker.C {
+		if err := DeliverWebhooks(svc, client, time.Now()); err != nil {
+			svc.Logger.Error("unable to deliver webhooks", "err", err)
+		}
+	}
+}
+
+// DeliverWebhooks makes one attempt of every delivery due at now,
+// failed deliveries are retried with exponential backoff until max attempts
+func DeliverWebhooks(svc services.Services, client *http.Client, now time.Time) error {
+	logCtx := svc.Logger.With("func", "DeliverWebhooks")
+
+	deliveries, err := svc.Storage.ClaimWebhookDeliveries(now, now.Add(webhookClaimLease), webhookClaimLimit)
+	if err != nil {
+		msg := "unable to claim webhook deliveries"
+		logCtx.Error(msg, "err", err)
+		return errors.New(msg)
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		deliveryLogCtx := logCtx.With("delivery_uuid", delivery.UUID, "attempt", delivery.Attempts+1)
+
+		attempt := callWebhook(svc, client, delivery)
+		attempt.Attempt = delivery.Attempts + 1
+		if err := svc.Storage.StoreWebhookResponse(delivery, attempt); err != nil {
+			deliveryLogCtx.Error("unable to store webhook response", "err", err)
+		}
+
+		delivery.RecordAttempt(attempt)
+		if err := svc.Storage.UpdateWebhookDelivery(delivery); err != nil {
+			deliveryLogCtx.Error("unable to update webhook delivery", "err", err)
+			continue
+		}
+
+		switch delivery.Status {
+		case types.WebhookDeliveryStatus_Delivered:
+			deliveryLogCtx.Info("webhook delivered")
+		case types.WebhookDeliveryStatus_Dead:
+			deliveryLogCtx.Error("webhook delivery failed, max attempts reached", "status", attempt.ResponseStatus, "err", attempt.Error)
+		default:
+			deliveryLogCtx.Warn("webhook delivery failed, will retry", "status", attempt.ResponseStatus, "err", attempt.Error, "next_attempt_at", delivery.NextAttemptAt)
+		}
+	}
+
+	return nil
+}
+
+func GetWebhookDeliveries(svc services.Services, survey *types.Survey, filter *types.WebhookDeliveriesFilter) ([]types.WebhookDelivery, error) {
+	logCtx := svc.Logger.With("survey_uuid", survey.UUID)
+
+	deliveries, err := svc.Storage.GetWebhookDeliveries(survey.UUID, filter)
+	if err != nil {
+		msg := "unable to get webhook deliveries"
+		logCtx.Error(msg, "err", err)
+		return nil, errors.New(msg)
+	}
+
+	return deliveries, nil
+}
+
+// RedeliverWebhook queues a delivered or dead delivery again with the same payload,
+// it gets max attempts of the endpoint, or the default if the endpoint is not in the survey config anymore
+func RedeliverWebhook(svc services.Services, survey *types.Survey, deliveryUUID string) (*types.WebhookDelivery, error) {
+	logCtx := svc.Logger.With("survey_uuid", survey.UUID, "delivery_uuid", deliveryUUID)
+	logCtx.Info("redelivering webhook")
+
+	delivery, err := svc.Storage.GetWebhookDelivery(survey.UUID, deliveryUUID)
+	if err != nil {
+		msg := "unable to get webhook delivery"
+		logCtx.Error(msg, "err", err)
+		return nil, errors.New(msg)
+	}
+	if delivery == nil {
+		return nil, types.ErrWebhookDeliveryNotFound
+	}
+	if delivery.Status == types.WebhookDeliveryStatus_Pending {
+		return nil, types.ErrWebhookDeliveryPending
+	}
+
+	maxAttempts := types.DefaultWebhookMaxAttempts
+	for _, endpoint := range survey.Config.WebhookEndpoints(delivery.EventType) {
+		if endpoint.URL == delivery.URL {
+			maxAttempts = endpoint.GetMaxAttempts()
+			break
+		}
+	}
+
+	delivery.Redeliver(time.Now(), maxAttempts)
+	queued, err := svc.Storage.RedeliverWebhookDelivery(delivery)
+	if err != nil {
+		msg := "unable to redeliver webhook"
+		logCtx.Error(msg, "err", err)
+		return nil, errors.New(msg)
+	}
+	// the delivery was redelivered concurrently
+	if !queued {
+		return nil, types.ErrWebhookDeliveryPending
+	}
+
+	logCtx.Info("webhook queued for redelivery", "max_attempts", delivery.MaxAttempts)
+
+	return delivery, nil
+}
+
+func callWebhook(svc services.Services, client *http.Client, delivery *types.WebhookDelivery) types.WebhookAttempt {
+	attempt := types.WebhookAttempt{
+		CreatedAt: time.Now(),
+	}
+
+	req, err := http.NewRequest(delivery.Method, delivery.URL, bytes.NewBuffer(delivery.Payload))
+	if err != nil {
+		attempt.Error = "invalid http request: " + err.Error()
+		return attempt
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	headers, err := types.ResolveWebhookHeaders(delivery.Headers)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	for _, h := range headers {
+		req.Header.Set(h.Name, h.Value)
+	}
+
+	// secret is resolved on every attempt, so it's never stored and can be rotated
+	if delivery.SecretEnv != "" {
+		secret := os.Getenv(delivery.SecretEnv)
+		if secret == "" {
+			attempt.Error = "webhook secret env var is not set: " + delivery.SecretEnv
+			return attempt
+		}
+		webhooks.SetHeaders(req.Header, secret, attempt.CreatedAt, delivery.Payload)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		attempt.Error = "error making request: " + err.Error()
+		return attempt
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			svc.Logger.Error("unable to close body", "err", err)
+		}
+	}()
+
+	attempt.ResponseStatus = resp.StatusCode
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponse))
+	if err == nil {
+		attempt.Response = string(responseBody)
+	}
+
+	return attempt
+}
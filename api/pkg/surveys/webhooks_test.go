@@ -0,0 +1,154 @@
+package surveys
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/plutov/formulosity/api/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeliverWebhooks(t *testing.T) {
+	cases := []struct {
+		name           string
+		responseStatus int
+		attempts       int
+		maxAttempts    int
+		expectStatus   types.WebhookDeliveryStatus
+		expectRetry    bool
+	}{
+		{
+			name:           "should mark delivery as delivered on 2xx response",
+			responseStatus: http.StatusOK,
+			maxAttempts:    3,
+			expectStatus:   types.WebhookDeliveryStatus_Delivered,
+		},
+		{
+			name:           "should retry failed delivery with backoff",
+			responseStatus: http.StatusInternalServerError,
+			maxAttempts:    3,
+			expectStatus:   types.WebhookDeliveryStatus_Pending,
+			expectRetry:    true,
+		},
+		{
+			name:           "should dead-letter delivery after max attempts",
+			responseStatus: http.StatusInternalServerError,
+			attempts:       2,
+			maxAttempts:    3,
+			expectStatus:   types.WebhookDeliveryStatus_Dead,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.responseStatus)
+				_, _ = w.Write([]byte("response"))
+			}))
+			defer server.Close()
+
+			svc, m := newTestServices(t)
+			now := time.Now()
+			delivery := types.WebhookDelivery{
+				UUID:        "delivery-uuid",
+				URL:         server.URL,
+				Method:      http.MethodPost,
+				Payload:     []byte(`{}`),
+				Status:      types.WebhookDeliveryStatus_Pending,
+				Attempts:    c.attempts,
+				MaxAttempts: c.maxAttempts,
+			}
+
+			m.EXPECT().ClaimWebhookDeliveries(now, now.Add(webhookClaimLease), webhookClaimLimit).Return([]types.WebhookDelivery{delivery}, nil)
+			m.EXPECT().StoreWebhookResponse(mock.Anything, mock.MatchedBy(func(a types.WebhookAttempt) bool {
+				return a.Attempt == c.attempts+1 && a.ResponseStatus == c.responseStatus && a.Response == "response"
+			})).Return(nil)
+			m.EXPECT().UpdateWebhookDelivery(mock.MatchedBy(func(d *types.WebhookDelivery) bool {
+				return d.Status == c.expectStatus && d.Attempts == c.attempts+1 && d.NextAttemptAt.After(now) == c.expectRetry
+			})).Return(nil)
+
+			assert.NoError(t, DeliverWebhooks(svc, server.Client(), now))
+		})
+	}
+}
+
+func TestDeliverWebhooksClaimError(t *testing.T) {
+	svc, m := newTestServices(t)
+	m.EXPECT().ClaimWebhookDeliveries(mock.Anything, mock.Anything, webhookClaimLimit).Return(nil, errors.New("db error"))
+
+	assert.EqualError(t, DeliverWebhooks(svc, http.DefaultClient, time.Now()), "unable to claim webhook deliveries")
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	cases := []struct {
+		name              string
+		delivery          *types.WebhookDelivery
+		expect            func(m *MockInterface)
+		expectErr         error
+		expectMaxAttempts int
+	}{
+		{
+			name:      "should return not found",
+			expectErr: types.ErrWebhookDeliveryNotFound,
+		},
+		{
+			name:      "should not redeliver pending delivery",
+			delivery:  &types.WebhookDelivery{UUID: "delivery-uuid", Status: types.WebhookDeliveryStatus_Pending},
+			expectErr: types.ErrWebhookDeliveryPending,
+		},
+		{
+			name:     "should not redeliver delivery which was redelivered concurrently",
+			delivery: &types.WebhookDelivery{UUID: "delivery-uuid", Status: types.WebhookDeliveryStatus_Dead, Attempts: 3, MaxAttempts: 3},
+			expect: func(m *MockInterface) {
+				m.EXPECT().RedeliverWebhookDelivery(mock.Anything).Return(false, nil)
+			},
+			expectErr: types.ErrWebhookDeliveryPending,
+		},
+		{
+			name:     "should redeliver dead delivery with max attempts of the endpoint",
+			delivery: &types.WebhookDelivery{UUID: "delivery-uuid", URL: "https://example.com/hook", EventType: types.WebhookEvent_SessionCompleted, Status: types.WebhookDeliveryStatus_Dead, Attempts: 3, MaxAttempts: 3},
+			expect: func(m *MockInterface) {
+				m.EXPECT().RedeliverWebhookDelivery(mock.MatchedBy(func(d *types.WebhookDelivery) bool {
+					return d.Status == types.WebhookDeliveryStatus_Pending
+				})).Return(true, nil)
+			},
+			expectMaxAttempts: 5,
+		},
+		{
+			name:     "should redeliver delivery with default max attempts if endpoint was removed",
+			delivery: &types.WebhookDelivery{UUID: "delivery-uuid", URL: "https://example.com/old", EventType: types.WebhookEvent_SessionCompleted, Status: types.WebhookDeliveryStatus_Delivered, Attempts: 1, MaxAttempts: 3},
+			expect: func(m *MockInterface) {
+				m.EXPECT().RedeliverWebhookDelivery(mock.Anything).Return(true, nil)
+			},
+			expectMaxAttempts: 1 + types.DefaultWebhookMaxAttempts,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, m := newTestServices(t)
+			survey := newTestSurvey()
+			survey.Config.Webhooks = []types.WebhookConfig{{URL: "https://example.com/hook", MaxAttempts: 2}}
+
+			m.EXPECT().GetWebhookDelivery("survey-uuid", "delivery-uuid").Return(c.delivery, nil)
+			if c.expect != nil {
+				c.expect(m)
+			}
+
+			delivery, err := RedeliverWebhook(svc, survey, "delivery-uuid")
+			if c.expectErr != nil {
+				assert.Equal(t, c.expectErr, err)
+				assert.Nil(t, delivery)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, types.WebhookDeliveryStatus_Pending, delivery.Status)
+			assert.Equal(t, c.expectMaxAttempts, delivery.MaxAttempts)
+		})
+	}
+}
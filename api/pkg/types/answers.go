@@ -4,6 +4,9 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -19,6 +22,8 @@ type Answer interface {
 
 type SingleOptionAnswer struct {
 	AnswerValue string `json:"value"`
+	// free-text value of "Other" option, stored separately from predefined options
+	Other *string `json:"other,omitempty"`
 }
 
 func (a SingleOptionAnswer) Value() (driver.Value, error) {
@@ -26,6 +31,14 @@ func (a SingleOptionAnswer) Value() (driver.Value, error) {
 }
 
 func (a *SingleOptionAnswer) Validate(q Question) error {
+	if a.Other != nil {
+		if len(a.AnswerValue) > 0 {
+			return fmt.Errorf("select either an option or other")
+		}
+
+		return validateOther(q, *a.Other)
+	}
+
 	if len(a.AnswerValue) == 0 {
 		return fmt.Errorf("invalid option selected")
 	}
@@ -47,6 +60,8 @@ func (a *SingleOptionAnswer) Validate(q Question) error {
 
 type MultiOptionsAnswer struct {
 	AnswerValue []string `json:"value"`
+	// free-text value of "Other" option, stored separately from predefined options
+	Other *string `json:"other,omitempty"`
 }
 
 func (a MultiOptionsAnswer) Value() (driver.Value, error) {
@@ -77,11 +92,42 @@ func (a *MultiOptionsAnswer) Validate(q Question) error {
 		uniqueOptions[option] = true
 	}
 
-	if q.Validation != nil && q.Validation.Min != nil && len(a.AnswerValue) < int(*q.Validation.Min) {
-		return fmt.Errorf("select at least %d options", *q.Validation.Min)
+	// other option counts as a selected option
+	selectedCount := len(a.AnswerValue)
+	if a.Other != nil {
+		if err := validateOther(q, *a.Other); err != nil {
+			return err
+		}
+		selectedCount++
 	}
-	if q.Validation != nil && q.Validation.Max != nil && len(a.AnswerValue) > int(*q.Validation.Max) {
-		return fmt.Errorf("select at most %d options", *q.Validation.Max)
+
+	if q.Validation != nil && q.Validation.Min != nil && selectedCount < int(*q.Validation.Min) {
+		return fmt.Errorf("select at least %d options", int(*q.Validation.Min))
+	}
+	if q.Validation != nil && q.Validation.Max != nil && selectedCount > int(*q.Validation.Max) {
+		return fmt.Errorf("select at most %d options", int(*q.Validation.Max))
+	}
+
+	return nil
+}
+
+// validateOther checks the free-text value of "Other" option
+func validateOther(q Question, other string) error {
+	if !q.AllowOther {
+		return fmt.Errorf("invalid option selected")
+	}
+	if len(strings.TrimSpace(other)) == 0 {
+		return fmt.Errorf("please specify other option")
+	}
+
+	if q.Validation != nil && q.Validation.Other != nil {
+		v := q.Validation.Other
+		if v.Min != nil && len(other) < int(*v.Min) {
+			return fmt.Errorf("please write at least %d characters", int(*v.Min))
+		}
+		if v.Max != nil && len(other) > int(*v.Max) {
+			return fmt.Errorf("please write at most %d characters", int(*v.Max))
+		}
 	}
 
 	return nil
@@ -97,10 +143,10 @@ func (a TextAnswer) Value() (driver.Value, error) {
 
 func (a *TextAnswer) Validate(q Question) error {
 	if q.Validation != nil && q.Validation.Min != nil && len(a.AnswerValue) < int(*q.Validation.Min) {
-		return fmt.Errorf("please write at least %d characters", *q.Validation.Min)
+		return fmt.Errorf("please write at least %d characters", int(*q.Validation.Min))
 	}
 	if q.Validation != nil && q.Validation.Max != nil && len(a.AnswerValue) > int(*q.Validation.Max) {
-		return fmt.Errorf("please write at most %d characters", *q.Validation.Max)
+		return fmt.Errorf("please write at most %d characters", int(*q.Validation.Max))
 	}
 
 	return nil
@@ -139,10 +185,57 @@ func (a *NumberAnswer) Validate(q Question) error {
 			return fmt.Errorf("maximum: %d", *q.Max)
 		}
 	}
+	if q.Type == QuestionType_NPS && (a.AnswerValue < NPS_Min || a.AnswerValue > NPS_Max) {
+		return fmt.Errorf("score must be between %d and %d", NPS_Min, NPS_Max)
+	}
+
+	return nil
+}
+
+type DecimalAnswer struct {
+	AnswerValue float64 `json:"value"`
+}
+
+func (a DecimalAnswer) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+func (a *DecimalAnswer) Validate(q Question) error {
+	if q.Validation == nil {
+		return nil
+	}
+
+	if q.Validation.Min != nil && a.AnswerValue < *q.Validation.Min {
+		return fmt.Errorf("minimum: %s", formatDecimal(*q.Validation.Min))
+	}
+	if q.Validation.Max != nil && a.AnswerValue > *q.Validation.Max {
+		return fmt.Errorf("maximum: %s", formatDecimal(*q.Validation.Max))
+	}
+	if q.Validation.Precision != nil {
+		if decimals := strings.Split(formatDecimal(a.AnswerValue), "."); len(decimals) == 2 && len(decimals[1]) > *q.Validation.Precision {
+			return fmt.Errorf("maximum decimal places: %d", *q.Validation.Precision)
+		}
+	}
+	if q.Validation.Step != nil {
+		// steps start from the minimum value if it's set
+		start := 0.0
+		if q.Validation.Min != nil {
+			start = *q.Validation.Min
+		}
+
+		steps := (a.AnswerValue - start) / *q.Validation.Step
+		if math.Abs(steps-math.Round(steps)) > 1e-9 {
+			return fmt.Errorf("value must be a multiple of %s", formatDecimal(*q.Validation.Step))
+		}
+	}
 
 	return nil
 }
 
+func formatDecimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
 type BoolAnswer struct {
 	AnswerValue bool `json:"value"`
 }
@@ -212,6 +305,153 @@ func (a *FileAnswer) Validate(q Question) error {
 	return nil
 }
 
+type MatrixAnswer struct {
+	AnswerValue map[string][]string `json:"value"`
+}
+
+func (a MatrixAnswer) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+// UnmarshalJSON accepts either a single column or a list of columns per row
+func (a *MatrixAnswer) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		AnswerValue map[string]json.RawMessage `json:"value"`
+	}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	a.AnswerValue = make(map[string][]string)
+	for row, value := range raw.AnswerValue {
+		var column string
+		if err := json.Unmarshal(value, &column); err == nil {
+			a.AnswerValue[row] = []string{column}
+			continue
+		}
+
+		var columns []string
+		if err := json.Unmarshal(value, &columns); err != nil {
+			return err
+		}
+		a.AnswerValue[row] = columns
+	}
+
+	return nil
+}
+
+func (a *MatrixAnswer) Validate(q Question) error {
+	for row, columns := range a.AnswerValue {
+		rowFound := false
+		for _, validRow := range q.Rows {
+			if validRow == row {
+				rowFound = true
+				break
+			}
+		}
+		if !rowFound {
+			return fmt.Errorf("invalid row: %s", row)
+		}
+
+		if len(columns) == 0 {
+			return fmt.Errorf("select a column for row: %s", row)
+		}
+		if !q.Multiple && len(columns) > 1 {
+			return fmt.Errorf("select only one column for row: %s", row)
+		}
+
+		uniqueColumns := make(map[string]bool)
+		for _, column := range columns {
+			columnFound := false
+			for _, validColumn := range q.Columns {
+				if validColumn == column {
+					columnFound = true
+					break
+				}
+			}
+			if !columnFound {
+				return fmt.Errorf("invalid column selected for row: %s", row)
+			}
+			if _, ok := uniqueColumns[column]; ok {
+				return fmt.Errorf("duplicate column selected for row: %s", row)
+			}
+
+			uniqueColumns[column] = true
+		}
+	}
+
+	// all rows are required unless validation sets the minimum number of rows
+	minRows := len(q.Rows)
+	if q.Validation != nil && q.Validation.Min != nil {
+		minRows = int(*q.Validation.Min)
+	}
+	if len(a.AnswerValue) < minRows {
+		for _, row := range q.Rows {
+			if _, ok := a.AnswerValue[row]; !ok {
+				return fmt.Errorf("select a column for row: %s", row)
+			}
+		}
+	}
+	if q.Validation != nil && q.Validation.Max != nil && len(a.AnswerValue) > int(*q.Validation.Max) {
+		return fmt.Errorf("answer at most %d rows", int(*q.Validation.Max))
+	}
+
+	return nil
+}
+
+// Flatten returns selected columns keyed by "questionID.row" in the order of question rows
+func (a *MatrixAnswer) Flatten(q Question) map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, row := range q.Rows {
+		key := q.ID + "." + row
+		columns, ok := a.AnswerValue[row]
+		switch {
+		case !ok:
+			values[key] = nil
+		case q.Multiple:
+			values[key] = columns
+		default:
+			values[key] = columns[0]
+		}
+	}
+
+	return values
+}
+
+// answerValue returns the submitted value of a given answer
+func answerValue(a Answer) interface{} {
+	switch v := a.(type) {
+	case *SingleOptionAnswer:
+		if v.Other != nil {
+			return *v.Other
+		}
+		return v.AnswerValue
+	case *MultiOptionsAnswer:
+		if v.Other != nil {
+			return append(append([]string{}, v.AnswerValue...), *v.Other)
+		}
+		return v.AnswerValue
+	case *TextAnswer:
+		return v.AnswerValue
+	case *DateAnswer:
+		return v.AnswerValue
+	case *NumberAnswer:
+		return v.AnswerValue
+	case *DecimalAnswer:
+		return v.AnswerValue
+	case *BoolAnswer:
+		return v.AnswerValue
+	case *EmailAnswer:
+		return v.AnswerValue
+	case *FileAnswer:
+		return v.AnswerValue
+	case *MatrixAnswer:
+		return v.AnswerValue
+	}
+
+	return nil
+}
+
 func formatBytes(bytes int64) string {
 	const (
 		KB = 1024
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -60,3 +62,295 @@ func TestFileAnswerValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestMatrixAnswerValidate(t *testing.T) {
+	question := Question{
+		ID:      "features",
+		Type:    QuestionType_Matrix,
+		Rows:    []string{"Speed", "Price"},
+		Columns: []string{"1", "2", "3"},
+	}
+	multipleQuestion := question
+	multipleQuestion.Multiple = true
+
+	cases := []struct {
+		question  Question
+		req       string
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			question:  question,
+			req:       `{"value": {"Speed": "1", "Price": "3"}}`,
+			expectErr: false,
+		},
+		{
+			question:  question,
+			req:       `{"value": {"Speed": "1"}}`,
+			expectErr: true,
+			errMsg:    "select a column for row: Price",
+		},
+		{
+			question:  question,
+			req:       `{"value": {"Speed": "1", "Price": "5"}}`,
+			expectErr: true,
+			errMsg:    "invalid column selected for row: Price",
+		},
+		{
+			question:  question,
+			req:       `{"value": {"Speed": "1", "Price": "2", "Design": "3"}}`,
+			expectErr: true,
+			errMsg:    "invalid row: Design",
+		},
+		{
+			question:  question,
+			req:       `{"value": {"Speed": ["1", "2"], "Price": "2"}}`,
+			expectErr: true,
+			errMsg:    "select only one column for row: Speed",
+		},
+		{
+			question:  multipleQuestion,
+			req:       `{"value": {"Speed": ["1", "2"], "Price": ["2"]}}`,
+			expectErr: false,
+		},
+	}
+
+	for _, c := range cases {
+		answer := &MatrixAnswer{}
+		if err := json.Unmarshal([]byte(c.req), answer); err != nil {
+			t.Fatalf("unable to decode answer: %v", err)
+		}
+
+		err := answer.Validate(c.question)
+		if c.expectErr {
+			if err == nil || !strings.Contains(err.Error(), c.errMsg) {
+				t.Errorf("expected error containing %s, got %v", c.errMsg, err)
+			}
+		} else if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+}
+
+func TestSetFlatAnswers(t *testing.T) {
+	questions := []Question{
+		{ID: "name", UUID: "uuid-1", Type: QuestionType_ShortText},
+		{ID: "features", UUID: "uuid-2", Type: QuestionType_Matrix, Rows: []string{"Speed", "Price"}, Columns: []string{"1", "2"}},
+	}
+	session := &SurveySession{
+		QuestionAnswers: []QuestionAnswer{
+			{QuestionUUID: "uuid-1", Answer: &TextAnswer{AnswerValue: "John"}},
+			{QuestionUUID: "uuid-2", Answer: &MatrixAnswer{AnswerValue: map[string][]string{"Speed": {"2"}, "Price": {"1"}}}},
+		},
+	}
+
+	session.SetFlatAnswers(questions)
+
+	expected := map[string]interface{}{
+		"name":           "John",
+		"features.Speed": "2",
+		"features.Price": "1",
+	}
+	if !reflect.DeepEqual(session.Answers, expected) {
+		t.Errorf("expected %v, got %v", expected, session.Answers)
+	}
+}
+
+func ptrFloat(f float64) *float64 {
+	return &f
+}
+
+func ptrInt(i int) *int {
+	return &i
+}
+
+func TestDecimalAnswerValidate(t *testing.T) {
+	question := Question{
+		Type: QuestionType_Number,
+		Unit: "kg",
+		Validation: &QuestionValidation{
+			Min:       ptrFloat(-10.5),
+			Max:       ptrFloat(100),
+			Step:      ptrFloat(0.5),
+			Precision: ptrInt(1),
+		},
+	}
+
+	cases := []struct {
+		question  Question
+		answer    DecimalAnswer
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			question:  question,
+			answer:    DecimalAnswer{AnswerValue: 72.5},
+			expectErr: false,
+		},
+		{
+			question:  question,
+			answer:    DecimalAnswer{AnswerValue: -10.5},
+			expectErr: false,
+		},
+		{
+			question:  question,
+			answer:    DecimalAnswer{AnswerValue: -11},
+			expectErr: true,
+			errMsg:    "minimum: -10.5",
+		},
+		{
+			question:  question,
+			answer:    DecimalAnswer{AnswerValue: 100.5},
+			expectErr: true,
+			errMsg:    "maximum: 100",
+		},
+		{
+			question:  question,
+			answer:    DecimalAnswer{AnswerValue: 72.25},
+			expectErr: true,
+			errMsg:    "maximum decimal places: 1",
+		},
+		{
+			question:  question,
+			answer:    DecimalAnswer{AnswerValue: 72.2},
+			expectErr: true,
+			errMsg:    "multiple of 0.5",
+		},
+		{
+			question:  Question{Type: QuestionType_Number},
+			answer:    DecimalAnswer{AnswerValue: 3.14159},
+			expectErr: false,
+		},
+	}
+
+	for _, c := range cases {
+		err := c.answer.Validate(c.question)
+		if c.expectErr {
+			if err == nil || !strings.Contains(err.Error(), c.errMsg) {
+				t.Errorf("expected error containing %s, got %v", c.errMsg, err)
+			}
+		} else if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+}
+
+func TestQuestionValidationValidateNumber(t *testing.T) {
+	cases := []struct {
+		validation QuestionValidation
+		expectErr  bool
+		errMsg     string
+	}{
+		{
+			validation: QuestionValidation{Min: ptrFloat(-1.5), Max: ptrFloat(1.5), Step: ptrFloat(0.1)},
+			expectErr:  false,
+		},
+		{
+			validation: QuestionValidation{Min: ptrFloat(2), Max: ptrFloat(1)},
+			expectErr:  true,
+			errMsg:     "min must be less than or equal",
+		},
+		{
+			validation: QuestionValidation{Step: ptrFloat(0)},
+			expectErr:  true,
+			errMsg:     "step must be greater than 0",
+		},
+		{
+			validation: QuestionValidation{Precision: ptrInt(-1)},
+			expectErr:  true,
+			errMsg:     "precision must be greater than or equal to 0",
+		},
+	}
+
+	for _, c := range cases {
+		err := c.validation.ValidateNumber()
+		if c.expectErr {
+			if err == nil || !strings.Contains(err.Error(), c.errMsg) {
+				t.Errorf("expected error containing %s, got %v", c.errMsg, err)
+			}
+		} else if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+}
+
+func TestOptionAnswersValidateOther(t *testing.T) {
+	single := Question{
+		Type:       QuestionType_DropdownSingle,
+		Options:    []string{"Berlin", "Munich"},
+		AllowOther: true,
+		Validation: &QuestionValidation{Other: &QuestionValidation{Min: ptrFloat(3), Max: ptrFloat(10)}},
+	}
+	multiple := Question{
+		Type:       QuestionType_DropdownMultiple,
+		Options:    []string{"Berlin", "Munich"},
+		AllowOther: true,
+		Validation: &QuestionValidation{Max: ptrFloat(2)},
+	}
+	withoutOther := single
+	withoutOther.AllowOther = false
+
+	cases := []struct {
+		question  Question
+		answer    Answer
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			question:  single,
+			answer:    &SingleOptionAnswer{Other: ptrString("Hamburg")},
+			expectErr: false,
+		},
+		{
+			question:  withoutOther,
+			answer:    &SingleOptionAnswer{Other: ptrString("Hamburg")},
+			expectErr: true,
+			errMsg:    "invalid option selected",
+		},
+		{
+			question:  single,
+			answer:    &SingleOptionAnswer{AnswerValue: "Berlin", Other: ptrString("Hamburg")},
+			expectErr: true,
+			errMsg:    "select either an option or other",
+		},
+		{
+			question:  single,
+			answer:    &SingleOptionAnswer{Other: ptrString(" ")},
+			expectErr: true,
+			errMsg:    "please specify other option",
+		},
+		{
+			question:  single,
+			answer:    &SingleOptionAnswer{Other: ptrString("Ulm")},
+			expectErr: false,
+		},
+		{
+			question:  single,
+			answer:    &SingleOptionAnswer{Other: ptrString("Frankfurt am Main")},
+			expectErr: true,
+			errMsg:    "at most 10 characters",
+		},
+		{
+			question:  multiple,
+			answer:    &MultiOptionsAnswer{AnswerValue: []string{"Berlin"}, Other: ptrString("Hamburg")},
+			expectErr: false,
+		},
+		{
+			question:  multiple,
+			answer:    &MultiOptionsAnswer{AnswerValue: []string{"Berlin", "Munich"}, Other: ptrString("Hamburg")},
+			expectErr: true,
+			errMsg:    "select at most 2 options",
+		},
+	}
+
+	for _, c := range cases {
+		err := c.answer.Validate(c.question)
+		if c.expectErr {
+			if err == nil || !strings.Contains(err.Error(), c.errMsg) {
+				t.Errorf("expected error containing %s, got %v", c.errMsg, err)
+			}
+		} else if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/plutov/formulosity/api/pkg/expr"
+)
+
+// plainIdentifier matches IDs which can be used in expressions without backticks
+var plainIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateComputedVariables checks that expressions reference questions or previously defined variables
+func (s *SurveyConfig) validateComputedVariables() error {
+	if s.Variables == nil {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	for _, q := range s.Questions.Questions {
+		if q.ID != "" {
+			known[q.ID] = true
+		}
+	}
+
+	for _, v := range s.Variables.Variables {
+		if !v.IsComputed() {
+			continue
+		}
+
+		if known[v.ID] {
+			return fmt.Errorf("variables[].id must not be the same as a question id: %s", v.ID)
+		}
+
+		e, err := expr.Parse(v.Expression)
+		if err != nil {
+			return fmt.Errorf("variables[].expression of %s is invalid: %w", v.ID, err)
+		}
+
+		if err := e.CheckIdentifiers(func(id string) bool { return known[id] }); err != nil {
+			var unknown *expr.UnknownIdentifierError
+			if !errors.As(err, &unknown) {
+				return fmt.Errorf("variables[].expression of %s is invalid: %w", v.ID, err)
+			}
+
+			// e.g. q-1 is parsed as q - 1, such IDs must be quoted
+			for _, q := range s.Questions.Questions {
+				if q.ID != "" && !plainIdentifier.MatchString(q.ID) && strings.HasPrefix(unknown.Rest, q.ID) {
+					return fmt.Errorf("variables[].expression of %s must reference %s in backticks: `%s`", v.ID, q.ID, q.ID)
+				}
+			}
+			return fmt.Errorf("variables[].expression of %s must reference a question or a previous variable: %s", v.ID, unknown.Name)
+		}
+
+		known[v.ID] = true
+	}
+
+	return nil
+}
+
+// ComputeVariables evaluates computed variables in order with the given answers keyed by question ID.
+// Variables which can't be evaluated, e.g. because of unanswered questions or division by zero, are nil
+func (s *SurveyConfig) ComputeVariables(answers map[string]Answer) map[string]interface{} {
+	if s.Variables == nil {
+		return nil
+	}
+
+	values := make(map[string]interface{})
+	for _, q := range s.Questions.Questions {
+		values[q.ID] = expressionValue(answers[q.ID])
+	}
+
+	var computed map[string]interface{}
+	for _, v := range s.Variables.Variables {
+		if !v.IsComputed() {
+			continue
+		}
+
+		value := v.compute(values)
+		values[v.ID] = value
+
+		if computed == nil {
+			computed = make(map[string]interface{})
+		}
+		computed[v.ID] = value
+	}
+
+	return computed
+}
+
+// compute evaluates the expression and converts the result to the variable type
+func (v Variable) compute(values map[string]interface{}) interface{} {
+	e, err := expr.Parse(v.Expression)
+	if err != nil {
+		return nil
+	}
+
+	result, err := e.Eval(values)
+	if err != nil || result == nil {
+		return nil
+	}
+
+	switch v.Type {
+	case VariableType_Number:
+		if num, ok := result.(float64); ok && !math.IsNaN(num) && !math.IsInf(num, 0) {
+			return num
+		}
+	case VariableType_Text:
+		switch r := result.(type) {
+		case string:
+			return r
+		case float64:
+			return formatDecimal(r)
+		case bool:
+			return strconv.FormatBool(r)
+		}
+	case VariableType_Boolean:
+		if b, ok := result.(bool); ok {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// expressionValue returns the answer value used in expressions, matrix answers can't be referenced
+func expressionValue(a Answer) interface{} {
+	if _, ok := a.(*MatrixAnswer); ok {
+		return nil
+	}
+
+	return answerValue(a)
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func computedSurveyConfig() *SurveyConfig {
+	return &SurveyConfig{
+		Questions: &Questions{
+			Questions: []Question{
+				{ID: "q1", Type: QuestionType_Rating},
+				{ID: "q2", Type: QuestionType_Rating},
+				{ID: "name", Type: QuestionType_ShortText},
+			},
+		},
+		Variables: &Variables{
+			Variables: []Variable{
+				{ID: "cities", Type: VariableType_List, Options: []string{"Berlin"}},
+				{ID: "satisfaction_index", Type: VariableType_Number, Expression: "(q1 + q2) / 2"},
+				{ID: "satisfied", Type: VariableType_Boolean, Expression: "satisfaction_index >= 4"},
+				{ID: "greeting", Type: VariableType_Text, Expression: "'Hi ' + name"},
+			},
+		},
+	}
+}
+
+func TestComputeVariables(t *testing.T) {
+	config := computedSurveyConfig()
+
+	variables := config.ComputeVariables(map[string]Answer{
+		"q1": &NumberAnswer{AnswerValue: 5},
+		"q2": &NumberAnswer{AnswerValue: 4},
+	})
+
+	expected := map[string]interface{}{
+		"satisfaction_index": 4.5,
+		"satisfied":          true,
+		"greeting":           nil,
+	}
+	if !reflect.DeepEqual(variables, expected) {
+		t.Errorf("expected %v, got %v", expected, variables)
+	}
+}
+
+func TestValidateComputedVariables(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*SurveyConfig)
+		valid  bool
+	}{
+		{"valid", func(c *SurveyConfig) {}, true},
+		{"unknown question", func(c *SurveyConfig) {
+			c.Variables.Variables[1].Expression = "(q1 + q3) / 2"
+		}, false},
+		{"later variable", func(c *SurveyConfig) {
+			c.Variables.Variables[1].Expression = "satisfied"
+		}, false},
+		{"invalid expression", func(c *SurveyConfig) {
+			c.Variables.Variables[1].Expression = "(q1 + q2"
+		}, false},
+		{"question id", func(c *SurveyConfig) {
+			c.Variables.Variables[1].ID = "q1"
+		}, false},
+		{"quoted question id", func(c *SurveyConfig) {
+			c.Questions.Questions[1].ID = "q-2"
+			c.Variables.Variables[1].Expression = "(q1 + `q-2`) / 2"
+		}, true},
+		{"unquoted question id", func(c *SurveyConfig) {
+			c.Questions.Questions[1].ID = "q-2"
+			c.Variables.Variables[1].Expression = "(q1 + q-2) / 2"
+		}, false},
+	}
+
+	for _, tt := range tests {
+		config := computedSurveyConfig()
+		tt.modify(config)
+
+		err := config.validateComputedVariables()
+		if tt.valid && err != nil {
+			t.Errorf("%s: expected valid, got %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expected error, got nil", tt.name)
+		}
+	}
+}
+
+func TestValidateComputedVariablesBacktickHint(t *testing.T) {
+	config := computedSurveyConfig()
+	config.Questions.Questions[1].ID = "q-2"
+	config.Variables.Variables[1].Expression = "(q1 + q-2) / 2"
+
+	err := config.validateComputedVariables()
+	if err == nil || !strings.Contains(err.Error(), "`q-2`") {
+		t.Errorf("expected backtick hint, got %v", err)
+	}
+
+	// q-2 is not referenced, so the hint is not shown for an unrelated identifier
+	config.Variables.Variables[1].Expression = "(q1 + q) / 2"
+	err = config.validateComputedVariables()
+	if err == nil || strings.Contains(err.Error(), "backticks") {
+		t.Errorf("expected error without hint, got %v", err)
+	}
+}
+
+func TestVariablesValidateExpression(t *testing.T) {
+	variables := &Variables{Variables: []Variable{{ID: "index", Type: VariableType_Number}}}
+	if err := variables.Validate(); err == nil {
+		t.Errorf("expected error for computed variable without expression")
+	}
+
+	variables = &Variables{Variables: []Variable{{ID: "cities", Type: VariableType_List, Expression: "1"}}}
+	if err := variables.Validate(); err == nil {
+		t.Errorf("expected error for list variable with expression")
+	}
+}
@@ -0,0 +1,199 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// QuestionJumpTarget_End can be used in jumpTo to skip all remaining questions
+const QuestionJumpTarget_End = "end"
+
+type QuestionCondition struct {
+	Question  string   `json:"question,omitempty" yaml:"question,omitempty"`
+	Equals    *string  `json:"equals,omitempty" yaml:"equals,omitempty"`
+	NotEquals *string  `json:"notEquals,omitempty" yaml:"notEquals,omitempty"`
+	In        []string `json:"in,omitempty" yaml:"in,omitempty"`
+}
+
+type QuestionJump struct {
+	QuestionCondition `yaml:",inline"`
+	To                string `json:"to" yaml:"to"`
+}
+
+func (c QuestionCondition) Validate() error {
+	operators := 0
+	if c.Equals != nil {
+		operators++
+	}
+	if c.NotEquals != nil {
+		operators++
+	}
+	if len(c.In) > 0 {
+		operators++
+	}
+	if operators != 1 {
+		return errors.New("exactly one of equals, notEquals or in is required")
+	}
+
+	return nil
+}
+
+// Match reports whether the answer satisfies the condition, unanswered questions never match
+func (c QuestionCondition) Match(answer Answer) bool {
+	if answer == nil {
+		return false
+	}
+
+	values := answerStrings(answer)
+	switch {
+	case c.Equals != nil:
+		return slices.Contains(values, *c.Equals)
+	case c.NotEquals != nil:
+		return !slices.Contains(values, *c.NotEquals)
+	case len(c.In) > 0:
+		for _, v := range values {
+			if slices.Contains(c.In, v) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// VisibleQuestions returns the questions shown to a respondent in order,
+// evaluating showIf and jumpTo against answers keyed by question ID.
+func (s *Questions) VisibleQuestions(answers map[string]Answer) []Question {
+	visible := []Question{}
+	shown := map[string]bool{}
+
+	// answers to questions hidden by the current path are ignored
+	answerOf := func(questionID string) Answer {
+		if !shown[questionID] {
+			return nil
+		}
+		return answers[questionID]
+	}
+
+	jumpTo := ""
+	for _, q := range s.Questions {
+		if jumpTo != "" {
+			if q.ID != jumpTo {
+				continue
+			}
+			jumpTo = ""
+		}
+
+		if q.ShowIf != nil && !q.ShowIf.Match(answerOf(q.ShowIf.Question)) {
+			continue
+		}
+
+		visible = append(visible, q)
+		shown[q.ID] = true
+
+		for _, j := range q.JumpTo {
+			questionID := j.Question
+			if questionID == "" {
+				questionID = q.ID
+			}
+			if j.Match(answerOf(questionID)) {
+				jumpTo = j.To
+				break
+			}
+		}
+
+		if jumpTo == QuestionJumpTarget_End {
+			break
+		}
+	}
+
+	return visible
+}
+
+func (s *Questions) validateConditions() error {
+	positions := make(map[string]int)
+	for i, q := range s.Questions {
+		if q.ID != "" {
+			positions[q.ID] = i
+		}
+	}
+
+	for i, q := range s.Questions {
+		if q.ShowIf != nil {
+			if err := q.ShowIf.Validate(); err != nil {
+				return fmt.Errorf("questions[].showIf is invalid: %w", err)
+			}
+			if pos, ok := positions[q.ShowIf.Question]; !ok || pos >= i {
+				return fmt.Errorf("questions[].showIf.question must reference a previous question: %s", q.ShowIf.Question)
+			}
+		}
+
+		for _, j := range q.JumpTo {
+			if err := j.Validate(); err != nil {
+				return fmt.Errorf("questions[].jumpTo is invalid: %w", err)
+			}
+			if j.Question != "" {
+				if pos, ok := positions[j.Question]; !ok || pos > i {
+					return fmt.Errorf("questions[].jumpTo.question must reference a previous question: %s", j.Question)
+				}
+			} else if q.ID == "" {
+				return fmt.Errorf("questions[].id is required when questions[].jumpTo is used")
+			}
+			if j.To == QuestionJumpTarget_End {
+				continue
+			}
+			if pos, ok := positions[j.To]; !ok || pos <= i {
+				return fmt.Errorf("questions[].jumpTo.to must reference a following question: %s", j.To)
+			}
+		}
+	}
+
+	return nil
+}
+
+// answerStrings returns answer values in the form used by conditions
+func answerStrings(a Answer) []string {
+	switch v := a.(type) {
+	case *SingleOptionAnswer:
+		if v.Other != nil {
+			return []string{*v.Other}
+		}
+		return []string{v.AnswerValue}
+	case *MultiOptionsAnswer:
+		if v.Other != nil {
+			return append(append([]string{}, v.AnswerValue...), *v.Other)
+		}
+		return v.AnswerValue
+	case *TextAnswer:
+		return []string{v.AnswerValue}
+	case *DateAnswer:
+		return []string{v.AnswerValue}
+	case *NumberAnswer:
+		return []string{strconv.FormatInt(v.AnswerValue, 10)}
+	case *DecimalAnswer:
+		return []string{formatDecimal(v.AnswerValue)}
+	case *BoolAnswer:
+		return []string{strconv.FormatBool(v.AnswerValue)}
+	case *EmailAnswer:
+		return []string{v.AnswerValue}
+	case *FileAnswer:
+		return []string{v.AnswerValue}
+	case *MatrixAnswer:
+		rows := make([]string, 0, len(v.AnswerValue))
+		for row := range v.AnswerValue {
+			rows = append(rows, row)
+		}
+		slices.Sort(rows)
+
+		values := []string{}
+		for _, row := range rows {
+			values = append(values, row+": "+strings.Join(v.AnswerValue[row], ", "))
+		}
+		return values
+	}
+
+	return nil
+}
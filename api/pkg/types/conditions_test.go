@@ -0,0 +1,126 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func conditionQuestions() *Questions {
+	return &Questions{
+		Questions: []Question{
+			{ID: "q1", Type: QuestionType_YesNo, Label: "Do you live in Berlin?", JumpTo: []QuestionJump{
+				{QuestionCondition: QuestionCondition{Equals: ptrString("false")}, To: "q4"},
+			}},
+			{ID: "q2", Type: QuestionType_DropdownSingle, Label: "District", Options: []string{"Mitte", "Pankow"}},
+			{ID: "q3", Type: QuestionType_ShortText, Label: "Street in Mitte", ShowIf: &QuestionCondition{Question: "q2", Equals: ptrString("Mitte")}},
+			{ID: "q4", Type: QuestionType_Email, Label: "Email"},
+		},
+	}
+}
+
+func TestVisibleQuestions(t *testing.T) {
+	cases := []struct {
+		name     string
+		answers  map[string]Answer
+		expected []string
+	}{
+		{
+			name:     "no answers",
+			answers:  map[string]Answer{},
+			expected: []string{"q1", "q2", "q4"},
+		},
+		{
+			name: "jump over district questions",
+			answers: map[string]Answer{
+				"q1": &BoolAnswer{AnswerValue: false},
+			},
+			expected: []string{"q1", "q4"},
+		},
+		{
+			name: "show street question",
+			answers: map[string]Answer{
+				"q1": &BoolAnswer{AnswerValue: true},
+				"q2": &SingleOptionAnswer{AnswerValue: "Mitte"},
+			},
+			expected: []string{"q1", "q2", "q3", "q4"},
+		},
+		{
+			name: "ignore answers of hidden questions",
+			answers: map[string]Answer{
+				"q1": &BoolAnswer{AnswerValue: false},
+				"q2": &SingleOptionAnswer{AnswerValue: "Mitte"},
+			},
+			expected: []string{"q1", "q4"},
+		},
+	}
+
+	for _, c := range cases {
+		visible := conditionQuestions().VisibleQuestions(c.answers)
+		ids := []string{}
+		for _, q := range visible {
+			ids = append(ids, q.ID)
+		}
+		if strings.Join(ids, ",") != strings.Join(c.expected, ",") {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, ids)
+		}
+	}
+}
+
+func TestQuestionsValidateConditions(t *testing.T) {
+	cases := []struct {
+		name      string
+		modify    func(q *Questions)
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			name:      "valid",
+			modify:    func(q *Questions) {},
+			expectErr: false,
+		},
+		{
+			name: "showIf references following question",
+			modify: func(q *Questions) {
+				q.Questions[1].ShowIf = &QuestionCondition{Question: "q3", Equals: ptrString("x")}
+			},
+			expectErr: true,
+			errMsg:    "must reference a previous question",
+		},
+		{
+			name: "showIf without operator",
+			modify: func(q *Questions) {
+				q.Questions[2].ShowIf = &QuestionCondition{Question: "q2"}
+			},
+			expectErr: true,
+			errMsg:    "exactly one of",
+		},
+		{
+			name: "jumpTo references previous question",
+			modify: func(q *Questions) {
+				q.Questions[0].JumpTo[0].To = "q1"
+			},
+			expectErr: true,
+			errMsg:    "must reference a following question",
+		},
+		{
+			name: "jumpTo end",
+			modify: func(q *Questions) {
+				q.Questions[0].JumpTo[0].To = QuestionJumpTarget_End
+			},
+			expectErr: false,
+		},
+	}
+
+	for _, c := range cases {
+		questions := conditionQuestions()
+		c.modify(questions)
+		err := questions.Validate()
+		if c.expectErr {
+			if err == nil || !strings.Contains(err.Error(), c.errMsg) {
+				t.Errorf("%s: expected error containing %s, got %v", c.name, c.errMsg, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: expected no error, got %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+const (
+	hiddenFieldMaxLength = 1024
+	// prefix of query params which filter sessions by hidden fields, e.g. hidden.utm_source=email
+	HiddenFieldFilterPrefix = "hidden."
+)
+
+var hiddenFieldRegexp = regexp.MustCompile(`^[\w\-]+$`)
+
+// query params which are used by the survey itself
+var reservedHiddenFields = map[string]bool{
+	"lang":                true,
+	"token":               true,
+	PrefillSignatureParam: true,
+}
+
+func (s *SurveyConfig) validateHiddenFields() error {
+	uniqueFields := make(map[string]bool)
+	for _, field := range s.HiddenFields {
+		if !hiddenFieldRegexp.MatchString(field) {
+			return fmt.Errorf("metadata.hiddenFields[] is invalid: %s", field)
+		}
+		if _, ok := reservedHiddenFields[field]; ok {
+			return fmt.Errorf("metadata.hiddenFields[] is reserved: %s", field)
+		}
+		if _, ok := uniqueFields[field]; ok {
+			return fmt.Errorf("metadata.hiddenFields[] must be unique: %s", field)
+		}
+		uniqueFields[field] = true
+	}
+
+	return nil
+}
+
+// HiddenFieldValues returns values of the declared hidden fields from query params and JSON body,
+// values from body take precedence, undeclared fields are ignored
+func (s *SurveyConfig) HiddenFieldValues(query url.Values, body map[string]interface{}) (map[string]string, error) {
+	var values map[string]string
+	for _, field := range s.HiddenFields {
+		value, ok := body[field]
+		if !ok {
+			if !query.Has(field) {
+				continue
+			}
+			value = query.Get(field)
+		}
+
+		var str string
+		switch v := value.(type) {
+		case string:
+			str = v
+		case float64:
+			str = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			str = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("hidden field %s must be a string", field)
+		}
+
+		if len(str) > hiddenFieldMaxLength {
+			return nil, fmt.Errorf("hidden field %s is too long", field)
+		}
+
+		if values == nil {
+			values = make(map[string]string)
+		}
+		values[field] = str
+	}
+
+	return values, nil
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestValidateHiddenFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  []string
+		wantErr bool
+	}{
+		{"valid", []string{"customer_id", "utm-source"}, false},
+		{"invalid name", []string{"customer id"}, true},
+		{"reserved", []string{"lang"}, true},
+		{"duplicate", []string{"utm_source", "utm_source"}, true},
+	}
+
+	for _, tt := range tests {
+		s := &SurveyConfig{HiddenFields: tt.fields}
+		err := s.validateHiddenFields()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestHiddenFieldValues(t *testing.T) {
+	s := &SurveyConfig{HiddenFields: []string{"customer_id", "utm_source"}}
+
+	tests := []struct {
+		name     string
+		query    url.Values
+		body     map[string]interface{}
+		expected map[string]string
+		wantErr  bool
+	}{
+		{"none", url.Values{}, nil, nil, false},
+		{"query", url.Values{"utm_source": {"email"}, "other": {"x"}}, nil, map[string]string{"utm_source": "email"}, false},
+		{"body", url.Values{}, map[string]interface{}{"customer_id": float64(42)}, map[string]string{"customer_id": "42"}, false},
+		{"body wins", url.Values{"utm_source": {"email"}}, map[string]interface{}{"utm_source": "ads"}, map[string]string{"utm_source": "ads"}, false},
+		{"invalid type", url.Values{}, map[string]interface{}{"customer_id": []interface{}{"1"}}, nil, true},
+	}
+
+	for _, tt := range tests {
+		values, err := s.HiddenFieldValues(tt.query, tt.body)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+			continue
+		}
+		if !reflect.DeepEqual(values, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, values)
+		}
+	}
+}
+
+func TestSurveySessionsFilterSetHiddenFields(t *testing.T) {
+	filter := &SurveySessionsFilter{Limit: 10, SortBy: "created_at", Order: "desc"}
+	filter.SetHiddenFields(url.Values{"hidden.utm_source": {"email"}, "hidden.customer_id": {"42"}, "limit": {"10"}})
+
+	expected := map[string]string{"utm_source": "email", "customer_id": "42"}
+	if !reflect.DeepEqual(filter.HiddenFields, expected) {
+		t.Errorf("expected %v, got %v", expected, filter.HiddenFields)
+	}
+
+	str := "limit=10_offset=0_sort_by=created_at_order=desc_hidden.customer_id=42_hidden.utm_source=email"
+	if filter.ToString() != str {
+		t.Errorf("expected %s, got %s", str, filter.ToString())
+	}
+}
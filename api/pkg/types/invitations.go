@@ -0,0 +1,104 @@
+package types
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+const maxInvitationsPerUpload = 10000
+
+var ErrInvitationNotFound = errors.New("invitation token is invalid or already used")
+
+// Invitation is a respondent invited to the survey with a single-use token
+type Invitation struct {
+	UUID      string     `json:"uuid"`
+	CreatedAt time.Time  `json:"created_at"`
+	Email     string     `json:"email"`
+	Name      string     `json:"name,omitempty"`
+	Token     string     `json:"token"`
+	URL       string     `json:"url"`
+	UsedAt    *time.Time `json:"used_at"`
+	// session created with the token, empty if the respondent hasn't started the survey yet
+	SessionUUID   string              `json:"session_uuid,omitempty"`
+	SessionStatus SurveySessionStatus `json:"session_status,omitempty"`
+}
+
+// NewInvitationToken returns a random token for the invitation link
+func NewInvitationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// ParseInvitationsCSV reads respondents from CSV with a header row,
+// email column is required and name column is optional, duplicate emails are ignored
+func ParseInvitationsCSV(r io.Reader) ([]Invitation, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("csv header is required")
+	}
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+	}
+	emailIndex := slices.Index(header, "email")
+	if emailIndex < 0 {
+		return nil, errors.New("csv email column is required")
+	}
+	nameIndex := slices.Index(header, "name")
+
+	invitations := []Invitation{}
+	emails := make(map[string]bool)
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv row %d is invalid", row)
+		}
+
+		invitation := Invitation{}
+		if emailIndex < len(record) {
+			invitation.Email = strings.ToLower(strings.TrimSpace(record[emailIndex]))
+		}
+		if nameIndex >= 0 && nameIndex < len(record) {
+			invitation.Name = strings.TrimSpace(record[nameIndex])
+		}
+		if err := validation.Validate(invitation.Email, validation.Required, is.Email); err != nil {
+			return nil, fmt.Errorf("csv row %d email is invalid", row)
+		}
+
+		if _, ok := emails[invitation.Email]; ok {
+			continue
+		}
+		emails[invitation.Email] = true
+
+		if len(invitations) == maxInvitationsPerUpload {
+			return nil, fmt.Errorf("csv can have at most %d respondents", maxInvitationsPerUpload)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if len(invitations) == 0 {
+		return nil, errors.New("csv has no respondents")
+	}
+
+	return invitations, nil
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseInvitationsCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		csv      string
+		expected []Invitation
+		wantErr  bool
+	}{
+		{"email only", "email\njohn@example.com\n", []Invitation{{Email: "john@example.com"}}, false},
+		{"email and name", "Name, Email\nJohn Doe, John@Example.com\nJane,jane@example.com\n", []Invitation{
+			{Email: "john@example.com", Name: "John Doe"},
+			{Email: "jane@example.com", Name: "Jane"},
+		}, false},
+		{"duplicate emails", "email\njohn@example.com\nJOHN@example.com\n", []Invitation{{Email: "john@example.com"}}, false},
+		{"empty", "", nil, true},
+		{"missing email column", "name\nJohn\n", nil, true},
+		{"invalid email", "email\njohn\n", nil, true},
+		{"no respondents", "email\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		invitations, err := ParseInvitationsCSV(strings.NewReader(tt.csv))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+			continue
+		}
+		if len(invitations) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, invitations)
+			continue
+		}
+		for i := range invitations {
+			if invitations[i].Email != tt.expected[i].Email || invitations[i].Name != tt.expected[i].Name {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected[i], invitations[i])
+			}
+		}
+	}
+}
+
+func TestNewInvitationToken(t *testing.T) {
+	token1, err := NewInvitationToken()
+	if err != nil {
+		t.Fatalf("unable to generate token: %v", err)
+	}
+	token2, _ := NewInvitationToken()
+
+	if len(token1) != 32 {
+		t.Errorf("expected token of 32 chars, got %s", token1)
+	}
+	if token1 == token2 {
+		t.Errorf("expected unique tokens, got %s", token1)
+	}
+}
@@ -0,0 +1,129 @@
+package types
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// IPProtection configures duplicate protection by IP address
+type IPProtection struct {
+	// sessions from the same subnet are counted together, e.g. 24 for IPv4 and 64 for IPv6,
+	// the whole address is used if empty
+	IPv4Prefix int `json:"ipv4Prefix,omitempty" yaml:"ipv4Prefix"`
+	IPv6Prefix int `json:"ipv6Prefix,omitempty" yaml:"ipv6Prefix"`
+	// number of sessions allowed from the same address, 1 if empty
+	MaxSessions int `json:"maxSessions,omitempty" yaml:"maxSessions"`
+	// addresses or CIDR ranges which are not limited, e.g. corporate NATs
+	Allowlist []string `json:"allowlist,omitempty" yaml:"allowlist"`
+}
+
+func (p *IPProtection) Validate() error {
+	if p.IPv4Prefix < 0 || p.IPv4Prefix > 32 {
+		return fmt.Errorf("security.ipProtection.ipv4Prefix must be between 0 and 32")
+	}
+	if p.IPv6Prefix < 0 || p.IPv6Prefix > 128 {
+		return fmt.Errorf("security.ipProtection.ipv6Prefix must be between 0 and 128")
+	}
+	if p.MaxSessions < 0 {
+		return fmt.Errorf("security.ipProtection.maxSessions must be greater than or equal to 0")
+	}
+	for _, entry := range p.Allowlist {
+		if _, err := parseIPPrefix(entry); err != nil {
+			return fmt.Errorf("security.ipProtection.allowlist is invalid: %s", entry)
+		}
+	}
+
+	return nil
+}
+
+func (s *SurveyConfig) ipProtection() IPProtection {
+	if s.Security == nil || s.Security.IPProtection == nil {
+		return IPProtection{}
+	}
+
+	return *s.Security.IPProtection
+}
+
+// MaxSessionsPerIPAddress returns the number of sessions allowed from the same IP address or subnet
+func (s *SurveyConfig) MaxSessionsPerIPAddress() int {
+	if maxSessions := s.ipProtection().MaxSessions; maxSessions > 0 {
+		return maxSessions
+	}
+
+	return 1
+}
+
+// IsIPAddressAllowlisted reports whether sessions from the IP address are not limited
+func (s *SurveyConfig) IsIPAddressAllowlisted(ipAddr string) bool {
+	addr, err := netip.ParseAddr(ipAddr)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, entry := range s.ipProtection().Allowlist {
+		if prefix, err := parseIPPrefix(entry); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IPAddressKey returns the subnet of the IP address which sessions are counted by,
+// invalid addresses are returned as is
+func (s *SurveyConfig) IPAddressKey(ipAddr string) string {
+	addr, err := netip.ParseAddr(ipAddr)
+	if err != nil {
+		return ipAddr
+	}
+	addr = addr.Unmap().WithZone("")
+
+	protection := s.ipProtection()
+	bits := protection.IPv4Prefix
+	if addr.Is6() {
+		bits = protection.IPv6Prefix
+	}
+	if bits == 0 {
+		return addr.String()
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return addr.String()
+	}
+
+	return prefix.String()
+}
+
+// HashIPAddress returns the salted hash of the IP address key which is stored instead of the address,
+// it's bound to the survey so respondents can't be tracked across surveys
+func HashIPAddress(surveyUUID string, key string, salt string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(surveyUUID + ":" + key))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func parseIPPrefix(entry string) (netip.Prefix, error) {
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -0,0 +1,99 @@
+package types
+
+import (
+	"testing"
+)
+
+func ipProtectionTestConfig(p *IPProtection) *SurveyConfig {
+	return &SurveyConfig{Security: &Security{DuplicateProtection: DuplicateProtectionType_Ip, IPProtection: p}}
+}
+
+func TestIPProtectionValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		protection IPProtection
+		wantErr    bool
+	}{
+		{"empty", IPProtection{}, false},
+		{"valid", IPProtection{IPv4Prefix: 24, IPv6Prefix: 64, MaxSessions: 3, Allowlist: []string{"203.0.113.0/24", "198.51.100.7", "2001:db8::/32"}}, false},
+		{"invalid ipv4 prefix", IPProtection{IPv4Prefix: 33}, true},
+		{"invalid ipv6 prefix", IPProtection{IPv6Prefix: -1}, true},
+		{"invalid max sessions", IPProtection{MaxSessions: -1}, true},
+		{"invalid allowlist", IPProtection{Allowlist: []string{"203.0.113.0/33"}}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.protection.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestIPAddressKey(t *testing.T) {
+	grouped := ipProtectionTestConfig(&IPProtection{IPv4Prefix: 24, IPv6Prefix: 64})
+	exact := ipProtectionTestConfig(nil)
+
+	tests := []struct {
+		name     string
+		config   *SurveyConfig
+		ipAddr   string
+		expected string
+	}{
+		{"exact ipv4", exact, "203.0.113.7", "203.0.113.7"},
+		{"exact ipv6", exact, "2001:db8::1", "2001:db8::1"},
+		{"ipv4 subnet", grouped, "203.0.113.7", "203.0.113.0/24"},
+		{"ipv4 mapped subnet", grouped, "::ffff:203.0.113.7", "203.0.113.0/24"},
+		{"ipv6 subnet", grouped, "2001:db8:0:1:abcd::1", "2001:db8:0:1::/64"},
+		{"invalid", grouped, "unknown", "unknown"},
+	}
+
+	for _, tt := range tests {
+		if key := tt.config.IPAddressKey(tt.ipAddr); key != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, key)
+		}
+	}
+}
+
+func TestIsIPAddressAllowlisted(t *testing.T) {
+	s := ipProtectionTestConfig(&IPProtection{Allowlist: []string{"203.0.113.0/24", "198.51.100.7"}})
+
+	tests := []struct {
+		ipAddr   string
+		expected bool
+	}{
+		{"203.0.113.42", true},
+		{"::ffff:203.0.113.42", true},
+		{"198.51.100.7", true},
+		{"198.51.100.8", false},
+		{"unknown", false},
+	}
+
+	for _, tt := range tests {
+		if allowlisted := s.IsIPAddressAllowlisted(tt.ipAddr); allowlisted != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.ipAddr, tt.expected, allowlisted)
+		}
+	}
+}
+
+func TestHashIPAddress(t *testing.T) {
+	hash := HashIPAddress("survey-1", "203.0.113.0/24", "salt")
+	if len(hash) != 64 {
+		t.Errorf("expected hex encoded hash, got %s", hash)
+	}
+	if hash == HashIPAddress("survey-2", "203.0.113.0/24", "salt") {
+		t.Errorf("expected hash to depend on survey")
+	}
+	if hash == HashIPAddress("survey-1", "203.0.113.0/24", "other") {
+		t.Errorf("expected hash to depend on salt")
+	}
+}
+
+func TestMaxSessionsPerIPAddress(t *testing.T) {
+	if max := ipProtectionTestConfig(nil).MaxSessionsPerIPAddress(); max != 1 {
+		t.Errorf("expected 1 session by default, got %d", max)
+	}
+	if max := ipProtectionTestConfig(&IPProtection{MaxSessions: 3}).MaxSessionsPerIPAddress(); max != 3 {
+		t.Errorf("expected 3 sessions, got %d", max)
+	}
+}
@@ -0,0 +1,242 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+const DefaultLocale = "en"
+
+// Translation overrides texts of the survey for a given locale,
+// it's read from metadata.<locale>.yaml and questions.<locale>.yaml files
+type Translation struct {
+	Locale      string                `json:"locale" yaml:"-"`
+	Title       string                `json:"title,omitempty" yaml:"title,omitempty"`
+	Intro       string                `json:"intro,omitempty" yaml:"intro,omitempty"`
+	Outro       string                `json:"outro,omitempty" yaml:"outro,omitempty"`
+	ScoreOutros []ScoreOutro          `json:"scoreOutros,omitempty" yaml:"scoreOutros,omitempty"`
+	Pages       []PageTranslation     `json:"pages,omitempty" yaml:"pages,omitempty"`
+	Questions   []QuestionTranslation `json:"questions,omitempty" yaml:"questions,omitempty"`
+}
+
+type PageTranslation struct {
+	ID          string `json:"id" yaml:"id"`
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+type QuestionTranslation struct {
+	ID          string `json:"id" yaml:"id"`
+	Label       string `json:"label" yaml:"label"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// labels of option values
+	Options []Option `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// Locales returns the default locale followed by translated locales
+func (s *SurveyConfig) Locales() []string {
+	locales := []string{s.DefaultLocale}
+	for _, t := range s.Translations {
+		locales = append(locales, t.Locale)
+	}
+
+	return locales
+}
+
+// NegotiateLocale picks a survey locale from ?lang= parameter or Accept-Language header,
+// falls back to the default locale
+func (s *SurveyConfig) NegotiateLocale(lang string, acceptLanguage string) string {
+	locales := s.Locales()
+	if len(locales) == 1 {
+		return s.DefaultLocale
+	}
+
+	desired, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	if lang != "" {
+		if tag, err := language.Parse(lang); err == nil {
+			desired = append([]language.Tag{tag}, desired...)
+		}
+	}
+	if len(desired) == 0 {
+		return s.DefaultLocale
+	}
+
+	supported := []language.Tag{}
+	for _, l := range locales {
+		supported = append(supported, language.Make(l))
+	}
+
+	_, index, confidence := language.NewMatcher(supported).Match(desired...)
+	if confidence == language.No {
+		return s.DefaultLocale
+	}
+
+	return locales[index]
+}
+
+// Localize returns a copy of the config with texts in a given locale,
+// texts which are not translated are kept in the default locale
+func (s *SurveyConfig) Localize(locale string) *SurveyConfig {
+	localized := *s
+	localized.Translations = nil
+
+	var t *Translation
+	for i := range s.Translations {
+		if s.Translations[i].Locale == locale {
+			t = &s.Translations[i]
+			break
+		}
+	}
+	if t == nil || s.Questions == nil {
+		return &localized
+	}
+
+	localized.Title = translate(s.Title, t.Title)
+	localized.Intro = translate(s.Intro, t.Intro)
+	localized.Outro = translate(s.Outro, t.Outro)
+
+	localized.ScoreOutros = slices.Clone(s.ScoreOutros)
+	for i, o := range localized.ScoreOutros {
+		for _, ot := range t.ScoreOutros {
+			if ot.MinScore == o.MinScore {
+				localized.ScoreOutros[i].Outro = translate(o.Outro, ot.Outro)
+				break
+			}
+		}
+	}
+
+	questions := *s.Questions
+	questions.Pages = slices.Clone(s.Questions.Pages)
+	for i, p := range questions.Pages {
+		for _, pt := range t.Pages {
+			if pt.ID == p.ID {
+				p.Title = translate(p.Title, pt.Title)
+				p.Description = translate(p.Description, pt.Description)
+				questions.Pages[i] = p
+				break
+			}
+		}
+	}
+
+	questions.Questions = slices.Clone(s.Questions.Questions)
+	for i, q := range questions.Questions {
+		for _, qt := range t.Questions {
+			if qt.ID == q.ID {
+				questions.Questions[i] = q.translate(qt)
+				break
+			}
+		}
+	}
+	localized.Questions = &questions
+
+	return &localized
+}
+
+func (q Question) translate(qt QuestionTranslation) Question {
+	q.Label = translate(q.Label, qt.Label)
+	q.Description = translate(q.Description, qt.Description)
+
+	if len(qt.Options) > 0 {
+		labels := []Option{}
+		for _, value := range q.Options {
+			label := q.optionLabel(value)
+			for _, o := range qt.Options {
+				if o.Value == value && o.Label != "" {
+					label = o.Label
+					break
+				}
+			}
+
+			if label != value {
+				labels = append(labels, Option{Value: value, Label: label})
+			}
+		}
+		q.OptionLabels = labels
+	}
+
+	return q
+}
+
+func translate(text string, translation string) string {
+	if translation == "" {
+		return text
+	}
+
+	return translation
+}
+
+// validateTranslations checks that every locale covers every question ID
+func (s *SurveyConfig) validateTranslations() error {
+	if len(s.Translations) == 0 {
+		return nil
+	}
+
+	if _, err := language.Parse(s.DefaultLocale); err != nil {
+		return fmt.Errorf("metadata.defaultLocale is invalid: %s", s.DefaultLocale)
+	}
+
+	questions := make(map[string]Question)
+	for _, q := range s.Questions.Questions {
+		if q.ID == "" {
+			return fmt.Errorf("questions[].id is required when translations are used")
+		}
+		questions[q.ID] = q
+	}
+
+	uniqueLocales := map[string]bool{
+		strings.ToLower(s.DefaultLocale): true,
+	}
+	for _, t := range s.Translations {
+		if _, err := language.Parse(t.Locale); err != nil {
+			return fmt.Errorf("locale is invalid: %s", t.Locale)
+		}
+		if _, ok := uniqueLocales[strings.ToLower(t.Locale)]; ok {
+			return fmt.Errorf("locale is duplicated: %s", t.Locale)
+		}
+		uniqueLocales[strings.ToLower(t.Locale)] = true
+
+		translated := make(map[string]bool)
+		for _, qt := range t.Questions {
+			q, ok := questions[qt.ID]
+			if !ok {
+				return fmt.Errorf("questions.%s.yaml: question is not found: %s", t.Locale, qt.ID)
+			}
+			if _, ok := translated[qt.ID]; ok {
+				return fmt.Errorf("questions.%s.yaml: question is duplicated: %s", t.Locale, qt.ID)
+			}
+			translated[qt.ID] = true
+
+			if qt.Label == "" {
+				return fmt.Errorf("questions.%s.yaml: questions[].label is required: %s", t.Locale, qt.ID)
+			}
+			for _, o := range qt.Options {
+				if !slices.Contains(q.Options, o.Value) {
+					return fmt.Errorf("questions.%s.yaml: option is not found in question %s: %s", t.Locale, qt.ID, o.Value)
+				}
+			}
+		}
+
+		for _, q := range s.Questions.Questions {
+			if _, ok := translated[q.ID]; !ok {
+				return fmt.Errorf("questions.%s.yaml: question is not translated: %s", t.Locale, q.ID)
+			}
+		}
+
+		for _, pt := range t.Pages {
+			if _, err := s.Questions.FindPageByID(pt.ID); err != nil {
+				return fmt.Errorf("questions.%s.yaml: page is not found: %s", t.Locale, pt.ID)
+			}
+		}
+
+		for _, ot := range t.ScoreOutros {
+			if !slices.ContainsFunc(s.ScoreOutros, func(o ScoreOutro) bool { return o.MinScore == ot.MinScore }) {
+				return fmt.Errorf("metadata.%s.yaml: score outro is not found: %d", t.Locale, ot.MinScore)
+			}
+		}
+	}
+
+	return nil
+}
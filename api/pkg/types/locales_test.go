@@ -0,0 +1,151 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func localizedSurveyConfig() *SurveyConfig {
+	return &SurveyConfig{
+		Title:         "Survey",
+		DefaultLocale: "en",
+		Questions: &Questions{
+			Questions: []Question{
+				{
+					ID:      "city",
+					Type:    QuestionType_DropdownSingle,
+					Label:   "City",
+					Options: []string{"ber", "muc"},
+					OptionLabels: []Option{
+						{Value: "ber", Label: "Berlin"},
+					},
+				},
+				{
+					ID:    "name",
+					Type:  QuestionType_ShortText,
+					Label: "Name",
+				},
+			},
+		},
+		Translations: []Translation{
+			{
+				Locale: "de",
+				Title:  "Umfrage",
+				Questions: []QuestionTranslation{
+					{
+						ID:    "city",
+						Label: "Stadt",
+						Options: []Option{
+							{Value: "muc", Label: "München"},
+						},
+					},
+					{
+						ID:    "name",
+						Label: "Name",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	config := localizedSurveyConfig()
+
+	tests := []struct {
+		lang           string
+		acceptLanguage string
+		expected       string
+	}{
+		{"", "", "en"},
+		{"de", "", "de"},
+		{"", "de-DE,de;q=0.9,en;q=0.8", "de"},
+		{"en", "de-DE,de;q=0.9", "en"},
+		{"", "fr-FR", "en"},
+		{"invalid locale", "", "en"},
+	}
+
+	for _, tt := range tests {
+		if locale := config.NegotiateLocale(tt.lang, tt.acceptLanguage); locale != tt.expected {
+			t.Errorf("NegotiateLocale(%q, %q) = %s, expected %s", tt.lang, tt.acceptLanguage, locale, tt.expected)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	config := localizedSurveyConfig()
+
+	localized := config.Localize("de")
+	if localized.Title != "Umfrage" {
+		t.Errorf("unexpected title: %s", localized.Title)
+	}
+	if localized.Translations != nil {
+		t.Errorf("expected translations to be removed")
+	}
+
+	q := localized.Questions.Questions[0]
+	if q.Label != "Stadt" {
+		t.Errorf("unexpected label: %s", q.Label)
+	}
+	if !reflect.DeepEqual(q.Options, []string{"ber", "muc"}) {
+		t.Errorf("unexpected options: %v", q.Options)
+	}
+	expectedLabels := []Option{{Value: "ber", Label: "Berlin"}, {Value: "muc", Label: "München"}}
+	if !reflect.DeepEqual(q.OptionLabels, expectedLabels) {
+		t.Errorf("unexpected option labels: %v", q.OptionLabels)
+	}
+
+	// original config is not changed
+	if config.Title != "Survey" || config.Questions.Questions[0].Label != "City" {
+		t.Errorf("expected original config to be unchanged")
+	}
+
+	defaultLocalized := config.Localize("en")
+	if defaultLocalized.Questions.Questions[0].Label != "City" {
+		t.Errorf("unexpected default label: %s", defaultLocalized.Questions.Questions[0].Label)
+	}
+}
+
+func TestValidateTranslations(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*SurveyConfig)
+		valid  bool
+	}{
+		{"valid", func(c *SurveyConfig) {}, true},
+		{"missing question", func(c *SurveyConfig) {
+			c.Translations[0].Questions = c.Translations[0].Questions[:1]
+		}, false},
+		{"unknown question", func(c *SurveyConfig) {
+			c.Translations[0].Questions = append(c.Translations[0].Questions, QuestionTranslation{ID: "age", Label: "Alter"})
+		}, false},
+		{"empty label", func(c *SurveyConfig) {
+			c.Translations[0].Questions[1].Label = ""
+		}, false},
+		{"unknown option", func(c *SurveyConfig) {
+			c.Translations[0].Questions[0].Options[0].Value = "ham"
+		}, false},
+		{"unknown page", func(c *SurveyConfig) {
+			c.Translations[0].Pages = []PageTranslation{{ID: "intro", Title: "Einleitung"}}
+		}, false},
+		{"default locale", func(c *SurveyConfig) {
+			c.Translations[0].Locale = "en"
+		}, false},
+		{"question without id", func(c *SurveyConfig) {
+			c.Questions.Questions[1].ID = ""
+		}, false},
+	}
+
+	for _, tt := range tests {
+		config := localizedSurveyConfig()
+		tt.modify(config)
+
+		err := config.validateTranslations()
+		if tt.valid && err != nil {
+			t.Errorf("%s: expected valid, got %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expected error, got nil", tt.name)
+		}
+	}
+}
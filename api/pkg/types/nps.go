@@ -0,0 +1,57 @@
+package types
+
+import "math"
+
+const (
+	NPS_Min = 0
+	NPS_Max = 10
+
+	npsPromoterMin = 9
+	npsPassiveMin  = 7
+)
+
+// AnswerValueCount is the number of answers with a given value to a question
+type AnswerValueCount struct {
+	QuestionID string
+	Value      int64
+	Count      int
+}
+
+type NPSStats struct {
+	QuestionID   string `json:"question_id"`
+	AnswersCount int    `json:"answers_count"`
+	Promoters    int    `json:"promoters"`
+	Passives     int    `json:"passives"`
+	Detractors   int    `json:"detractors"`
+	// from -100 to 100
+	Score int `json:"score"`
+}
+
+// NewNPSStats groups scores of a question into promoters (9-10), passives (7-8) and detractors (0-6)
+func NewNPSStats(questionID string, counts []AnswerValueCount) NPSStats {
+	stats := NPSStats{
+		QuestionID: questionID,
+	}
+
+	for _, c := range counts {
+		if c.QuestionID != questionID {
+			continue
+		}
+
+		switch {
+		case c.Value >= npsPromoterMin:
+			stats.Promoters += c.Count
+		case c.Value >= npsPassiveMin:
+			stats.Passives += c.Count
+		default:
+			stats.Detractors += c.Count
+		}
+		stats.AnswersCount += c.Count
+	}
+
+	if stats.AnswersCount > 0 {
+		stats.Score = int(math.Round(float64(stats.Promoters-stats.Detractors) * 100 / float64(stats.AnswersCount)))
+	}
+
+	return stats
+}
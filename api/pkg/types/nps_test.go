@@ -0,0 +1,43 @@
+package types
+
+import "testing"
+
+func TestNewNPSStats(t *testing.T) {
+	cases := []struct {
+		name     string
+		counts   []AnswerValueCount
+		expected NPSStats
+	}{
+		{
+			name:     "no answers",
+			counts:   []AnswerValueCount{},
+			expected: NPSStats{QuestionID: "nps"},
+		},
+		{
+			name: "mixed answers",
+			counts: []AnswerValueCount{
+				{QuestionID: "nps", Value: 10, Count: 4},
+				{QuestionID: "nps", Value: 9, Count: 1},
+				{QuestionID: "nps", Value: 8, Count: 2},
+				{QuestionID: "nps", Value: 6, Count: 1},
+				{QuestionID: "nps", Value: 0, Count: 1},
+				{QuestionID: "other", Value: 0, Count: 10},
+			},
+			expected: NPSStats{QuestionID: "nps", AnswersCount: 9, Promoters: 5, Passives: 2, Detractors: 2, Score: 33},
+		},
+		{
+			name: "only detractors",
+			counts: []AnswerValueCount{
+				{QuestionID: "nps", Value: 3, Count: 2},
+			},
+			expected: NPSStats{QuestionID: "nps", AnswersCount: 2, Detractors: 2, Score: -100},
+		},
+	}
+
+	for _, c := range cases {
+		stats := NewNPSStats("nps", c.counts)
+		if stats != c.expected {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, stats)
+		}
+	}
+}
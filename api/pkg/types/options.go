@@ -0,0 +1,83 @@
+package types
+
+import "gopkg.in/yaml.v3"
+
+// Option is an answer choice, the value is stored in answers and the label is displayed to respondents
+type Option struct {
+	Value string `json:"value" yaml:"value"`
+	Label string `json:"label" yaml:"label"`
+}
+
+// UnmarshalYAML accepts either a plain string or a {value, label} object
+func (o *Option) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		o.Value = value.Value
+		return nil
+	}
+
+	type option Option
+	return value.Decode((*option)(o))
+}
+
+// splitOptions returns option values and options which have labels different from values
+func splitOptions(options []Option) ([]string, []Option) {
+	if options == nil {
+		return nil, nil
+	}
+
+	values := []string{}
+	var labels []Option
+	for _, o := range options {
+		values = append(values, o.Value)
+		if o.Label != "" && o.Label != o.Value {
+			labels = append(labels, o)
+		}
+	}
+
+	return values, labels
+}
+
+// optionLabel returns the label displayed for a given option value
+func (q Question) optionLabel(value string) string {
+	for _, o := range q.OptionLabels {
+		if o.Value == value {
+			return o.Label
+		}
+	}
+
+	return value
+}
+
+// UnmarshalYAML reads options of a question
+func (q *Question) UnmarshalYAML(value *yaml.Node) error {
+	type question Question
+	raw := struct {
+		question `yaml:",inline"`
+		Options  []Option `yaml:"options,omitempty"`
+	}{}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	*q = Question(raw.question)
+	q.Options, q.OptionLabels = splitOptions(raw.Options)
+
+	return nil
+}
+
+// UnmarshalYAML reads options of a variable
+func (v *Variable) UnmarshalYAML(value *yaml.Node) error {
+	type variable Variable
+	raw := struct {
+		variable `yaml:",inline"`
+		Options  []Option `yaml:"options,omitempty"`
+	}{}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	*v = Variable(raw.variable)
+	v.Options, v.OptionLabels = splitOptions(raw.Options)
+
+	return nil
+}
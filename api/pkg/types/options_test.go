@@ -0,0 +1,79 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestQuestionUnmarshalYAMLOptions(t *testing.T) {
+	data := `
+type: single-choice
+label: What is the capital of Germany?
+options:
+  - Berlin
+  - value: munich
+    label: Munich
+  - value: hamburg
+`
+	q := Question{}
+	if err := yaml.Unmarshal([]byte(data), &q); err != nil {
+		t.Fatalf("unable to parse question: %v", err)
+	}
+
+	if q.Label != "What is the capital of Germany?" {
+		t.Errorf("unexpected label: %s", q.Label)
+	}
+	if !reflect.DeepEqual(q.Options, []string{"Berlin", "munich", "hamburg"}) {
+		t.Errorf("unexpected options: %v", q.Options)
+	}
+	if !reflect.DeepEqual(q.OptionLabels, []Option{{Value: "munich", Label: "Munich"}}) {
+		t.Errorf("unexpected option labels: %v", q.OptionLabels)
+	}
+
+	answer := &SingleOptionAnswer{AnswerValue: "munich"}
+	if err := answer.Validate(q); err != nil {
+		t.Errorf("expected option value to be valid, got %v", err)
+	}
+	answer = &SingleOptionAnswer{AnswerValue: "Munich"}
+	if err := answer.Validate(q); err == nil {
+		t.Errorf("expected option label to be invalid")
+	}
+}
+
+func TestSetOptionsFromVariablesLabels(t *testing.T) {
+	data := `
+variables:
+  - id: cities
+    type: list
+    options:
+      - value: ber
+        label: Berlin
+      - Munich
+`
+	variables := &Variables{}
+	if err := yaml.Unmarshal([]byte(data), variables); err != nil {
+		t.Fatalf("unable to parse variables: %v", err)
+	}
+
+	config := &SurveyConfig{
+		Variables: variables,
+		Questions: &Questions{
+			Questions: []Question{
+				{Type: QuestionType_DropdownSingle, Label: "City", OptionsFromVariable: ptrString("cities")},
+			},
+		},
+	}
+	if err := config.SetOptionsFromVariables(); err != nil {
+		t.Fatalf("unable to set options: %v", err)
+	}
+
+	q := config.Questions.Questions[0]
+	if !reflect.DeepEqual(q.Options, []string{"ber", "Munich"}) {
+		t.Errorf("unexpected options: %v", q.Options)
+	}
+	if !reflect.DeepEqual(q.OptionLabels, []Option{{Value: "ber", Label: "Berlin"}}) {
+		t.Errorf("unexpected option labels: %v", q.OptionLabels)
+	}
+}
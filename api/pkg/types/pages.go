@@ -0,0 +1,77 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Page struct {
+	ID                 string   `json:"id" yaml:"id"`
+	Title              string   `json:"title" yaml:"title"`
+	Description        string   `json:"description" yaml:"description"`
+	RandomizeQuestions bool     `json:"randomizeQuestions,omitempty" yaml:"randomizeQuestions,omitempty"`
+	Questions          []string `json:"questions" yaml:"questions"`
+}
+
+// validatePages checks that pages cover all questions in the order they are defined
+func (s *Questions) validatePages() error {
+	if len(s.Pages) == 0 {
+		return nil
+	}
+
+	uniqueIDs := make(map[string]bool)
+	pageQuestions := []string{}
+	for _, p := range s.Pages {
+		if p.ID == "" {
+			return errors.New("pages[].id is required")
+		}
+		if _, ok := uniqueIDs[p.ID]; ok {
+			return fmt.Errorf("pages[].id must be unique: %s", p.ID)
+		}
+		uniqueIDs[p.ID] = true
+
+		if len(p.Questions) == 0 {
+			return fmt.Errorf("pages[].questions must have at least one question: %s", p.ID)
+		}
+		pageQuestions = append(pageQuestions, p.Questions...)
+	}
+
+	if len(pageQuestions) != len(s.Questions) {
+		return errors.New("pages[].questions must include every question exactly once")
+	}
+	for i, q := range s.Questions {
+		if q.ID == "" {
+			return errors.New("questions[].id is required when pages are used")
+		}
+		if pageQuestions[i] != q.ID {
+			return fmt.Errorf("pages[].questions must follow the order of questions: %s", pageQuestions[i])
+		}
+	}
+
+	return nil
+}
+
+func (s *Questions) FindPageByID(pageID string) (*Page, error) {
+	for _, p := range s.Pages {
+		if p.ID == pageID {
+			page := p
+			return &page, nil
+		}
+	}
+
+	return nil, errors.New("page not found")
+}
+
+// FindPageByQuestionID returns the page containing a given question
+func (s *Questions) FindPageByQuestionID(questionID string) (*Page, error) {
+	for _, p := range s.Pages {
+		for _, id := range p.Questions {
+			if id == questionID {
+				page := p
+				return &page, nil
+			}
+		}
+	}
+
+	return nil, errors.New("page not found")
+}
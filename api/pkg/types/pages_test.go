@@ -0,0 +1,74 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuestionsValidatePages(t *testing.T) {
+	cases := []struct {
+		name      string
+		pages     []Page
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			name:      "no pages",
+			pages:     nil,
+			expectErr: false,
+		},
+		{
+			name: "valid",
+			pages: []Page{
+				{ID: "about", Questions: []string{"q1", "q2"}},
+				{ID: "contact", Questions: []string{"q3"}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "duplicate page",
+			pages: []Page{
+				{ID: "about", Questions: []string{"q1", "q2"}},
+				{ID: "about", Questions: []string{"q3"}},
+			},
+			expectErr: true,
+			errMsg:    "pages[].id must be unique",
+		},
+		{
+			name: "missing question",
+			pages: []Page{
+				{ID: "about", Questions: []string{"q1", "q2"}},
+			},
+			expectErr: true,
+			errMsg:    "must include every question exactly once",
+		},
+		{
+			name: "wrong order",
+			pages: []Page{
+				{ID: "about", Questions: []string{"q1", "q3"}},
+				{ID: "contact", Questions: []string{"q2"}},
+			},
+			expectErr: true,
+			errMsg:    "must follow the order of questions",
+		},
+	}
+
+	for _, c := range cases {
+		questions := &Questions{
+			Pages: c.pages,
+			Questions: []Question{
+				{ID: "q1", Type: QuestionType_ShortText, Label: "Name"},
+				{ID: "q2", Type: QuestionType_Date, Label: "Birthday"},
+				{ID: "q3", Type: QuestionType_Email, Label: "Email"},
+			},
+		}
+		err := questions.Validate()
+		if c.expectErr {
+			if err == nil || !strings.Contains(err.Error(), c.errMsg) {
+				t.Errorf("%s: expected error containing %s, got %v", c.name, c.errMsg, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: expected no error, got %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pipeRegexp matches answer references like {{question_id}}
+var pipeRegexp = regexp.MustCompile(`\{\{\s*([\w\-]+)\s*\}\}`)
+
+// PipeAnswers returns a copy of the question with answer references in label,
+// description and options replaced by the given answers keyed by question ID.
+// Options copied from another question keep their labels from the survey config.
+func (q Question) PipeAnswers(answers map[string]Answer, s *SurveyConfig) Question {
+	pipe := func(text string) string {
+		return pipeRegexp.ReplaceAllStringFunc(text, func(ref string) string {
+			questionID := pipeRegexp.FindStringSubmatch(ref)[1]
+			answer, ok := answers[questionID]
+			if !ok || answer == nil {
+				return ""
+			}
+
+			return strings.Join(answerStrings(answer), ", ")
+		})
+	}
+
+	q.Label = pipe(q.Label)
+	q.Description = pipe(q.Description)
+
+	options := []string{}
+	var labels []Option
+	if q.OptionsFromQuestion != nil {
+		if answer, ok := answers[*q.OptionsFromQuestion]; ok && answer != nil {
+			var source Question
+			if s != nil && s.Questions != nil {
+				if found, err := s.FindQuestionByID(*q.OptionsFromQuestion); err == nil {
+					source = *found
+				}
+			}
+
+			for _, value := range answerStrings(answer) {
+				options = append(options, value)
+				if label := source.optionLabel(value); label != value {
+					labels = append(labels, Option{Value: value, Label: label})
+				}
+			}
+		}
+	}
+	for _, option := range q.Options {
+		options = append(options, pipe(option))
+	}
+	if len(options) > 0 {
+		q.Options = options
+	}
+
+	for _, o := range q.OptionLabels {
+		labels = append(labels, Option{Value: pipe(o.Value), Label: pipe(o.Label)})
+	}
+	if labels != nil {
+		q.OptionLabels = labels
+	}
+
+	return q
+}
+
+// pipedQuestionIDs returns IDs of the questions referenced by the question texts and options
+func (q Question) pipedQuestionIDs() []string {
+	ids := []string{}
+	texts := append([]string{q.Label, q.Description}, q.Options...)
+	for _, o := range q.OptionLabels {
+		texts = append(texts, o.Label)
+	}
+	for _, text := range texts {
+		for _, match := range pipeRegexp.FindAllStringSubmatch(text, -1) {
+			ids = append(ids, match[1])
+		}
+	}
+
+	return ids
+}
+
+// validatePipes checks that piped answers reference previous questions
+func (s *Questions) validatePipes() error {
+	previous := make(map[string]Question)
+	for _, q := range s.Questions {
+		for _, id := range q.pipedQuestionIDs() {
+			if _, ok := previous[id]; !ok {
+				return fmt.Errorf("questions[].label, description and options must reference a previous question: %s", id)
+			}
+		}
+
+		if q.OptionsFromQuestion != nil {
+			if q.Type != QuestionType_DropdownSingle && q.Type != QuestionType_DropdownMultiple && q.Type != QuestionType_Ranking {
+				return fmt.Errorf("questions[].optionsFromQuestion is not supported for type: %s", q.Type)
+			}
+			source, ok := previous[*q.OptionsFromQuestion]
+			if !ok {
+				return fmt.Errorf("questions[].optionsFromQuestion must reference a previous question: %s", *q.OptionsFromQuestion)
+			}
+			if source.Type != QuestionType_DropdownSingle && source.Type != QuestionType_DropdownMultiple && source.Type != QuestionType_Ranking {
+				return fmt.Errorf("questions[].optionsFromQuestion must reference a choice question: %s", *q.OptionsFromQuestion)
+			}
+		}
+
+		if q.ID != "" {
+			previous[q.ID] = q
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package types
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPipeAnswers(t *testing.T) {
+	answers := map[string]Answer{
+		"q_rating": &NumberAnswer{AnswerValue: 4},
+		"q_cities": &MultiOptionsAnswer{AnswerValue: []string{"Berlin", "Hamburg"}},
+	}
+	config := &SurveyConfig{Questions: &Questions{Questions: []Question{
+		{ID: "q_cities", Type: QuestionType_DropdownMultiple, Options: []string{"Berlin", "Hamburg"}, OptionLabels: []Option{{Value: "Hamburg", Label: "Hamburg (HH)"}}},
+	}}}
+
+	cases := []struct {
+		name           string
+		question       Question
+		config         *SurveyConfig
+		expectedLabel  string
+		expectedDesc   string
+		expectedOpts   string
+		expectedLabels []Option
+	}{
+		{
+			name: "texts and options",
+			question: Question{
+				Label:               "Why did you rate {{q_rating}} stars?",
+				Description:         "You selected {{ q_cities }}{{q_unknown}}",
+				OptionsFromQuestion: ptrString("q_cities"),
+				Options:             []string{"None of {{q_cities}}"},
+			},
+			expectedLabel: "Why did you rate 4 stars?",
+			expectedDesc:  "You selected Berlin, Hamburg",
+			expectedOpts:  "Berlin|Hamburg|None of Berlin, Hamburg",
+		},
+		{
+			name: "option labels from question",
+			question: Question{
+				OptionsFromQuestion: ptrString("q_cities"),
+				Options:             []string{"other"},
+				OptionLabels:        []Option{{Value: "other", Label: "Other"}},
+			},
+			config:         config,
+			expectedOpts:   "Berlin|Hamburg|other",
+			expectedLabels: []Option{{Value: "Hamburg", Label: "Hamburg (HH)"}, {Value: "other", Label: "Other"}},
+		},
+	}
+
+	for _, c := range cases {
+		original := c.question.Label
+		piped := c.question.PipeAnswers(answers, c.config)
+		if piped.Label != c.expectedLabel {
+			t.Errorf("%s: unexpected label: %s", c.name, piped.Label)
+		}
+		if piped.Description != c.expectedDesc {
+			t.Errorf("%s: unexpected description: %s", c.name, piped.Description)
+		}
+		if strings.Join(piped.Options, "|") != c.expectedOpts {
+			t.Errorf("%s: unexpected options: %v", c.name, piped.Options)
+		}
+		if !reflect.DeepEqual(piped.OptionLabels, c.expectedLabels) {
+			t.Errorf("%s: unexpected option labels: %v", c.name, piped.OptionLabels)
+		}
+		if c.question.Label != original {
+			t.Errorf("%s: original question must not be modified", c.name)
+		}
+	}
+}
+
+func TestQuestionsValidatePipes(t *testing.T) {
+	cases := []struct {
+		questions []Question
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			questions: []Question{
+				{ID: "q1", Type: QuestionType_DropdownMultiple, Label: "Cities"},
+				{ID: "q2", Type: QuestionType_DropdownSingle, Label: "Favourite of {{q1}}", OptionsFromQuestion: ptrString("q1")},
+			},
+			expectErr: false,
+		},
+		{
+			questions: []Question{
+				{ID: "q1", Type: QuestionType_ShortText, Label: "Why {{q2}}?"},
+				{ID: "q2", Type: QuestionType_ShortText, Label: "Name"},
+			},
+			expectErr: true,
+			errMsg:    "must reference a previous question: q2",
+		},
+		{
+			questions: []Question{
+				{ID: "q1", Type: QuestionType_ShortText, Label: "Name"},
+				{ID: "q2", Type: QuestionType_DropdownSingle, Label: "Pick", OptionsFromQuestion: ptrString("q1")},
+			},
+			expectErr: true,
+			errMsg:    "must reference a choice question",
+		},
+	}
+
+	for _, c := range cases {
+		questions := &Questions{Questions: c.questions}
+		err := questions.validatePipes()
+		if c.expectErr {
+			if err == nil || !strings.Contains(err.Error(), c.errMsg) {
+				t.Errorf("expected error containing %s, got %v", c.errMsg, err)
+			}
+		} else if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// prefix of query params which prefill answers, e.g. prefill.rating=5
+	PrefillParamPrefix = "prefill."
+	// query param with the hex encoded HMAC-SHA256 signature of the prefilled answers
+	PrefillSignatureParam = "signature"
+)
+
+// PrefillValues returns prefilled values keyed by question ID from query params,
+// the signature is verified with the given secret if the survey requires signed prefill
+func (s *SurveyConfig) PrefillValues(urlSlug string, query url.Values, secret string) (map[string][]string, error) {
+	values := url.Values{}
+	for key, v := range query {
+		if questionID, ok := strings.CutPrefix(key, PrefillParamPrefix); ok {
+			values[questionID] = v
+		}
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	prefill := s.Prefill()
+	if prefill == "" {
+		return nil, errors.New("prefilled answers are not allowed")
+	}
+
+	if prefill == PrefillType_Signed {
+		if secret == "" {
+			return nil, errors.New("prefill secret is not configured")
+		}
+
+		signature, err := hex.DecodeString(query.Get(PrefillSignatureParam))
+		if err != nil || !hmac.Equal(signature, signPrefillValues(urlSlug, values, secret)) {
+			return nil, errors.New("prefill signature is invalid")
+		}
+	}
+
+	for questionID := range values {
+		if _, err := s.FindQuestionByID(questionID); err != nil {
+			return nil, fmt.Errorf("prefilled question not found: %s", questionID)
+		}
+	}
+
+	return values, nil
+}
+
+// SignPrefillValues returns the hex encoded signature of prefilled values keyed by question ID,
+// it's bound to the survey so the same link can't be used for another survey
+func SignPrefillValues(urlSlug string, values map[string][]string, secret string) string {
+	return hex.EncodeToString(signPrefillValues(urlSlug, values, secret))
+}
+
+func signPrefillValues(urlSlug string, values url.Values, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	// Encode sorts values by key, so the signature doesn't depend on the order of query params
+	mac.Write([]byte(urlSlug + "?" + values.Encode()))
+
+	return mac.Sum(nil)
+}
+
+// PrefillAnswer converts prefilled values to the answer of the question, the answer is not validated
+func (q Question) PrefillAnswer(values []string) (Answer, error) {
+	answer, err := q.GetAnswerType()
+	if err != nil {
+		return nil, err
+	}
+
+	if a, ok := answer.(*MultiOptionsAnswer); ok {
+		a.AnswerValue = values
+		return a, nil
+	}
+
+	if len(values) != 1 {
+		return nil, errors.New("single value is required")
+	}
+	value := values[0]
+
+	switch a := answer.(type) {
+	case *SingleOptionAnswer:
+		a.AnswerValue = value
+	case *TextAnswer:
+		a.AnswerValue = value
+	case *DateAnswer:
+		a.AnswerValue = value
+	case *EmailAnswer:
+		a.AnswerValue = value
+	case *NumberAnswer:
+		a.AnswerValue, err = strconv.ParseInt(value, 10, 64)
+	case *DecimalAnswer:
+		a.AnswerValue, err = strconv.ParseFloat(value, 64)
+	case *BoolAnswer:
+		a.AnswerValue, err = strconv.ParseBool(value)
+	default:
+		return nil, fmt.Errorf("question type %s can't be prefilled", q.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("value is invalid: %s", value)
+	}
+
+	return answer, nil
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestPrefillValues(t *testing.T) {
+	questions := &Questions{Questions: []Question{
+		{ID: "rating", Type: QuestionType_Rating},
+		{ID: "colors", Type: QuestionType_DropdownMultiple},
+	}}
+	values := map[string][]string{"rating": {"5"}, "colors": {"red", "blue"}}
+	signature := SignPrefillValues("survey", values, "secret")
+
+	tests := []struct {
+		name     string
+		prefill  PrefillType
+		query    url.Values
+		secret   string
+		expected map[string][]string
+		wantErr  bool
+	}{
+		{"no prefill", "", url.Values{"lang": {"de"}}, "", nil, false},
+		{"not allowed", "", url.Values{"prefill.rating": {"5"}}, "", nil, true},
+		{"unsigned", PrefillType_Unsigned, url.Values{"prefill.rating": {"5"}}, "", map[string][]string{"rating": {"5"}}, false},
+		{"unknown question", PrefillType_Unsigned, url.Values{"prefill.city": {"Berlin"}}, "", nil, true},
+		{"signed", PrefillType_Signed, url.Values{"prefill.colors": {"red", "blue"}, "prefill.rating": {"5"}, "signature": {signature}}, "secret", values, false},
+		{"signed without secret", PrefillType_Signed, url.Values{"prefill.colors": {"red", "blue"}, "prefill.rating": {"5"}, "signature": {signature}}, "", nil, true},
+		{"tampered", PrefillType_Signed, url.Values{"prefill.colors": {"red", "blue"}, "prefill.rating": {"1"}, "signature": {signature}}, "secret", nil, true},
+		{"missing signature", PrefillType_Signed, url.Values{"prefill.rating": {"5"}}, "secret", nil, true},
+	}
+
+	for _, tt := range tests {
+		s := &SurveyConfig{Questions: questions, Security: &Security{Prefill: tt.prefill}}
+		prefilled, err := s.PrefillValues("survey", tt.query, tt.secret)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(prefilled, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, prefilled)
+		}
+	}
+}
+
+func TestPrefillSignatureIsBoundToSurvey(t *testing.T) {
+	values := map[string][]string{"rating": {"5"}}
+	if SignPrefillValues("survey-a", values, "secret") == SignPrefillValues("survey-b", values, "secret") {
+		t.Errorf("expected different signatures for different surveys")
+	}
+}
+
+func TestQuestionPrefillAnswer(t *testing.T) {
+	tests := []struct {
+		name     string
+		question Question
+		values   []string
+		expected Answer
+		wantErr  bool
+	}{
+		{"rating", Question{Type: QuestionType_Rating}, []string{"4"}, &NumberAnswer{AnswerValue: 4}, false},
+		{"rating invalid", Question{Type: QuestionType_Rating}, []string{"four"}, nil, true},
+		{"number", Question{Type: QuestionType_Number}, []string{"1.5"}, &DecimalAnswer{AnswerValue: 1.5}, false},
+		{"yes/no", Question{Type: QuestionType_YesNo}, []string{"true"}, &BoolAnswer{AnswerValue: true}, false},
+		{"single choice", Question{Type: QuestionType_DropdownSingle}, []string{"red"}, &SingleOptionAnswer{AnswerValue: "red"}, false},
+		{"multiple values", Question{Type: QuestionType_DropdownSingle}, []string{"red", "blue"}, nil, true},
+		{"multiple choice", Question{Type: QuestionType_DropdownMultiple}, []string{"red", "blue"}, &MultiOptionsAnswer{AnswerValue: []string{"red", "blue"}}, false},
+		{"file", Question{Type: QuestionType_File}, []string{"a.png"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		answer, err := tt.question.PrefillAnswer(tt.values)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(answer, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, answer)
+		}
+	}
+}
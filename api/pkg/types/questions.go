@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/gob"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -22,6 +23,9 @@ const (
 	QuestionType_YesNo            QuestionType = "yes-no"
 	QuestionType_Email            QuestionType = "email"
 	QuestionType_File             QuestionType = "file"
+	QuestionType_Matrix           QuestionType = "matrix"
+	QuestionType_Number           QuestionType = "number"
+	QuestionType_NPS              QuestionType = "nps"
 )
 
 var supportedQuestionTypes = map[QuestionType]bool{
@@ -35,10 +39,15 @@ var supportedQuestionTypes = map[QuestionType]bool{
 	QuestionType_YesNo:            true,
 	QuestionType_Email:            true,
 	QuestionType_File:             true,
+	QuestionType_Matrix:           true,
+	QuestionType_Number:           true,
+	QuestionType_NPS:              true,
 }
 
 type Questions struct {
-	Questions []Question `json:"questions" yaml:"questions"`
+	RandomizeQuestions bool       `json:"randomizeQuestions,omitempty" yaml:"randomizeQuestions,omitempty"`
+	Pages              []Page     `json:"pages,omitempty" yaml:"pages,omitempty"`
+	Questions          []Question `json:"questions" yaml:"questions"`
 }
 
 type Question struct {
@@ -49,16 +58,35 @@ type Question struct {
 	Min                 *int                `json:"min,omitempty" yaml:"min,omitempty"`
 	Max                 *int                `json:"max,omitempty" yaml:"max,omitempty"`
 	OptionsFromVariable *string             `json:"-" yaml:"optionsFromVariable,omitempty"`
-	Options             []string            `json:"options,omitempty" yaml:"options,omitempty"`
+	OptionsFromQuestion *string             `json:"optionsFromQuestion,omitempty" yaml:"optionsFromQuestion,omitempty"`
+	Options             []string            `json:"options,omitempty" yaml:"-"`
+	OptionLabels        []Option            `json:"optionLabels,omitempty" yaml:"-"`
+	AllowOther          bool                `json:"allowOther,omitempty" yaml:"allowOther,omitempty"`
+	RandomizeOptions    bool                `json:"randomizeOptions,omitempty" yaml:"randomizeOptions,omitempty"`
+	PinnedOptions       []string            `json:"pinnedOptions,omitempty" yaml:"pinnedOptions,omitempty"`
+	Pinned              bool                `json:"pinned,omitempty" yaml:"pinned,omitempty"`
+	Rows                []string            `json:"rows,omitempty" yaml:"rows,omitempty"`
+	Columns             []string            `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Multiple            bool                `json:"multiple,omitempty" yaml:"multiple,omitempty"`
+	Unit                string              `json:"unit,omitempty" yaml:"unit,omitempty"`
+	Correct             CorrectAnswer       `json:"correct,omitempty" yaml:"correct,omitempty"`
+	Points              *int                `json:"points,omitempty" yaml:"points,omitempty"`
 	UUID                string              `json:"uuid" yaml:"-"`
 	Validation          *QuestionValidation `json:"validation,omitempty" yaml:"validation,omitempty"`
+	Required            *bool               `json:"required,omitempty" yaml:"required,omitempty"`
+	ShowIf              *QuestionCondition  `json:"showIf,omitempty" yaml:"showIf,omitempty"`
+	JumpTo              []QuestionJump      `json:"jumpTo,omitempty" yaml:"jumpTo,omitempty"`
 }
 
 type QuestionValidation struct {
-	Min          *int      `json:"min,omitempty" yaml:"min,omitempty"`
-	Max          *int      `json:"max,omitempty" yaml:"max,omitempty"`
+	Min          *float64  `json:"min,omitempty" yaml:"min,omitempty"`
+	Max          *float64  `json:"max,omitempty" yaml:"max,omitempty"`
+	Step         *float64  `json:"step,omitempty" yaml:"step,omitempty"`
+	Precision    *int      `json:"precision,omitempty" yaml:"precision,omitempty"`
 	Formats      *[]string `json:"formats,omitempty" yaml:"formats,omitempty"`
 	MaxSizeBytes *string   `json:"max_size_bytes,omitempty" yaml:"max_size_bytes,omitempty"`
+	// length of the "Other" free-text option, Min and Max are the number of selected options in multiple choice questions
+	Other *QuestionValidation `json:"other,omitempty" yaml:"other,omitempty"`
 }
 
 func (s *Questions) Validate() error {
@@ -83,15 +111,30 @@ func (s *Questions) Validate() error {
 		}
 
 		if q.Validation != nil {
-			if err := q.Validation.Validate(); err != nil {
+			validate := q.Validation.Validate
+			if q.Type == QuestionType_Number {
+				validate = q.Validation.ValidateNumber
+			}
+			if err := validate(); err != nil {
 				return err
 			}
 		}
 
+		if err := q.validateCorrect(); err != nil {
+			return err
+		}
+
+		if q.AllowOther && q.Type != QuestionType_DropdownSingle && q.Type != QuestionType_DropdownMultiple {
+			return fmt.Errorf("questions[].allowOther is not supported for type: %s", q.Type)
+		}
+
 		switch q.Type {
 		case QuestionType_DropdownSingle:
 		case QuestionType_DropdownMultiple:
 		case QuestionType_Ranking:
+			if q.OptionsFromQuestion != nil {
+				break
+			}
 			if err := q.ValidateOptions(); err != nil {
 				return err
 			}
@@ -99,10 +142,22 @@ func (s *Questions) Validate() error {
 			if err := q.ValidateMinMax(); err != nil {
 				return err
 			}
+		case QuestionType_Matrix:
+			if err := q.ValidateMatrix(); err != nil {
+				return err
+			}
 		}
 	}
 
-	return nil
+	if err := s.validateConditions(); err != nil {
+		return err
+	}
+
+	if err := s.validatePages(); err != nil {
+		return err
+	}
+
+	return s.validateRandomization()
 }
 
 func (v QuestionValidation) ValidateFile() error {
@@ -133,6 +188,11 @@ func (v QuestionValidation) ValidateFile() error {
 	return nil
 }
 
+// IsRequired returns true unless the question is explicitly marked as optional
+func (q Question) IsRequired() bool {
+	return q.Required == nil || *q.Required
+}
+
 func (q Question) GenerateHash() string {
 	var b bytes.Buffer
 	if err := gob.NewEncoder(&b).Encode(q); err != nil {
@@ -164,6 +224,38 @@ func (q Question) ValidateOptions() error {
 	return nil
 }
 
+func (q Question) ValidateMatrix() error {
+	if err := validateUniqueValues("questions[].rows", q.Rows); err != nil {
+		return err
+	}
+	if err := validateUniqueValues("questions[].columns", q.Columns); err != nil {
+		return err
+	}
+	if q.Validation != nil && q.Validation.Max != nil && int(*q.Validation.Max) > len(q.Rows) {
+		return fmt.Errorf("questions[].validation.max must be less than or equal to the number of rows")
+	}
+
+	return nil
+}
+
+func validateUniqueValues(field string, values []string) error {
+	unique := make(map[string]bool)
+	for _, v := range values {
+		if len(v) == 0 {
+			return fmt.Errorf("%s must not be empty", field)
+		}
+		if _, ok := unique[v]; ok {
+			return fmt.Errorf("%s must be unique", field)
+		}
+		unique[v] = true
+	}
+	if len(unique) == 0 {
+		return fmt.Errorf("%s must have at least one value", field)
+	}
+
+	return nil
+}
+
 func (q Question) ValidateMinMax() error {
 	if q.Min == nil || *q.Min == 0 {
 		return fmt.Errorf("questions[].min is required")
@@ -191,9 +283,35 @@ func (v QuestionValidation) Validate() error {
 	if v.Max != nil && *v.Max < 0 {
 		return fmt.Errorf("questions[].validation.max must be greater than or equal to 0")
 	}
+	if v.Min != nil && *v.Min != math.Trunc(*v.Min) {
+		return fmt.Errorf("questions[].validation.min must be an integer")
+	}
+	if v.Max != nil && *v.Max != math.Trunc(*v.Max) {
+		return fmt.Errorf("questions[].validation.max must be an integer")
+	}
+	if v.Min != nil && v.Max != nil && *v.Min > *v.Max {
+		return fmt.Errorf("questions[].validation.min must be less than or equal to questions[].validation.max")
+	}
+	if v.Other != nil {
+		if err := v.Other.Validate(); err != nil {
+			return fmt.Errorf("questions[].validation.other is invalid: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateNumber validates rules of number questions, which allow negative and decimal bounds
+func (v QuestionValidation) ValidateNumber() error {
 	if v.Min != nil && v.Max != nil && *v.Min > *v.Max {
 		return fmt.Errorf("questions[].validation.min must be less than or equal to questions[].validation.max")
 	}
+	if v.Step != nil && *v.Step <= 0 {
+		return fmt.Errorf("questions[].validation.step must be greater than 0")
+	}
+	if v.Precision != nil && *v.Precision < 0 {
+		return fmt.Errorf("questions[].validation.precision must be greater than or equal to 0")
+	}
 
 	return nil
 }
@@ -220,6 +338,12 @@ func (q Question) GetAnswerType() (Answer, error) {
 		return &EmailAnswer{}, nil
 	case QuestionType_File:
 		return &FileAnswer{}, nil
+	case QuestionType_Matrix:
+		return &MatrixAnswer{}, nil
+	case QuestionType_Number:
+		return &DecimalAnswer{}, nil
+	case QuestionType_NPS:
+		return &NumberAnswer{}, nil
 	default:
 		return nil, fmt.Errorf("question type %s is not supported", q.Type)
 	}
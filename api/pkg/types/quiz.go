@@ -0,0 +1,238 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// question types which can have correct answers
+var quizQuestionTypes = map[QuestionType]bool{
+	QuestionType_DropdownSingle:   true,
+	QuestionType_DropdownMultiple: true,
+	QuestionType_ShortText:        true,
+	QuestionType_Date:             true,
+	QuestionType_Rating:           true,
+	QuestionType_Ranking:          true,
+	QuestionType_YesNo:            true,
+	QuestionType_Number:           true,
+	QuestionType_NPS:              true,
+}
+
+// CorrectAnswer is a list of accepted answers, it can be a single value or a list in questions.yaml
+type CorrectAnswer []string
+
+// UnmarshalYAML accepts either a scalar or a list of scalars
+func (c *CorrectAnswer) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*c = CorrectAnswer{value.Value}
+		return nil
+	}
+
+	values := []string{}
+	if err := value.Decode(&values); err != nil {
+		return err
+	}
+	*c = values
+
+	return nil
+}
+
+// ScoreOutro is shown instead of the outro when the session score is at least MinScore
+type ScoreOutro struct {
+	MinScore int    `json:"minScore" yaml:"minScore"`
+	Outro    string `json:"outro" yaml:"outro"`
+}
+
+// IsQuiz returns true if at least one question has correct answers
+func (s *Questions) IsQuiz() bool {
+	for _, q := range s.Questions {
+		if len(q.Correct) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Score returns points of correctly answered visible questions and the maximum points of visible questions
+func (s *Questions) Score(answers map[string]Answer) (int, int) {
+	score, maxScore := 0, 0
+	for _, q := range s.VisibleQuestions(answers) {
+		if len(q.Correct) == 0 {
+			continue
+		}
+
+		maxScore += q.QuizPoints()
+		if q.IsCorrect(answers[q.ID]) {
+			score += q.QuizPoints()
+		}
+	}
+
+	return score, maxScore
+}
+
+// QuizPoints returns points for a correct answer, 1 by default
+func (q Question) QuizPoints() int {
+	if q.Points == nil {
+		return 1
+	}
+
+	return *q.Points
+}
+
+// IsCorrect reports whether the answer matches the correct answers of the question:
+// multiple choice must select exactly the correct options, ranking must have the same order,
+// other types must match one of the correct values
+func (q Question) IsCorrect(answer Answer) bool {
+	if len(q.Correct) == 0 || answer == nil {
+		return false
+	}
+
+	values := answerStrings(answer)
+	switch q.Type {
+	case QuestionType_DropdownMultiple:
+		sortedValues := slices.Clone(values)
+		slices.Sort(sortedValues)
+		sortedCorrect := slices.Clone([]string(q.Correct))
+		slices.Sort(sortedCorrect)
+		return slices.Equal(sortedValues, sortedCorrect)
+	case QuestionType_Ranking:
+		return slices.Equal(values, q.Correct)
+	}
+
+	if len(values) != 1 {
+		return false
+	}
+
+	for _, correct := range q.Correct {
+		if matchesCorrect(values[0], correct) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesCorrect(value string, correct string) bool {
+	if a, err := strconv.ParseFloat(value, 64); err == nil {
+		if b, err := strconv.ParseFloat(correct, 64); err == nil {
+			return a == b
+		}
+	}
+
+	return strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(correct))
+}
+
+// HideCorrect returns the question without correct answers, so they are not sent to respondents
+func (q Question) HideCorrect() Question {
+	q.Correct = nil
+	return q
+}
+
+// HideCorrect returns a copy of the config without correct answers
+func (s *SurveyConfig) HideCorrect() *SurveyConfig {
+	config := *s
+	if s.Questions == nil || !s.Questions.IsQuiz() {
+		return &config
+	}
+
+	questions := *s.Questions
+	questions.Questions = make([]Question, len(s.Questions.Questions))
+	for i, q := range s.Questions.Questions {
+		questions.Questions[i] = q.HideCorrect()
+	}
+	config.Questions = &questions
+
+	return &config
+}
+
+// OutroForScore returns the score outro with the highest MinScore reached, or the default outro
+func (s *SurveyConfig) OutroForScore(score int) string {
+	outro := s.Outro
+	minScore := 0
+	found := false
+	for _, o := range s.ScoreOutros {
+		if score >= o.MinScore && (!found || o.MinScore > minScore) {
+			outro = o.Outro
+			minScore = o.MinScore
+			found = true
+		}
+	}
+
+	return outro
+}
+
+func (q Question) validateCorrect() error {
+	if q.Points != nil && len(q.Correct) == 0 {
+		return fmt.Errorf("questions[].points requires questions[].correct")
+	}
+	if q.Points != nil && *q.Points <= 0 {
+		return fmt.Errorf("questions[].points must be greater than 0")
+	}
+	if len(q.Correct) == 0 {
+		return nil
+	}
+
+	if _, ok := quizQuestionTypes[q.Type]; !ok {
+		return fmt.Errorf("questions[].correct is not supported for type: %s", q.Type)
+	}
+
+	for _, correct := range q.Correct {
+		if correct == "" {
+			return fmt.Errorf("questions[].correct must not be empty")
+		}
+
+		switch q.Type {
+		case QuestionType_DropdownSingle, QuestionType_DropdownMultiple, QuestionType_Ranking:
+			// options can be piped from another question
+			if len(q.Options) > 0 && !slices.Contains(q.Options, correct) {
+				return fmt.Errorf("questions[].correct is not found in options: %s", correct)
+			}
+		case QuestionType_YesNo:
+			if _, err := strconv.ParseBool(correct); err != nil {
+				return fmt.Errorf("questions[].correct must be true or false: %s", correct)
+			}
+		case QuestionType_Rating, QuestionType_Number, QuestionType_NPS:
+			if _, err := strconv.ParseFloat(correct, 64); err != nil {
+				return fmt.Errorf("questions[].correct must be a number: %s", correct)
+			}
+		}
+	}
+
+	if q.Type == QuestionType_Ranking && len(q.Options) > 0 && len(q.Correct) != len(q.Options) {
+		return fmt.Errorf("questions[].correct must rank all options")
+	}
+
+	return nil
+}
+
+func (s *SurveyConfig) validateScoreOutros() error {
+	if len(s.ScoreOutros) == 0 {
+		return nil
+	}
+
+	if !s.Questions.IsQuiz() {
+		return fmt.Errorf("metadata.scoreOutros requires questions with correct answers")
+	}
+
+	uniqueScores := make(map[int]bool)
+	for _, o := range s.ScoreOutros {
+		if o.MinScore < 0 {
+			return fmt.Errorf("metadata.scoreOutros[].minScore must be greater than or equal to 0")
+		}
+		if _, ok := uniqueScores[o.MinScore]; ok {
+			return fmt.Errorf("metadata.scoreOutros[].minScore must be unique")
+		}
+		uniqueScores[o.MinScore] = true
+
+		if o.Outro == "" {
+			return fmt.Errorf("metadata.scoreOutros[].outro is required")
+		}
+	}
+
+	return nil
+}
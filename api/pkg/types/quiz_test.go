@@ -0,0 +1,133 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCorrectAnswerUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected CorrectAnswer
+	}{
+		{"correct: Berlin", CorrectAnswer{"Berlin"}},
+		{"correct: 42", CorrectAnswer{"42"}},
+		{"correct: [Red, Blue]", CorrectAnswer{"Red", "Blue"}},
+	}
+
+	for _, tt := range tests {
+		q := Question{}
+		if err := yaml.Unmarshal([]byte(tt.data), &q); err != nil {
+			t.Fatalf("unable to parse question: %v", err)
+		}
+		if !reflect.DeepEqual(q.Correct, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.data, tt.expected, q.Correct)
+		}
+	}
+}
+
+func TestQuestionIsCorrect(t *testing.T) {
+	tests := []struct {
+		name     string
+		question Question
+		answer   Answer
+		expected bool
+	}{
+		{"single choice", Question{Type: QuestionType_DropdownSingle, Correct: CorrectAnswer{"Berlin"}}, &SingleOptionAnswer{AnswerValue: "Berlin"}, true},
+		{"single choice wrong", Question{Type: QuestionType_DropdownSingle, Correct: CorrectAnswer{"Berlin"}}, &SingleOptionAnswer{AnswerValue: "Munich"}, false},
+		{"multiple choice", Question{Type: QuestionType_DropdownMultiple, Correct: CorrectAnswer{"Red", "Blue"}}, &MultiOptionsAnswer{AnswerValue: []string{"Blue", "Red"}}, true},
+		{"multiple choice partial", Question{Type: QuestionType_DropdownMultiple, Correct: CorrectAnswer{"Red", "Blue"}}, &MultiOptionsAnswer{AnswerValue: []string{"Red"}}, false},
+		{"ranking", Question{Type: QuestionType_Ranking, Correct: CorrectAnswer{"a", "b"}}, &MultiOptionsAnswer{AnswerValue: []string{"a", "b"}}, true},
+		{"ranking order", Question{Type: QuestionType_Ranking, Correct: CorrectAnswer{"a", "b"}}, &MultiOptionsAnswer{AnswerValue: []string{"b", "a"}}, false},
+		{"short text case", Question{Type: QuestionType_ShortText, Correct: CorrectAnswer{"Paris"}}, &TextAnswer{AnswerValue: " paris"}, true},
+		{"number", Question{Type: QuestionType_Number, Correct: CorrectAnswer{"3.50"}}, &DecimalAnswer{AnswerValue: 3.5}, true},
+		{"yes-no", Question{Type: QuestionType_YesNo, Correct: CorrectAnswer{"true"}}, &BoolAnswer{AnswerValue: false}, false},
+		{"skipped", Question{Type: QuestionType_ShortText, Correct: CorrectAnswer{"Paris"}}, nil, false},
+		{"no correct", Question{Type: QuestionType_ShortText}, &TextAnswer{AnswerValue: "Paris"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.question.IsCorrect(tt.answer); got != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestQuestionsScore(t *testing.T) {
+	questions := &Questions{
+		Questions: []Question{
+			{ID: "capital", Type: QuestionType_DropdownSingle, Correct: CorrectAnswer{"Berlin"}, Points: ptrInt(2)},
+			{ID: "sum", Type: QuestionType_Number, Correct: CorrectAnswer{"4"}},
+			{ID: "name", Type: QuestionType_ShortText},
+			{ID: "bonus", Type: QuestionType_YesNo, Correct: CorrectAnswer{"true"}, ShowIf: &QuestionCondition{Question: "name", Equals: ptrString("bonus")}},
+		},
+	}
+
+	answers := map[string]Answer{
+		"capital": &SingleOptionAnswer{AnswerValue: "Berlin"},
+		"sum":     &DecimalAnswer{AnswerValue: 5},
+		"name":    &TextAnswer{AnswerValue: "John"},
+	}
+
+	// hidden questions don't count towards the max score
+	score, maxScore := questions.Score(answers)
+	if score != 2 || maxScore != 3 {
+		t.Errorf("expected 2/3, got %d/%d", score, maxScore)
+	}
+}
+
+func TestQuestionValidateCorrect(t *testing.T) {
+	tests := []struct {
+		name     string
+		question Question
+		valid    bool
+	}{
+		{"no quiz", Question{Type: QuestionType_LongText}, true},
+		{"single choice", Question{Type: QuestionType_DropdownSingle, Options: []string{"a", "b"}, Correct: CorrectAnswer{"a"}, Points: ptrInt(3)}, true},
+		{"unknown option", Question{Type: QuestionType_DropdownSingle, Options: []string{"a", "b"}, Correct: CorrectAnswer{"c"}}, false},
+		{"unsupported type", Question{Type: QuestionType_LongText, Correct: CorrectAnswer{"a"}}, false},
+		{"points without correct", Question{Type: QuestionType_ShortText, Points: ptrInt(1)}, false},
+		{"zero points", Question{Type: QuestionType_ShortText, Correct: CorrectAnswer{"a"}, Points: ptrInt(0)}, false},
+		{"yes-no", Question{Type: QuestionType_YesNo, Correct: CorrectAnswer{"yes"}}, false},
+		{"number", Question{Type: QuestionType_Number, Correct: CorrectAnswer{"abc"}}, false},
+		{"partial ranking", Question{Type: QuestionType_Ranking, Options: []string{"a", "b"}, Correct: CorrectAnswer{"a"}}, false},
+	}
+
+	for _, tt := range tests {
+		err := tt.question.validateCorrect()
+		if tt.valid && err != nil {
+			t.Errorf("%s: expected valid, got %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expected error, got nil", tt.name)
+		}
+	}
+}
+
+func TestOutroForScore(t *testing.T) {
+	config := &SurveyConfig{
+		Outro: "Thank you!",
+		ScoreOutros: []ScoreOutro{
+			{MinScore: 5, Outro: "Good"},
+			{MinScore: 9, Outro: "Excellent"},
+		},
+	}
+
+	tests := []struct {
+		score    int
+		expected string
+	}{
+		{0, "Thank you!"},
+		{5, "Good"},
+		{8, "Good"},
+		{10, "Excellent"},
+	}
+
+	for _, tt := range tests {
+		if outro := config.OutroForScore(tt.score); outro != tt.expected {
+			t.Errorf("OutroForScore(%d) = %s, expected %s", tt.score, outro, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ID of the quota which limits the number of completed responses of the survey
+const QuotaID_MaxResponses = "maxResponses"
+
+const defaultQuotaFullMessage = "Thank you for your interest, we have already collected enough responses."
+
+// Quota limits the number of completed responses with one of the values answered to the question,
+// all completed responses are limited with maxResponses
+type Quota struct {
+	ID         string   `json:"id" yaml:"id"`
+	QuestionID string   `json:"questionId,omitempty" yaml:"questionId"`
+	Values     []string `json:"values,omitempty" yaml:"values"`
+	Limit      int      `json:"limit" yaml:"limit"`
+	// shown to respondents screened out by this quota
+	Message string `json:"message,omitempty" yaml:"message"`
+}
+
+func (s *SurveyConfig) validateQuotas() error {
+	if s.MaxResponses < 0 {
+		return fmt.Errorf("metadata.maxResponses must be greater than or equal to 0")
+	}
+
+	uniqueIDs := make(map[string]bool)
+	for _, quota := range s.Quotas {
+		if quota.ID == "" {
+			return fmt.Errorf("metadata.quotas[].id is required")
+		}
+		if quota.ID == QuotaID_MaxResponses {
+			return fmt.Errorf("metadata.quotas[].id is reserved: %s", quota.ID)
+		}
+		if _, ok := uniqueIDs[quota.ID]; ok {
+			return fmt.Errorf("metadata.quotas[].id must be unique: %s", quota.ID)
+		}
+		uniqueIDs[quota.ID] = true
+
+		if quota.Limit <= 0 {
+			return fmt.Errorf("metadata.quotas[].limit must be greater than 0: %s", quota.ID)
+		}
+		if len(quota.Values) == 0 {
+			return fmt.Errorf("metadata.quotas[].values is required: %s", quota.ID)
+		}
+
+		q, err := s.FindQuestionByID(quota.QuestionID)
+		if err != nil {
+			return fmt.Errorf("metadata.quotas[].questionId is invalid: %s", quota.QuestionID)
+		}
+
+		switch q.Type {
+		case QuestionType_File, QuestionType_Matrix:
+			return fmt.Errorf("metadata.quotas[].questionId type is not supported: %s", q.Type)
+		case QuestionType_DropdownSingle, QuestionType_DropdownMultiple, QuestionType_Ranking:
+			values, open := s.choiceValues(*q)
+			if open {
+				break
+			}
+			for _, v := range quota.Values {
+				if !slices.Contains(values, v) {
+					return fmt.Errorf("metadata.quotas[].values is invalid: %s", v)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// choiceValues returns values which can be answered to the choice question including options from
+// a previous question, open is true if any value can be answered, e.g. in "Other" or piped options
+func (s *SurveyConfig) choiceValues(q Question) ([]string, bool) {
+	if q.AllowOther {
+		return nil, true
+	}
+
+	values := []string{}
+	for _, o := range q.Options {
+		if pipeRegexp.MatchString(o) {
+			return nil, true
+		}
+		values = append(values, o)
+	}
+
+	if q.OptionsFromQuestion != nil {
+		source, err := s.FindQuestionByID(*q.OptionsFromQuestion)
+		if err != nil {
+			return values, false
+		}
+
+		sourceValues, open := s.choiceValues(*source)
+		if open {
+			return nil, true
+		}
+		values = append(values, sourceValues...)
+	}
+
+	return values, false
+}
+
+// Matches reports whether the answer to the quota question is one of the quota values
+func (q Quota) Matches(answers map[string]Answer) bool {
+	answer, ok := answers[q.QuestionID]
+	if !ok || answer == nil {
+		return false
+	}
+
+	for _, v := range answerStrings(answer) {
+		if slices.Contains(q.Values, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchingQuotas returns quotas the response with given answers counts towards,
+// including the maximum number of responses of the survey
+func (s *SurveyConfig) MatchingQuotas(answers map[string]Answer) []Quota {
+	quotas := []Quota{}
+	if s.MaxResponses > 0 {
+		quotas = append(quotas, Quota{ID: QuotaID_MaxResponses, Limit: s.MaxResponses})
+	}
+	for _, q := range s.Quotas {
+		if q.Matches(answers) {
+			quotas = append(quotas, q)
+		}
+	}
+
+	return quotas
+}
+
+// QuotaMessage returns the message shown to respondents screened out by the quota with a given ID
+func (s *SurveyConfig) QuotaMessage(quotaID string) string {
+	for _, q := range s.Quotas {
+		if q.ID == quotaID && q.Message != "" {
+			return q.Message
+		}
+	}
+
+	if s.QuotaFullMessage != "" {
+		return s.QuotaFullMessage
+	}
+
+	return defaultQuotaFullMessage
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"testing"
+)
+
+func quotasTestConfig(quotas []Quota) *SurveyConfig {
+	return &SurveyConfig{
+		Questions: &Questions{Questions: []Question{
+			{ID: "q_role", Type: QuestionType_DropdownSingle, Options: []string{"student", "employee"}},
+			{ID: "q_langs", Type: QuestionType_DropdownMultiple, Options: []string{"go", "rust"}},
+			{ID: "q_cv", Type: QuestionType_File},
+			{ID: "q_favorite", Type: QuestionType_DropdownSingle, OptionsFromQuestion: ptrString("q_langs"), Options: []string{"none"}},
+			{ID: "q_country", Type: QuestionType_DropdownSingle, Options: []string{"Germany"}, AllowOther: true},
+		}},
+		Quotas: quotas,
+	}
+}
+
+func TestValidateQuotas(t *testing.T) {
+	tests := []struct {
+		name    string
+		quotas  []Quota
+		wantErr bool
+	}{
+		{"valid", []Quota{{ID: "students", QuestionID: "q_role", Values: []string{"student"}, Limit: 100}}, false},
+		{"missing id", []Quota{{QuestionID: "q_role", Values: []string{"student"}, Limit: 100}}, true},
+		{"reserved id", []Quota{{ID: QuotaID_MaxResponses, QuestionID: "q_role", Values: []string{"student"}, Limit: 100}}, true},
+		{"duplicate id", []Quota{
+			{ID: "students", QuestionID: "q_role", Values: []string{"student"}, Limit: 100},
+			{ID: "students", QuestionID: "q_role", Values: []string{"employee"}, Limit: 100},
+		}, true},
+		{"invalid limit", []Quota{{ID: "students", QuestionID: "q_role", Values: []string{"student"}}}, true},
+		{"missing values", []Quota{{ID: "students", QuestionID: "q_role", Limit: 100}}, true},
+		{"missing question", []Quota{{ID: "all", Values: []string{"student"}, Limit: 100}}, true},
+		{"unknown question", []Quota{{ID: "students", QuestionID: "q_unknown", Values: []string{"student"}, Limit: 100}}, true},
+		{"unknown option", []Quota{{ID: "students", QuestionID: "q_role", Values: []string{"teacher"}, Limit: 100}}, true},
+		{"unsupported type", []Quota{{ID: "cvs", QuestionID: "q_cv", Values: []string{"cv.pdf"}, Limit: 100}}, true},
+		{"options from question", []Quota{{ID: "gophers", QuestionID: "q_favorite", Values: []string{"go", "none"}, Limit: 100}}, false},
+		{"unknown option from question", []Quota{{ID: "pythonistas", QuestionID: "q_favorite", Values: []string{"python"}, Limit: 100}}, true},
+		{"other value", []Quota{{ID: "french", QuestionID: "q_country", Values: []string{"France"}, Limit: 100}}, false},
+	}
+
+	for _, tt := range tests {
+		err := quotasTestConfig(tt.quotas).validateQuotas()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestMatchingQuotas(t *testing.T) {
+	s := quotasTestConfig([]Quota{
+		{ID: "students", QuestionID: "q_role", Values: []string{"student"}, Limit: 100},
+		{ID: "gophers", QuestionID: "q_langs", Values: []string{"go"}, Limit: 10},
+	})
+	s.MaxResponses = 1000
+
+	tests := []struct {
+		name     string
+		answers  map[string]Answer
+		expected []string
+	}{
+		{"no answers", map[string]Answer{}, []string{QuotaID_MaxResponses}},
+		{"single choice", map[string]Answer{"q_role": &SingleOptionAnswer{AnswerValue: "student"}}, []string{QuotaID_MaxResponses, "students"}},
+		{"other option", map[string]Answer{"q_role": &SingleOptionAnswer{AnswerValue: "employee"}}, []string{QuotaID_MaxResponses}},
+		{"multiple choice", map[string]Answer{"q_langs": &MultiOptionsAnswer{AnswerValue: []string{"rust", "go"}}}, []string{QuotaID_MaxResponses, "gophers"}},
+		{"skipped", map[string]Answer{"q_role": nil}, []string{QuotaID_MaxResponses}},
+	}
+
+	for _, tt := range tests {
+		quotas := s.MatchingQuotas(tt.answers)
+		ids := []string{}
+		for _, q := range quotas {
+			ids = append(ids, q.ID)
+		}
+		if len(ids) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, ids)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, ids)
+				break
+			}
+		}
+	}
+}
+
+func TestQuotaMessage(t *testing.T) {
+	s := quotasTestConfig([]Quota{
+		{ID: "students", QuestionID: "q_role", Values: []string{"student"}, Limit: 100, Message: "Enough students"},
+		{ID: "employees", QuestionID: "q_role", Values: []string{"employee"}, Limit: 100},
+	})
+
+	if msg := s.QuotaMessage("students"); msg != "Enough students" {
+		t.Errorf("expected quota message, got %s", msg)
+	}
+	if msg := s.QuotaMessage("employees"); msg != defaultQuotaFullMessage {
+		t.Errorf("expected default message, got %s", msg)
+	}
+
+	s.QuotaFullMessage = "Survey is full"
+	if msg := s.QuotaMessage(QuotaID_MaxResponses); msg != "Survey is full" {
+		t.Errorf("expected survey message, got %s", msg)
+	}
+}
@@ -0,0 +1,263 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// SessionOrder is the order of questions and options shown in a session
+type SessionOrder struct {
+	// question IDs
+	Questions []string `json:"questions,omitempty"`
+	// option values keyed by question ID
+	Options map[string][]string `json:"options,omitempty"`
+}
+
+// NewSessionOrder randomizes questions and options seeded by the session UUID,
+// returns nil if the survey has no randomization
+func NewSessionOrder(s *Questions, sessionUUID string) *SessionOrder {
+	order := &SessionOrder{}
+
+	pinnedQuestions := make(map[string]bool)
+	for _, q := range s.Questions {
+		if q.Pinned {
+			pinnedQuestions[q.ID] = true
+		}
+	}
+
+	questionIDs := []string{}
+	if len(s.Pages) > 0 {
+		// questions are randomized within their pages
+		for _, p := range s.Pages {
+			if s.RandomizeQuestions || p.RandomizeQuestions {
+				questionIDs = append(questionIDs, shuffleUnpinned(p.Questions, pinnedQuestions, sessionRand(sessionUUID, "page:"+p.ID))...)
+			} else {
+				questionIDs = append(questionIDs, p.Questions...)
+			}
+		}
+	} else {
+		for _, q := range s.Questions {
+			questionIDs = append(questionIDs, q.ID)
+		}
+		if s.RandomizeQuestions {
+			questionIDs = shuffleUnpinned(questionIDs, pinnedQuestions, sessionRand(sessionUUID, "questions"))
+		}
+	}
+	if s.RandomizeQuestions || slices.ContainsFunc(s.Pages, func(p Page) bool { return p.RandomizeQuestions }) {
+		order.Questions = questionIDs
+	}
+
+	for _, q := range s.Questions {
+		if !q.RandomizeOptions {
+			continue
+		}
+
+		pinnedOptions := make(map[string]bool)
+		for _, option := range q.PinnedOptions {
+			pinnedOptions[option] = true
+		}
+
+		if order.Options == nil {
+			order.Options = make(map[string][]string)
+		}
+		order.Options[q.ID] = shuffleUnpinned(q.Options, pinnedOptions, sessionRand(sessionUUID, "options:"+q.ID))
+	}
+
+	if order.Questions == nil && order.Options == nil {
+		return nil
+	}
+
+	return order
+}
+
+// OrderQuestions returns questions in the order of the session
+func (o *SessionOrder) OrderQuestions(questions []Question) []Question {
+	if o == nil || o.Questions == nil {
+		return questions
+	}
+
+	ids := []string{}
+	byID := make(map[string]Question)
+	for _, q := range questions {
+		ids = append(ids, q.ID)
+		byID[q.ID] = q
+	}
+
+	ordered := []Question{}
+	for _, id := range orderValues(ids, o.Questions) {
+		ordered = append(ordered, byID[id])
+	}
+
+	return ordered
+}
+
+// OrderOptions returns a copy of the question with options in the order of the session
+func (o *SessionOrder) OrderOptions(q Question) Question {
+	if o == nil {
+		return q
+	}
+
+	if options, ok := o.Options[q.ID]; ok {
+		q.Options = orderValues(q.Options, options)
+	}
+
+	return q
+}
+
+// orderValues sorts values by a given order, values missing in the order
+// (e.g. added to the survey after the session was created) are kept at the end
+func orderValues(values []string, order []string) []string {
+	known := make(map[string]bool)
+	for _, v := range values {
+		known[v] = true
+	}
+
+	ordered := []string{}
+	added := make(map[string]bool)
+	for _, v := range order {
+		if known[v] && !added[v] {
+			ordered = append(ordered, v)
+			added[v] = true
+		}
+	}
+	for _, v := range values {
+		if !added[v] {
+			ordered = append(ordered, v)
+			added[v] = true
+		}
+	}
+
+	return ordered
+}
+
+// shuffleUnpinned shuffles values while pinned values keep their positions
+func shuffleUnpinned(values []string, pinned map[string]bool, r *rand.Rand) []string {
+	shuffled := slices.Clone(values)
+
+	positions := []int{}
+	for i, v := range shuffled {
+		if !pinned[v] {
+			positions = append(positions, i)
+		}
+	}
+
+	r.Shuffle(len(positions), func(i, j int) {
+		shuffled[positions[i]], shuffled[positions[j]] = shuffled[positions[j]], shuffled[positions[i]]
+	})
+
+	return shuffled
+}
+
+// sessionRand returns a random generator which is deterministic for a given session and key
+func sessionRand(sessionUUID string, key string) *rand.Rand {
+	seed := sha256.Sum256([]byte(fmt.Sprintf("%s:%s", sessionUUID, key)))
+	return rand.New(rand.NewPCG(binary.BigEndian.Uint64(seed[:8]), binary.BigEndian.Uint64(seed[8:16])))
+}
+
+// validateRandomization checks pinned options of questions with randomized options
+// and that randomized questions don't depend on each other
+func (s *Questions) validateRandomization() error {
+	if err := s.validateRandomizedDependencies(); err != nil {
+		return err
+	}
+
+	for _, q := range s.Questions {
+		if len(q.PinnedOptions) > 0 && !q.RandomizeOptions {
+			return fmt.Errorf("questions[].pinnedOptions requires randomizeOptions: %s", q.ID)
+		}
+		if !q.RandomizeOptions {
+			continue
+		}
+
+		if q.Type != QuestionType_DropdownSingle && q.Type != QuestionType_DropdownMultiple && q.Type != QuestionType_Ranking {
+			return fmt.Errorf("questions[].randomizeOptions is not supported for type: %s", q.Type)
+		}
+		// options from answers are only known when the question is shown, after the session order is created,
+		// options from variables are set when the survey is parsed, so they can be randomized
+		if q.OptionsFromQuestion != nil {
+			return fmt.Errorf("questions[].randomizeOptions is not supported with optionsFromQuestion: %s", q.ID)
+		}
+		for _, option := range q.PinnedOptions {
+			if !slices.Contains(q.Options, option) {
+				return fmt.Errorf("questions[].pinnedOptions must reference an option: %s", option)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateRandomizedDependencies checks that questions shuffled together don't reference each other,
+// conditions, jumps and piped answers need the referenced question to be shown first,
+// pinned questions keep their positions, so they can reference each other
+func (s *Questions) validateRandomizedDependencies() error {
+	sets := [][]string{}
+	if len(s.Pages) > 0 {
+		for _, p := range s.Pages {
+			if s.RandomizeQuestions || p.RandomizeQuestions {
+				sets = append(sets, p.Questions)
+			}
+		}
+	} else if s.RandomizeQuestions {
+		ids := []string{}
+		for _, q := range s.Questions {
+			if q.ID != "" {
+				ids = append(ids, q.ID)
+			}
+		}
+		sets = append(sets, ids)
+	}
+
+	byID := make(map[string]Question)
+	for _, q := range s.Questions {
+		if q.ID != "" {
+			byID[q.ID] = q
+		}
+	}
+
+	shuffled := make(map[string]bool)
+	for _, set := range sets {
+		for _, id := range set {
+			if !byID[id].Pinned {
+				shuffled[id] = true
+			}
+		}
+	}
+
+	for _, set := range sets {
+		for _, id := range set {
+			q := byID[id]
+			dependsOn := func(other string) bool {
+				return slices.Contains(set, other) && (shuffled[id] || shuffled[other])
+			}
+
+			if q.ShowIf != nil && dependsOn(q.ShowIf.Question) {
+				return fmt.Errorf("questions[].showIf must not reference a question randomized with it: %s", q.ID)
+			}
+			if q.OptionsFromQuestion != nil && dependsOn(*q.OptionsFromQuestion) {
+				return fmt.Errorf("questions[].optionsFromQuestion must not reference a question randomized with it: %s", q.ID)
+			}
+			for _, piped := range q.pipedQuestionIDs() {
+				if dependsOn(piped) {
+					return fmt.Errorf("questions[].label, description and options must not reference a question randomized with it: %s", q.ID)
+				}
+			}
+			if len(q.JumpTo) > 0 && shuffled[id] {
+				return fmt.Errorf("questions[].jumpTo is not supported for randomized questions: %s", q.ID)
+			}
+		}
+	}
+
+	for _, q := range s.Questions {
+		for _, j := range q.JumpTo {
+			if shuffled[j.To] {
+				return fmt.Errorf("questions[].jumpTo.to must not reference a randomized question: %s", j.To)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package types
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func randomizedQuestions() *Questions {
+	return &Questions{
+		RandomizeQuestions: true,
+		Questions: []Question{
+			{ID: "q1", Type: QuestionType_ShortText, Label: "Name"},
+			{ID: "q2", Type: QuestionType_ShortText, Label: "City"},
+			{ID: "q3", Type: QuestionType_ShortText, Label: "Street"},
+			{ID: "q4", Type: QuestionType_ShortText, Label: "Zip"},
+			{
+				ID:               "q5",
+				Type:             QuestionType_DropdownSingle,
+				Label:            "Color",
+				Options:          []string{"Red", "Green", "Blue", "Yellow", "None of the above"},
+				RandomizeOptions: true,
+				PinnedOptions:    []string{"None of the above"},
+			},
+			{ID: "q6", Type: QuestionType_Email, Label: "Email", Pinned: true},
+		},
+	}
+}
+
+func TestNewSessionOrder(t *testing.T) {
+	questions := randomizedQuestions()
+
+	order := NewSessionOrder(questions, "session-1")
+	if order == nil {
+		t.Fatalf("expected order, got nil")
+	}
+	if !reflect.DeepEqual(order, NewSessionOrder(questions, "session-1")) {
+		t.Errorf("expected the same order for the same session")
+	}
+
+	if len(order.Questions) != 6 || order.Questions[5] != "q6" {
+		t.Errorf("expected pinned question to stay last, got %v", order.Questions)
+	}
+	options := order.Options["q5"]
+	if len(options) != 5 || options[4] != "None of the above" {
+		t.Errorf("expected pinned option to stay last, got %v", options)
+	}
+
+	// different sessions get different orders
+	orders := make(map[string]bool)
+	for _, sessionUUID := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		orders[strings.Join(NewSessionOrder(questions, sessionUUID).Questions, ",")] = true
+	}
+	if len(orders) < 2 {
+		t.Errorf("expected questions to be randomized per session")
+	}
+}
+
+func TestNewSessionOrderPages(t *testing.T) {
+	questions := randomizedQuestions()
+	questions.RandomizeQuestions = false
+	questions.Pages = []Page{
+		{ID: "p1", Questions: []string{"q1", "q2", "q3"}, RandomizeQuestions: true},
+		{ID: "p2", Questions: []string{"q4", "q5", "q6"}},
+	}
+
+	for _, sessionUUID := range []string{"a", "b", "c", "d"} {
+		order := NewSessionOrder(questions, sessionUUID)
+		firstPage := slices.Clone(order.Questions[:3])
+		slices.Sort(firstPage)
+		if !reflect.DeepEqual(firstPage, []string{"q1", "q2", "q3"}) {
+			t.Errorf("expected questions to stay within their page, got %v", order.Questions)
+		}
+		if !reflect.DeepEqual(order.Questions[3:], []string{"q4", "q5", "q6"}) {
+			t.Errorf("expected questions of not randomized page to keep their order, got %v", order.Questions)
+		}
+	}
+}
+
+func TestNewSessionOrderNoRandomization(t *testing.T) {
+	questions := &Questions{
+		Questions: []Question{
+			{ID: "q1", Type: QuestionType_ShortText, Label: "Name"},
+		},
+	}
+
+	if order := NewSessionOrder(questions, "session-1"); order != nil {
+		t.Errorf("expected no order, got %v", order)
+	}
+}
+
+func TestSessionOrderOrderOptions(t *testing.T) {
+	order := &SessionOrder{
+		Options: map[string][]string{"q1": {"Blue", "Red", "Green"}},
+	}
+
+	q := order.OrderOptions(Question{ID: "q1", Options: []string{"Red", "Green", "Blue", "Yellow"}})
+	if !reflect.DeepEqual(q.Options, []string{"Blue", "Red", "Green", "Yellow"}) {
+		t.Errorf("expected new options to be kept at the end, got %v", q.Options)
+	}
+}
+
+func TestQuestionsValidateRandomization(t *testing.T) {
+	cases := []struct {
+		name      string
+		question  Question
+		expectErr bool
+	}{
+		{"randomized options", Question{ID: "q1", Type: QuestionType_DropdownSingle, Options: []string{"a", "b"}, RandomizeOptions: true, PinnedOptions: []string{"b"}}, false},
+		{"pinned without randomization", Question{ID: "q1", Type: QuestionType_DropdownSingle, Options: []string{"a", "b"}, PinnedOptions: []string{"b"}}, true},
+		{"unknown pinned option", Question{ID: "q1", Type: QuestionType_DropdownSingle, Options: []string{"a", "b"}, RandomizeOptions: true, PinnedOptions: []string{"c"}}, true},
+		{"unsupported type", Question{ID: "q1", Type: QuestionType_ShortText, RandomizeOptions: true}, true},
+		{"options from question", Question{ID: "q1", Type: QuestionType_DropdownMultiple, OptionsFromQuestion: ptrString("q0"), RandomizeOptions: true}, true},
+	}
+
+	for _, c := range cases {
+		questions := &Questions{Questions: []Question{c.question}}
+		if err := questions.validateRandomization(); (err != nil) != c.expectErr {
+			t.Errorf("%s: expected error %v, got %v", c.name, c.expectErr, err)
+		}
+	}
+}
+
+func TestQuestionsValidateRandomizedDependencies(t *testing.T) {
+	cases := []struct {
+		name      string
+		modify    func(*Questions)
+		expectErr bool
+	}{
+		{"independent", func(s *Questions) {}, false},
+		{"show if", func(s *Questions) {
+			s.Questions[2].ShowIf = &QuestionCondition{Question: "q1", Equals: ptrString("John")}
+		}, true},
+		{"pinned show if", func(s *Questions) {
+			s.Questions[0].Pinned = true
+			s.Questions[5].ShowIf = &QuestionCondition{Question: "q1", Equals: ptrString("John")}
+		}, false},
+		{"piped answer", func(s *Questions) {
+			s.Questions[1].Label = "City of {{ q1 }}"
+		}, true},
+		{"options from question", func(s *Questions) {
+			s.Questions[1].Type = QuestionType_DropdownSingle
+			s.Questions[1].OptionsFromQuestion = ptrString("q5")
+		}, true},
+		{"jump from randomized question", func(s *Questions) {
+			s.Questions[1].JumpTo = []QuestionJump{{QuestionCondition: QuestionCondition{Equals: ptrString("Berlin")}, To: "q6"}}
+		}, true},
+		{"jump to randomized question", func(s *Questions) {
+			s.Questions[5].JumpTo = []QuestionJump{{QuestionCondition: QuestionCondition{Equals: ptrString("a@b.c")}, To: "q4"}}
+		}, true},
+		{"other page", func(s *Questions) {
+			s.RandomizeQuestions = false
+			s.Pages = []Page{
+				{ID: "p1", Questions: []string{"q1", "q2"}},
+				{ID: "p2", RandomizeQuestions: true, Questions: []string{"q3", "q4", "q5", "q6"}},
+			}
+			s.Questions[2].ShowIf = &QuestionCondition{Question: "q1", Equals: ptrString("John")}
+		}, false},
+		{"same page", func(s *Questions) {
+			s.RandomizeQuestions = false
+			s.Pages = []Page{
+				{ID: "p1", Questions: []string{"q1", "q2"}},
+				{ID: "p2", RandomizeQuestions: true, Questions: []string{"q3", "q4", "q5", "q6"}},
+			}
+			s.Questions[3].ShowIf = &QuestionCondition{Question: "q3", Equals: ptrString("Main")}
+		}, true},
+	}
+
+	for _, c := range cases {
+		questions := randomizedQuestions()
+		c.modify(questions)
+		if err := questions.validateRandomizedDependencies(); (err != nil) != c.expectErr {
+			t.Errorf("%s: expected error %v, got %v", c.name, c.expectErr, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const respondentCookiePrefix = "formulosity_respondent_"
+
+// RespondentCookieName returns the name of the cookie identifying the respondent of the survey
+func RespondentCookieName(urlSlug string) string {
+	return respondentCookiePrefix + urlSlug
+}
+
+// SignRespondentCookie returns the cookie value with the session UUID and its signature,
+// it's bound to the survey so the cookie can't be reused for another survey
+func SignRespondentCookie(surveyUUID string, sessionUUID string, secret string) string {
+	return sessionUUID + "." + hex.EncodeToString(signRespondentCookie(surveyUUID, sessionUUID, secret))
+}
+
+// VerifyRespondentCookie returns the session UUID from the cookie value if the signature is valid
+func VerifyRespondentCookie(surveyUUID string, value string, secret string) (string, error) {
+	sessionUUID, signatureHex, ok := strings.Cut(value, ".")
+	if !ok || sessionUUID == "" {
+		return "", errors.New("respondent cookie is invalid")
+	}
+
+	signature, err := hex.DecodeString(signatureHex)
+	if err != nil || !hmac.Equal(signature, signRespondentCookie(surveyUUID, sessionUUID, secret)) {
+		return "", errors.New("respondent cookie signature is invalid")
+	}
+
+	return sessionUUID, nil
+}
+
+func signRespondentCookie(surveyUUID string, sessionUUID string, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(surveyUUID + ":" + sessionUUID))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,35 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestVerifyRespondentCookie(t *testing.T) {
+	value := SignRespondentCookie("survey-1", "session-1", "secret")
+
+	tests := []struct {
+		name       string
+		surveyUUID string
+		value      string
+		secret     string
+		expected   string
+		wantErr    bool
+	}{
+		{"valid", "survey-1", value, "secret", "session-1", false},
+		{"another survey", "survey-2", value, "secret", "", true},
+		{"another secret", "survey-1", value, "other", "", true},
+		{"tampered session", "survey-1", "session-2" + value[len("session-1"):], "secret", "", true},
+		{"missing signature", "survey-1", "session-1", "secret", "", true},
+		{"invalid signature", "survey-1", "session-1.zz", "secret", "", true},
+	}
+
+	for _, tt := range tests {
+		sessionUUID, err := VerifyRespondentCookie(tt.surveyUUID, tt.value, tt.secret)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+		if sessionUUID != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, sessionUUID)
+		}
+	}
+}
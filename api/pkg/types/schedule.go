@@ -0,0 +1,49 @@
+package types
+
+import (
+	"errors"
+	"time"
+)
+
+type SurveyScheduleStatus string
+
+const (
+	SurveyScheduleStatus_NotYetOpen SurveyScheduleStatus = "not_yet_open"
+	SurveyScheduleStatus_Open       SurveyScheduleStatus = "open"
+	SurveyScheduleStatus_Closed     SurveyScheduleStatus = "closed"
+)
+
+func (s *SurveyConfig) validateSchedule() error {
+	if s.OpensAt != nil && s.ClosesAt != nil && !s.ClosesAt.After(*s.OpensAt) {
+		return errors.New("metadata.closesAt must be after metadata.opensAt")
+	}
+
+	return nil
+}
+
+// ScheduleStatus returns whether the survey accepts responses at the given time by opensAt and closesAt
+func (s *SurveyConfig) ScheduleStatus(now time.Time) SurveyScheduleStatus {
+	if s.OpensAt != nil && now.Before(*s.OpensAt) {
+		return SurveyScheduleStatus_NotYetOpen
+	}
+	if s.ClosesAt != nil && !now.Before(*s.ClosesAt) {
+		return SurveyScheduleStatus_Closed
+	}
+
+	return SurveyScheduleStatus_Open
+}
+
+// ScheduledTransition returns the delivery status the survey is switched to by the last passed
+// opensAt or closesAt and the time of the transition, false if none of them has passed yet
+func (s *SurveyConfig) ScheduledTransition(now time.Time) (SurveyDeliveryStatus, time.Time, bool) {
+	switch s.ScheduleStatus(now) {
+	case SurveyScheduleStatus_Closed:
+		return SurveyDeliveryStatus_Stopped, *s.ClosesAt, true
+	case SurveyScheduleStatus_Open:
+		if s.OpensAt != nil {
+			return SurveyDeliveryStatus_Launched, *s.OpensAt, true
+		}
+	}
+
+	return "", time.Time{}, false
+}
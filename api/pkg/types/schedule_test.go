@@ -0,0 +1,66 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSurveyConfigSchedule(t *testing.T) {
+	s := &SurveyConfig{}
+	data := "opensAt: 2025-03-01T09:00:00Z\nclosesAt: \"2025-03-31T18:00:00+02:00\"\n"
+	if err := yaml.Unmarshal([]byte(data), s); err != nil {
+		t.Fatalf("unable to parse schedule: %v", err)
+	}
+	if err := s.validateSchedule(); err != nil {
+		t.Fatalf("expected valid schedule, got %v", err)
+	}
+
+	opensAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	closesAt := time.Date(2025, 3, 31, 16, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		now            time.Time
+		expected       SurveyScheduleStatus
+		expectedStatus SurveyDeliveryStatus
+		expectedAt     time.Time
+		expectedOk     bool
+	}{
+		{"before", opensAt.Add(-time.Second), SurveyScheduleStatus_NotYetOpen, "", time.Time{}, false},
+		{"opened", opensAt, SurveyScheduleStatus_Open, SurveyDeliveryStatus_Launched, opensAt, true},
+		{"closed", closesAt, SurveyScheduleStatus_Closed, SurveyDeliveryStatus_Stopped, closesAt, true},
+	}
+
+	for _, tt := range tests {
+		if status := s.ScheduleStatus(tt.now); status != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, status)
+		}
+
+		status, at, ok := s.ScheduledTransition(tt.now)
+		if status != tt.expectedStatus || !at.Equal(tt.expectedAt) || ok != tt.expectedOk {
+			t.Errorf("%s: expected transition to %s at %v, got %s at %v", tt.name, tt.expectedStatus, tt.expectedAt, status, at)
+		}
+	}
+}
+
+func TestSurveyConfigValidateSchedule(t *testing.T) {
+	opensAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	closesAt := opensAt.Add(-time.Hour)
+
+	if err := (&SurveyConfig{OpensAt: &opensAt, ClosesAt: &closesAt}).validateSchedule(); err == nil {
+		t.Errorf("expected error when closesAt is before opensAt")
+	}
+	if err := (&SurveyConfig{ClosesAt: &closesAt}).validateSchedule(); err != nil {
+		t.Errorf("expected only closesAt to be valid, got %v", err)
+	}
+
+	s := &SurveyConfig{}
+	if status := s.ScheduleStatus(opensAt); status != SurveyScheduleStatus_Open {
+		t.Errorf("expected survey without schedule to be open, got %s", status)
+	}
+	if _, _, ok := s.ScheduledTransition(opensAt); ok {
+		t.Errorf("expected no transition for survey without schedule")
+	}
+}
@@ -9,21 +9,65 @@ type DuplicateProtectionType string
 const (
 	DuplicateProtectionType_Cookie DuplicateProtectionType = "cookie"
 	DuplicateProtectionType_Ip     DuplicateProtectionType = "ip"
+	// respondents need a single-use invitation token
+	DuplicateProtectionType_Invite DuplicateProtectionType = "invite"
 )
 
 var supportedDuplicateProtectionTypes = map[DuplicateProtectionType]bool{
 	DuplicateProtectionType_Cookie: true,
 	DuplicateProtectionType_Ip:     true,
+	DuplicateProtectionType_Invite: true,
+}
+
+type PrefillType string
+
+const (
+	PrefillType_Unsigned PrefillType = "unsigned"
+	PrefillType_Signed   PrefillType = "signed"
+)
+
+var supportedPrefillTypes = map[PrefillType]bool{
+	PrefillType_Unsigned: true,
+	PrefillType_Signed:   true,
 }
 
 type Security struct {
 	DuplicateProtection DuplicateProtectionType `json:"duplicateProtection" yaml:"duplicateProtection"`
+	// answers can't be prefilled from the survey URL if empty
+	Prefill      PrefillType   `json:"prefill,omitempty" yaml:"prefill"`
+	IPProtection *IPProtection `json:"ipProtection,omitempty" yaml:"ipProtection"`
 }
 
 func (s *Security) Validate() error {
-	if _, ok := supportedDuplicateProtectionTypes[s.DuplicateProtection]; !ok {
+	if _, ok := supportedDuplicateProtectionTypes[s.DuplicateProtection]; s.DuplicateProtection != "" && !ok {
 		return fmt.Errorf("security.duplicateProtection is invalid: %s", s.DuplicateProtection)
 	}
+	if _, ok := supportedPrefillTypes[s.Prefill]; s.Prefill != "" && !ok {
+		return fmt.Errorf("security.prefill is invalid: %s", s.Prefill)
+	}
+	if s.IPProtection != nil {
+		if err := s.IPProtection.Validate(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
+
+// DuplicateProtection returns the duplicate protection of the survey, empty if security.yaml is missing
+func (s *SurveyConfig) DuplicateProtection() DuplicateProtectionType {
+	if s.Security == nil {
+		return ""
+	}
+
+	return s.Security.DuplicateProtection
+}
+
+// Prefill returns how answers can be prefilled from the survey URL, empty if they can't
+func (s *SurveyConfig) Prefill() PrefillType {
+	if s.Security == nil {
+		return ""
+	}
+
+	return s.Security.Prefill
+}
@@ -55,6 +55,8 @@ type Survey struct {
 	Name           string               `json:"name"`
 	URLSlug        string               `json:"url_slug"`
 	URL            string               `json:"url"`
+	Locale         string               `json:"locale,omitempty"`
+	Locales        []string             `json:"locales,omitempty"`
 
 	Config *SurveyConfig `json:"config"`
 	Stats  SurveyStats   `json:"stats"`
@@ -64,6 +66,8 @@ type SurveyStats struct {
 	SessionsCountInProgess int `json:"sessions_count_in_progress"`
 	SessionsCountCompleted int `json:"sessions_count_completed"`
 	CompletionRate         int `json:"completion_rate"`
+
+	NPS []NPSStats `json:"nps,omitempty"`
 }
 
 type SurveyConfig struct {
@@ -72,11 +76,29 @@ type SurveyConfig struct {
 	Outro   string         `json:"outro" yaml:"outro"`
 	Theme   string         `json:"theme" yaml:"theme"`
 	Webhook *WebhookConfig `json:"webhook" yaml:"webhook"`
+	// endpoints subscribed to events, in addition to webhook
+	Webhooks []WebhookConfig `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	// locale of texts in metadata.yaml and questions.yaml
+	DefaultLocale string `json:"defaultLocale" yaml:"defaultLocale"`
+	// outros of quizzes by the session score
+	ScoreOutros []ScoreOutro `json:"scoreOutros,omitempty" yaml:"scoreOutros,omitempty"`
+	// captured from the survey URL and stored on sessions
+	HiddenFields []string `json:"hiddenFields,omitempty" yaml:"hiddenFields,omitempty"`
+	// maximum number of completed responses, the survey is stopped when it's reached
+	MaxResponses int `json:"maxResponses,omitempty" yaml:"maxResponses,omitempty"`
+	// respondents who hit a full quota are screened out with the quota message or this message
+	Quotas           []Quota `json:"quotas,omitempty" yaml:"quotas,omitempty"`
+	QuotaFullMessage string  `json:"quotaFullMessage,omitempty" yaml:"quotaFullMessage,omitempty"`
+	// responses are accepted only between these times, delivery status is switched by the scheduler
+	OpensAt  *time.Time `json:"opensAt,omitempty" yaml:"opensAt,omitempty"`
+	ClosesAt *time.Time `json:"closesAt,omitempty" yaml:"closesAt,omitempty"`
 
 	Hash      string     `json:"hash" yaml:"-"`
 	Questions *Questions `json:"questions" yaml:"-"`
 	Variables *Variables `json:"variables" yaml:"-"`
 	Security  *Security  `json:"security" yaml:"-"`
+
+	Translations []Translation `json:"translations,omitempty" yaml:"-"`
 }
 
 type SurveysSyncResult struct {
@@ -97,6 +119,19 @@ func (a *SurveyConfig) Scan(value interface{}) error {
 	return json.Unmarshal(b, &a)
 }
 
+// Public returns a copy of the config without settings which must not be sent to respondents,
+// e.g. webhook endpoints and headers
+func (s *SurveyConfig) Public() *SurveyConfig {
+	config := *s
+	config.Webhook = nil
+	config.Webhooks = nil
+	config.Quotas = nil
+	config.Variables = nil
+	config.Security = nil
+
+	return &config
+}
+
 func (s *SurveyConfig) Validate() error {
 	if s.Title == "" {
 		return fmt.Errorf("metadata.title is required")
@@ -104,9 +139,18 @@ func (s *SurveyConfig) Validate() error {
 	if s.Theme == "" {
 		s.Theme = Theme_Default
 	}
+	if s.DefaultLocale == "" {
+		s.DefaultLocale = DefaultLocale
+	}
 	if _, ok := SupportedThemes[s.Theme]; !ok {
 		return fmt.Errorf("theme is invalid: %s", s.Theme)
 	}
+	if err := s.validateHiddenFields(); err != nil {
+		return err
+	}
+	if err := s.validateSchedule(); err != nil {
+		return err
+	}
 
 	if s.Questions == nil {
 		return fmt.Errorf("questions is required")
@@ -122,12 +166,37 @@ func (s *SurveyConfig) Validate() error {
 	if err := s.Questions.Validate(); err != nil {
 		return err
 	}
+	if err := s.Questions.validatePipes(); err != nil {
+		return err
+	}
+	if err := s.validateComputedVariables(); err != nil {
+		return err
+	}
+	if err := s.validateScoreOutros(); err != nil {
+		return err
+	}
+	if err := s.validateQuotas(); err != nil {
+		return err
+	}
+	if err := s.validateTranslations(); err != nil {
+		return err
+	}
 
+	if s.Security != nil {
+		if err := s.Security.Validate(); err != nil {
+			return err
+		}
+	}
 	if s.Webhook != nil {
 		if err := s.Webhook.Validate(); err != nil {
 			return err
 		}
 	}
+	for _, wc := range s.Webhooks {
+		if err := wc.Validate(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -153,6 +222,7 @@ func (s *SurveyConfig) SetOptionsFromVariables() error {
 			}
 
 			q.Options = variable.Options
+			q.OptionLabels = variable.OptionLabels
 
 			s.Questions.Questions[i] = q
 		}
@@ -165,6 +235,15 @@ func (s *SurveyConfig) Normalize() {
 	p := bluemonday.StripTagsPolicy()
 	s.Intro = p.Sanitize(s.Intro)
 	s.Outro = p.Sanitize(s.Outro)
+	for i, o := range s.ScoreOutros {
+		o.Outro = p.Sanitize(o.Outro)
+		s.ScoreOutros[i] = o
+	}
+	s.QuotaFullMessage = p.Sanitize(s.QuotaFullMessage)
+	for i, q := range s.Quotas {
+		q.Message = p.Sanitize(q.Message)
+		s.Quotas[i] = q
+	}
 
 	uniqueIDs := make(map[string]bool)
 	if s.Questions != nil {
@@ -187,6 +266,31 @@ func (s *SurveyConfig) Normalize() {
 
 			s.Questions.Questions[i] = q
 		}
+
+		for i, page := range s.Questions.Pages {
+			page.Title = p.Sanitize(page.Title)
+			page.Description = p.Sanitize(page.Description)
+			s.Questions.Pages[i] = page
+		}
+	}
+
+	for i, t := range s.Translations {
+		t.Intro = p.Sanitize(t.Intro)
+		t.Outro = p.Sanitize(t.Outro)
+		for j, o := range t.ScoreOutros {
+			o.Outro = p.Sanitize(o.Outro)
+			t.ScoreOutros[j] = o
+		}
+		for j, pt := range t.Pages {
+			pt.Title = p.Sanitize(pt.Title)
+			pt.Description = p.Sanitize(pt.Description)
+			t.Pages[j] = pt
+		}
+		for j, qt := range t.Questions {
+			qt.Description = p.Sanitize(qt.Description)
+			t.Questions[j] = qt
+		}
+		s.Translations[i] = t
 	}
 }
 
@@ -213,3 +317,14 @@ func (s *SurveyConfig) FindQuestionByUUID(questionUUID string) (*Question, error
 
 	return nil, errors.New("question not found")
 }
+
+func (s *SurveyConfig) FindQuestionByID(questionID string) (*Question, error) {
+	for _, q := range s.Questions.Questions {
+		if q.ID == questionID {
+			question := q
+			return &question, nil
+		}
+	}
+
+	return nil, errors.New("question not found")
+}
@@ -1,22 +1,32 @@
 package types
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 )
 
 type SurveySessionStatus string
 
 const (
-	SurveySessionStatus_InProgress = "in_progress"
-	SurveySessionStatus_Completed  = "completed"
+	SurveySessionStatus_InProgress  = "in_progress"
+	SurveySessionStatus_Completed   = "completed"
+	SurveySessionStatus_ScreenedOut = "screened_out"
 )
 
+var ErrDuplicateSession = errors.New("duplicate session for respondent")
+
 type QuestionAnswer struct {
 	QuestionID   string `json:"question_id"`
 	QuestionUUID string `json:"question_uuid"`
 	AnswerBytes  []byte `json:"answer_bytes"`
 	Answer       Answer `json:"answer"`
+	Skipped      bool   `json:"skipped"`
+	// stored from the survey URL when the session was created
+	Prefilled bool `json:"prefilled,omitempty"`
 }
 
 type SurveySession struct {
@@ -27,13 +37,86 @@ type SurveySession struct {
 	Status          SurveySessionStatus `json:"status"`
 	SurveyUUID      string              `json:"survey_uuid"`
 	IPAddr          string              `json:"ip_addr"`
+	Locale          string              `json:"locale,omitempty"`
+	Score           *int                `json:"score,omitempty"`
+	MaxScore        *int                `json:"max_score,omitempty"`
+	HiddenFields    map[string]string   `json:"hidden_fields,omitempty"`
+	QuotaID         string              `json:"quota_id,omitempty"`
+	InvitationToken string              `json:"-"`
 	QuestionAnswers []QuestionAnswer    `json:"question_answers"`
 	WebhookData     WebhookData         `json:"webhookData"`
+
+	// answer values keyed by question ID, matrix answers are flattened into "questionID.row" keys
+	Answers map[string]interface{} `json:"answers,omitempty"`
+	// computed variables keyed by variable ID
+	Variables map[string]interface{} `json:"variables,omitempty"`
+	// randomized order of questions and options
+	DisplayOrder *SessionOrder `json:"display_order,omitempty"`
+
+	// evaluated from the survey questions and answers
+	VisibleQuestions []string   `json:"visible_questions,omitempty"`
+	NextQuestionUUID string     `json:"next_question_uuid,omitempty"`
+	NextPageID       string     `json:"next_page_id,omitempty"`
+	Questions        []Question `json:"questions,omitempty"`
+	Outro            string     `json:"outro,omitempty"`
+}
+
+// AnswersByQuestionID returns decoded answers keyed by question ID, skipped questions have nil answers
+func (s *SurveySession) AnswersByQuestionID() map[string]Answer {
+	answers := make(map[string]Answer)
+	for _, a := range s.QuestionAnswers {
+		answers[a.QuestionID] = a.Answer
+	}
+
+	return answers
+}
+
+// SetAnswer adds or replaces the answer to a given question, nil answer means the question is skipped
+func (s *SurveySession) SetAnswer(q Question, answer Answer) {
+	for i, a := range s.QuestionAnswers {
+		if a.QuestionUUID == q.UUID {
+			s.QuestionAnswers[i].Answer = answer
+			s.QuestionAnswers[i].Skipped = answer == nil
+			return
+		}
+	}
+
+	s.QuestionAnswers = append(s.QuestionAnswers, QuestionAnswer{
+		QuestionID:   q.ID,
+		QuestionUUID: q.UUID,
+		Answer:       answer,
+		Skipped:      answer == nil,
+	})
+}
+
+// SetFlatAnswers fills answer values keyed by question ID for session listings and webhooks
+func (s *SurveySession) SetFlatAnswers(questions []Question) {
+	s.Answers = make(map[string]interface{})
+	for _, a := range s.QuestionAnswers {
+		for _, q := range questions {
+			if q.UUID != a.QuestionUUID {
+				continue
+			}
+
+			if matrixAnswer, ok := a.Answer.(*MatrixAnswer); ok {
+				for key, value := range matrixAnswer.Flatten(q) {
+					s.Answers[key] = value
+				}
+			} else {
+				s.Answers[q.ID] = answerValue(a.Answer)
+			}
+
+			break
+		}
+	}
 }
 
 type WebhookData struct {
 	StatusCode int16  `json:"statusCode"`
 	Response   string `json:"response"`
+	// status of the last delivery of the session
+	Status   WebhookDeliveryStatus `json:"status,omitempty"`
+	Attempts int                   `json:"attempts,omitempty"`
 }
 
 type SurveySessionsFilter struct {
@@ -41,6 +124,8 @@ type SurveySessionsFilter struct {
 	Offset int    `query:"offset"`
 	SortBy string `query:"sort_by"`
 	Order  string `query:"order"`
+	// sessions must have all given hidden field values
+	HiddenFields map[string]string `query:"-"`
 }
 
 var supportedSortBy = map[string]bool{
@@ -48,6 +133,7 @@ var supportedSortBy = map[string]bool{
 	"created_at":   true,
 	"completed_at": true,
 	"status":       true,
+	"score":        true,
 }
 
 var supportedOrder = map[string]bool{
@@ -79,6 +165,29 @@ func (v *SurveySessionsFilter) Validate() error {
 	return nil
 }
 
+// SetHiddenFields reads hidden field filters from query params like hidden.utm_source=email
+func (v *SurveySessionsFilter) SetHiddenFields(query url.Values) {
+	for key := range query {
+		if field, ok := strings.CutPrefix(key, HiddenFieldFilterPrefix); ok && field != "" {
+			if v.HiddenFields == nil {
+				v.HiddenFields = make(map[string]string)
+			}
+			v.HiddenFields[field] = query.Get(key)
+		}
+	}
+}
+
 func (v *SurveySessionsFilter) ToString() string {
-	return fmt.Sprintf("limit=%d_offset=%d_sort_by=%s_order=%s", v.Limit, v.Offset, v.SortBy, v.Order)
+	str := fmt.Sprintf("limit=%d_offset=%d_sort_by=%s_order=%s", v.Limit, v.Offset, v.SortBy, v.Order)
+
+	fields := make([]string, 0, len(v.HiddenFields))
+	for field := range v.HiddenFields {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	for _, field := range fields {
+		str += fmt.Sprintf("_%s%s=%s", HiddenFieldFilterPrefix, field, v.HiddenFields[field])
+	}
+
+	return str
 }
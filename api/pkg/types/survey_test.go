@@ -0,0 +1,35 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSurveyConfigPublic(t *testing.T) {
+	config := &SurveyConfig{
+		Title:     "Survey",
+		Webhook:   &WebhookConfig{URL: "https://example.com/hook", Secret: "${WEBHOOK_SECRET}"},
+		Webhooks:  []WebhookConfig{{URL: "https://example.com/hook", Headers: []WebhookHeader{{Name: "Authorization", Value: "${API_TOKEN}"}}}},
+		Quotas:    []Quota{{ID: "q1", Limit: 10}},
+		Variables: &Variables{},
+		Security:  &Security{},
+	}
+
+	public := config.Public()
+	b, err := json.Marshal(public)
+	if err != nil {
+		t.Fatalf("unable to marshal config: %v", err)
+	}
+	for _, s := range []string{"example.com", "API_TOKEN", "WEBHOOK_SECRET", "q1"} {
+		if strings.Contains(string(b), s) {
+			t.Errorf("expected %s to be removed, got %s", s, b)
+		}
+	}
+	if public.Title != "Survey" {
+		t.Errorf("expected title to be kept, got %s", public.Title)
+	}
+	if config.Webhook == nil || config.Security == nil {
+		t.Errorf("expected original config to be unchanged")
+	}
+}
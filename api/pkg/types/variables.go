@@ -9,10 +9,17 @@ type VariableType string
 
 const (
 	VariableType_List VariableType = "list"
+	// computed from answers by expression
+	VariableType_Number  VariableType = "number"
+	VariableType_Text    VariableType = "text"
+	VariableType_Boolean VariableType = "boolean"
 )
 
 var supportedVariableTypes = map[VariableType]bool{
-	VariableType_List: true,
+	VariableType_List:    true,
+	VariableType_Number:  true,
+	VariableType_Text:    true,
+	VariableType_Boolean: true,
 }
 
 type Variables struct {
@@ -20,9 +27,11 @@ type Variables struct {
 }
 
 type Variable struct {
-	ID      string       `json:"id" yaml:"id"`
-	Type    VariableType `json:"type" yaml:"type"`
-	Options []string     `json:"options,omitempty" yaml:"options,omitempty"`
+	ID           string       `json:"id" yaml:"id"`
+	Type         VariableType `json:"type" yaml:"type"`
+	Options      []string     `json:"options,omitempty" yaml:"-"`
+	OptionLabels []Option     `json:"optionLabels,omitempty" yaml:"-"`
+	Expression   string       `json:"expression,omitempty" yaml:"expression,omitempty"`
 }
 
 func (v *Variables) Validate() error {
@@ -35,6 +44,13 @@ func (v *Variables) Validate() error {
 			return fmt.Errorf("variables[].type is invalid: %s", variable.Type)
 		}
 
+		if variable.IsComputed() && variable.Expression == "" {
+			return fmt.Errorf("variables[].expression is required for type: %s", variable.Type)
+		}
+		if !variable.IsComputed() && variable.Expression != "" {
+			return fmt.Errorf("variables[].expression is not supported for type: %s", variable.Type)
+		}
+
 		if _, ok := uniqueIDs[variable.ID]; ok {
 			return fmt.Errorf("variables[].id is duplicated: %s", variable.ID)
 		}
@@ -43,3 +59,8 @@ func (v *Variables) Validate() error {
 
 	return nil
 }
+
+// IsComputed returns true for variables computed from answers
+func (v Variable) IsComputed() bool {
+	return v.Type != VariableType_List
+}
@@ -2,15 +2,36 @@ package types
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
+)
+
+const (
+	DefaultWebhookMaxAttempts = 8
+	webhookRetryBaseDelay     = 30 * time.Second
+	webhookRetryMaxDelay      = 6 * time.Hour
 )
 
 type WebhookConfig struct {
 	URL    string `json:"url" yaml:"url"`
 	Method string `json:"method" yaml:"method"`
+	// delivery is dead-lettered after this number of failed attempts, DefaultWebhookMaxAttempts if empty
+	MaxAttempts int `json:"maxAttempts,omitempty" yaml:"maxAttempts"`
+	// reference to the env var with the signing secret, e.g. ${WEBHOOK_SECRET}, payloads are not signed if empty
+	Secret string `json:"secret,omitempty" yaml:"secret"`
+	// events the endpoint is subscribed to, session.completed if empty
+	Events  []WebhookEventType `json:"events,omitempty" yaml:"events"`
+	Headers []WebhookHeader    `json:"headers,omitempty" yaml:"headers"`
+	// Go template of the body rendered with the event, the event as JSON if empty
+	Template string `json:"template,omitempty" yaml:"template"`
 }
 
+var webhookSecretRef = regexp.MustCompile(`^\$\{([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
 func (wc *WebhookConfig) Validate() error {
 	parsedUrl, err := url.ParseRequestURI(wc.URL)
 	if err != nil {
@@ -29,5 +50,173 @@ func (wc *WebhookConfig) Validate() error {
 		return errors.New("unsupported http method for webhook")
 	}
 
+	if wc.MaxAttempts < 0 {
+		return errors.New("webhook maxAttempts must be greater than or equal to 0")
+	}
+
+	// secrets must not be stored in plain YAML
+	if wc.Secret != "" && !webhookSecretRef.MatchString(wc.Secret) {
+		return errors.New("webhook secret must be an env var reference, e.g. ${WEBHOOK_SECRET}")
+	}
+
+	for _, event := range wc.Events {
+		if _, ok := supportedWebhookEventTypes[event]; !ok {
+			return fmt.Errorf("webhook event is invalid: %s", event)
+		}
+	}
+
+	if err := wc.validatePayload(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (wc *WebhookConfig) GetMaxAttempts() int {
+	if wc.MaxAttempts > 0 {
+		return wc.MaxAttempts
+	}
+
+	return DefaultWebhookMaxAttempts
+}
+
+// IsSubscribed reports whether the endpoint receives events of a given type
+func (wc *WebhookConfig) IsSubscribed(event WebhookEventType) bool {
+	if len(wc.Events) == 0 {
+		return event == WebhookEvent_SessionCompleted
+	}
+
+	return slices.Contains(wc.Events, event)
+}
+
+// SecretEnv returns the name of the env var with the signing secret, empty if payloads are not signed
+func (wc *WebhookConfig) SecretEnv() string {
+	matches := webhookSecretRef.FindStringSubmatch(wc.Secret)
+	if len(matches) != 2 {
+		return ""
+	}
+
+	return matches[1]
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatus_Pending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatus_Delivered WebhookDeliveryStatus = "delivered"
+	// delivery failed max attempts times and is not retried anymore
+	WebhookDeliveryStatus_Dead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is a webhook call queued in the outbox, the payload is taken when the delivery is created
+type WebhookDelivery struct {
+	ID            int64                 `json:"-"`
+	UUID          string                `json:"uuid"`
+	CreatedAt     time.Time             `json:"created_at"`
+	SurveyUUID    string                `json:"-"`
+	SessionID     int64                 `json:"-"`
+	SessionUUID   string                `json:"session_uuid,omitempty"`
+	URL           string                `json:"url"`
+	Method        string                `json:"method"`
+	Payload       []byte                `json:"-"`
+	SecretEnv     string                `json:"-"`
+	Headers       []WebhookHeader       `json:"-"`
+	EventType     WebhookEventType      `json:"event_type"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int                   `json:"attempts"`
+	MaxAttempts   int                   `json:"max_attempts"`
+	NextAttemptAt time.Time             `json:"next_attempt_at"`
+	DeliveredAt   *time.Time            `json:"delivered_at"`
+	// attempts log, oldest first
+	Responses []WebhookAttempt `json:"responses"`
+}
+
+var (
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookDeliveryPending  = errors.New("webhook delivery is pending")
+)
+
+// WebhookAttempt is a single call of the webhook
+type WebhookAttempt struct {
+	CreatedAt time.Time `json:"created_at"`
+	Attempt   int       `json:"attempt"`
+	// 0 if there was no response
+	ResponseStatus int    `json:"response_status"`
+	Response       string `json:"response"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Succeeded reports whether the receiver responded with 2xx status
+func (a WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.ResponseStatus >= 200 && a.ResponseStatus < 300
+}
+
+// WebhookRetryDelay returns the exponential backoff before the next attempt after a given number of attempts
+func WebhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxDelay {
+			return webhookRetryMaxDelay
+		}
+	}
+
+	return delay
+}
+
+// RecordAttempt updates the delivery status after the attempt
+func (d *WebhookDelivery) RecordAttempt(attempt WebhookAttempt) {
+	d.Attempts = attempt.Attempt
+
+	switch {
+	case attempt.Succeeded():
+		d.Status = WebhookDeliveryStatus_Delivered
+		deliveredAt := attempt.CreatedAt
+		d.DeliveredAt = &deliveredAt
+	case d.Attempts >= d.MaxAttempts:
+		d.Status = WebhookDeliveryStatus_Dead
+	default:
+		d.Status = WebhookDeliveryStatus_Pending
+		d.NextAttemptAt = attempt.CreatedAt.Add(WebhookRetryDelay(d.Attempts))
+	}
+}
+
+// Redeliver queues the delivery again with a new budget of attempts, previous attempts are kept
+func (d *WebhookDelivery) Redeliver(now time.Time, maxAttempts int) {
+	d.Status = WebhookDeliveryStatus_Pending
+	d.MaxAttempts = d.Attempts + maxAttempts
+	d.NextAttemptAt = now
+	d.DeliveredAt = nil
+}
+
+// WebhookDeliveriesFilter filters the deliveries log
+type WebhookDeliveriesFilter struct {
+	Limit       int                   `query:"limit"`
+	Offset      int                   `query:"offset"`
+	SessionUUID string                `query:"session_uuid"`
+	Status      WebhookDeliveryStatus `query:"status"`
+	// status code of the last attempt response
+	ResponseStatus int `query:"response_status"`
+}
+
+func (v *WebhookDeliveriesFilter) Validate() error {
+	if v.Limit <= 0 {
+		v.Limit = 100
+	}
+
+	if v.Offset < 0 {
+		v.Offset = 0
+	}
+
+	switch v.Status {
+	case "", WebhookDeliveryStatus_Pending, WebhookDeliveryStatus_Delivered, WebhookDeliveryStatus_Dead:
+	default:
+		return fmt.Errorf("status is invalid: %s", v.Status)
+	}
+
+	if v.ResponseStatus != 0 && (v.ResponseStatus < 100 || v.ResponseStatus > 599) {
+		return fmt.Errorf("response_status is invalid: %d", v.ResponseStatus)
+	}
+
 	return nil
 }
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"time"
+)
+
+type WebhookEventType string
+
+const (
+	WebhookEvent_SessionStarted    WebhookEventType = "session.started"
+	WebhookEvent_AnswerSubmitted   WebhookEventType = "answer.submitted"
+	WebhookEvent_SessionCompleted  WebhookEventType = "session.completed"
+	WebhookEvent_SessionDeleted    WebhookEventType = "session.deleted"
+	WebhookEvent_SurveySynced      WebhookEventType = "survey.synced"
+	WebhookEvent_SurveyParseFailed WebhookEventType = "survey.parse_failed"
+)
+
+var supportedWebhookEventTypes = map[WebhookEventType]bool{
+	WebhookEvent_SessionStarted:    true,
+	WebhookEvent_AnswerSubmitted:   true,
+	WebhookEvent_SessionCompleted:  true,
+	WebhookEvent_SessionDeleted:    true,
+	WebhookEvent_SurveySynced:      true,
+	WebhookEvent_SurveyParseFailed: true,
+}
+
+// version of the event envelope, it's changed on breaking changes of the envelope or event data
+const WebhookEventVersion = 1
+
+// WebhookEvent is the envelope of every event sent to webhooks
+type WebhookEvent struct {
+	ID        string             `json:"id"`
+	Type      WebhookEventType   `json:"type"`
+	Version   int                `json:"version"`
+	CreatedAt time.Time          `json:"created_at"`
+	Survey    WebhookEventSurvey `json:"survey"`
+	// session for session and answer events, survey for survey events
+	Data interface{} `json:"data"`
+}
+
+type WebhookEventSurvey struct {
+	UUID    string `json:"uuid"`
+	Name    string `json:"name"`
+	URLSlug string `json:"url_slug"`
+}
+
+// AnswerSubmittedData is the data of answer.submitted event
+type AnswerSubmittedData struct {
+	Session     WebhookSession `json:"session"`
+	QuestionIDs []string       `json:"question_ids"`
+}
+
+// SurveyEventData is the data of survey events, config is omitted as it may contain secrets references
+type SurveyEventData struct {
+	ParseStatus    SurveyParseStatus    `json:"parse_status"`
+	DeliveryStatus SurveyDeliveryStatus `json:"delivery_status"`
+	ErrorLog       string               `json:"error_log,omitempty"`
+	Hash           string               `json:"hash,omitempty"`
+}
+
+func NewWebhookEvent(id string, eventType WebhookEventType, survey *Survey, data interface{}, now time.Time) WebhookEvent {
+	return WebhookEvent{
+		ID:        id,
+		Type:      eventType,
+		Version:   WebhookEventVersion,
+		CreatedAt: now.UTC(),
+		Survey: WebhookEventSurvey{
+			UUID:    survey.UUID,
+			Name:    survey.Name,
+			URLSlug: survey.URLSlug,
+		},
+		Data: data,
+	}
+}
+
+// WebhookEndpoints returns endpoints subscribed to events of a given type
+func (s *SurveyConfig) WebhookEndpoints(event WebhookEventType) []WebhookConfig {
+	endpoints := []WebhookConfig{}
+	if s == nil {
+		return endpoints
+	}
+
+	if s.Webhook != nil && s.Webhook.IsSubscribed(event) {
+		endpoints = append(endpoints, *s.Webhook)
+	}
+	for _, wc := range s.Webhooks {
+		if wc.IsSubscribed(event) {
+			endpoints = append(endpoints, wc)
+		}
+	}
+
+	return endpoints
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestWebhookEndpoints(t *testing.T) {
+	s := &SurveyConfig{
+		Webhook: &WebhookConfig{URL: "https://example.com/legacy", Method: "POST"},
+		Webhooks: []WebhookConfig{
+			{URL: "https://example.com/sessions", Method: "POST", Events: []WebhookEventType{WebhookEvent_SessionStarted, WebhookEvent_SessionCompleted}},
+			{URL: "https://example.com/surveys", Method: "POST", Events: []WebhookEventType{WebhookEvent_SurveyParseFailed}},
+		},
+	}
+
+	tests := []struct {
+		event    WebhookEventType
+		expected []string
+	}{
+		{WebhookEvent_SessionCompleted, []string{"https://example.com/legacy", "https://example.com/sessions"}},
+		{WebhookEvent_SessionStarted, []string{"https://example.com/sessions"}},
+		{WebhookEvent_SurveyParseFailed, []string{"https://example.com/surveys"}},
+		{WebhookEvent_AnswerSubmitted, []string{}},
+	}
+
+	for _, tt := range tests {
+		endpoints := s.WebhookEndpoints(tt.event)
+		if len(endpoints) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.event, tt.expected, endpoints)
+			continue
+		}
+		for i := range endpoints {
+			if endpoints[i].URL != tt.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tt.event, tt.expected, endpoints)
+				break
+			}
+		}
+	}
+
+	var empty *SurveyConfig
+	if endpoints := empty.WebhookEndpoints(WebhookEvent_SurveySynced); len(endpoints) != 0 {
+		t.Errorf("expected no endpoints without config, got %v", endpoints)
+	}
+}
+
+func TestWebhookConfigValidateEvents(t *testing.T) {
+	wc := &WebhookConfig{URL: "https://example.com/webhook", Method: "POST", Events: []WebhookEventType{WebhookEvent_SurveySynced}}
+	if err := wc.Validate(); err != nil {
+		t.Errorf("expected valid events, got %v", err)
+	}
+
+	wc.Events = append(wc.Events, "survey.deleted")
+	if err := wc.Validate(); err == nil {
+		t.Errorf("expected error for unknown event")
+	}
+}
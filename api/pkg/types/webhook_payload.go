@@ -0,0 +1,206 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"text/template"
+	"time"
+)
+
+// WebhookHeader is a custom header of webhook requests, value must be an env var reference, e.g. ${API_TOKEN}
+type WebhookHeader struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+}
+
+var webhookHeaderName = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// WebhookSession is the session in webhook events, answers are keyed by question ID
+type WebhookSession struct {
+	UUID         string                   `json:"uuid"`
+	CreatedAt    time.Time                `json:"created_at"`
+	CompletedAt  *time.Time               `json:"completed_at"`
+	Status       SurveySessionStatus      `json:"status"`
+	Locale       string                   `json:"locale,omitempty"`
+	Score        *int                     `json:"score,omitempty"`
+	MaxScore     *int                     `json:"max_score,omitempty"`
+	HiddenFields map[string]string        `json:"hidden_fields,omitempty"`
+	QuotaID      string                   `json:"quota_id,omitempty"`
+	Variables    map[string]interface{}   `json:"variables,omitempty"`
+	Answers      map[string]WebhookAnswer `json:"answers"`
+}
+
+type WebhookAnswer struct {
+	Label   string      `json:"label"`
+	Value   interface{} `json:"value"`
+	Skipped bool        `json:"skipped,omitempty"`
+}
+
+// NewWebhookSession returns the session with answers keyed by question ID and labelled with question labels,
+// matrix answers are flattened into "questionID.row" keys
+func NewWebhookSession(s *SurveyConfig, session *SurveySession) WebhookSession {
+	ws := WebhookSession{
+		UUID:         session.UUID,
+		CreatedAt:    session.CreatedAt,
+		CompletedAt:  session.CompletedAt,
+		Status:       session.Status,
+		Locale:       session.Locale,
+		Score:        session.Score,
+		MaxScore:     session.MaxScore,
+		HiddenFields: session.HiddenFields,
+		QuotaID:      session.QuotaID,
+		Variables:    session.Variables,
+		Answers:      make(map[string]WebhookAnswer),
+	}
+	if s == nil || s.Questions == nil {
+		return ws
+	}
+
+	for _, a := range session.QuestionAnswers {
+		for _, q := range s.Questions.Questions {
+			if q.UUID != a.QuestionUUID {
+				continue
+			}
+
+			if matrixAnswer, ok := a.Answer.(*MatrixAnswer); ok {
+				for _, row := range q.Rows {
+					key := q.ID + "." + row
+					ws.Answers[key] = WebhookAnswer{Label: q.Label + " - " + row, Value: matrixAnswer.Flatten(q)[key]}
+				}
+			} else {
+				ws.Answers[q.ID] = WebhookAnswer{Label: q.Label, Value: answerValue(a.Answer), Skipped: a.Skipped}
+			}
+
+			break
+		}
+	}
+
+	return ws
+}
+
+func (wc *WebhookConfig) validatePayload() error {
+	for _, h := range wc.Headers {
+		if !webhookHeaderName.MatchString(h.Name) {
+			return fmt.Errorf("webhook header name is invalid: %s", h.Name)
+		}
+		// header values are tokens in most cases, they must not be stored in plain YAML
+		if !webhookSecretRef.MatchString(h.Value) {
+			return fmt.Errorf("webhook header value must be an env var reference, e.g. ${API_TOKEN}: %s", h.Name)
+		}
+	}
+
+	if wc.Template != "" {
+		if _, err := wc.parseTemplate(); err != nil {
+			return fmt.Errorf("webhook template is invalid: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (wc *WebhookConfig) parseTemplate() (*template.Template, error) {
+	return template.New("webhook").Option("missingkey=zero").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(wc.Template)
+}
+
+// jsonEscaped is a string printed by templates escaped for JSON strings, so answers can't break the body,
+// it's marshaled as the original string by the json func
+type jsonEscaped string
+
+func (s jsonEscaped) String() string {
+	b, _ := json.Marshal(string(s))
+	return string(b[1 : len(b)-1])
+}
+
+// templateData returns the event as it's sent in JSON with all strings escaped,
+// so templates use JSON field names, e.g. {{ .survey.name }}
+func templateData(event WebhookEvent) (interface{}, error) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	return escapeStrings(data), nil
+}
+
+func escapeStrings(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return jsonEscaped(v)
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = escapeStrings(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = escapeStrings(value)
+		}
+	}
+
+	return v
+}
+
+// RenderPayload returns the body of the event for this endpoint, the event as JSON if there is no template
+func (wc *WebhookConfig) RenderPayload(event WebhookEvent) ([]byte, error) {
+	if wc.Template == "" {
+		return json.Marshal(event)
+	}
+
+	tmpl, err := wc.parseTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := templateData(event)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	// requests are sent as application/json
+	if !json.Valid(buf.Bytes()) {
+		return nil, errors.New("webhook template must render valid JSON")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ResolveWebhookHeaders replaces env var references in header values with their values,
+// they are resolved on every attempt, so tokens are never stored
+func ResolveWebhookHeaders(headers []WebhookHeader) ([]WebhookHeader, error) {
+	resolved := []WebhookHeader{}
+	for _, h := range headers {
+		matches := webhookSecretRef.FindStringSubmatch(h.Value)
+		if len(matches) != 2 {
+			return nil, fmt.Errorf("webhook header value is not an env var reference: %s", h.Name)
+		}
+
+		value, ok := os.LookupEnv(matches[1])
+		if !ok {
+			return nil, fmt.Errorf("webhook header env var is not set: %s", matches[1])
+		}
+
+		resolved = append(resolved, WebhookHeader{Name: h.Name, Value: value})
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,122 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewWebhookSession(t *testing.T) {
+	s := &SurveyConfig{Questions: &Questions{Questions: []Question{
+		{ID: "q_name", UUID: "uuid-1", Label: "Your name", Type: QuestionType_ShortText},
+		{ID: "q_rate", UUID: "uuid-2", Label: "Rate", Type: QuestionType_Matrix, Rows: []string{"speed", "price"}, Columns: []string{"good", "bad"}},
+		{ID: "q_comment", UUID: "uuid-3", Label: "Comment", Type: QuestionType_LongText},
+	}}}
+	session := &SurveySession{
+		UUID: "session-1",
+		QuestionAnswers: []QuestionAnswer{
+			{QuestionUUID: "uuid-1", Answer: &TextAnswer{AnswerValue: "John"}},
+			{QuestionUUID: "uuid-2", Answer: &MatrixAnswer{AnswerValue: map[string][]string{"speed": {"good"}}}},
+			{QuestionUUID: "uuid-3", Skipped: true},
+		},
+	}
+
+	ws := NewWebhookSession(s, session)
+
+	tests := []struct {
+		key     string
+		label   string
+		value   interface{}
+		skipped bool
+	}{
+		{"q_name", "Your name", "John", false},
+		{"q_rate.speed", "Rate - speed", "good", false},
+		{"q_rate.price", "Rate - price", nil, false},
+		{"q_comment", "Comment", nil, true},
+	}
+
+	for _, tt := range tests {
+		a, ok := ws.Answers[tt.key]
+		if !ok {
+			t.Errorf("%s: expected answer", tt.key)
+			continue
+		}
+		if a.Label != tt.label || a.Value != tt.value || a.Skipped != tt.skipped {
+			t.Errorf("%s: expected %s=%v skipped %v, got %+v", tt.key, tt.label, tt.value, tt.skipped, a)
+		}
+	}
+}
+
+func TestWebhookConfigRenderPayload(t *testing.T) {
+	event := NewWebhookEvent("event-1", WebhookEvent_SessionCompleted, &Survey{UUID: "survey-1", Name: "survey1"}, WebhookSession{
+		UUID: "session-1",
+		Answers: map[string]WebhookAnswer{
+			"q_name":  {Label: "Your name", Value: `John "J" \ }`},
+			"q_admin": {Label: "Comment", Value: `", "admin": true, "x": "`},
+		},
+	}, time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+		wantErr  bool
+	}{
+		{"template", `{"text": "{{ .survey.name }}: {{ .data.answers.q_name.value }}"}`, `{"text": "survey1: John \"J\" \\ }"}`, false},
+		{"injected fields", `{"comment": "{{ .data.answers.q_admin.value }}"}`, `{"comment": "\", \"admin\": true, \"x\": \""}`, false},
+		{"json func", `{"name": {{ json .data.answers.q_name.value }}}`, `{"name": "John \"J\" \\ }"}`, false},
+		{"number", `{"version": {{ .version }}}`, `{"version": 1}`, false},
+		{"condition", `{"completed": {{ eq .type "session.completed" }}}`, `{"completed": true}`, false},
+		{"unknown field", `{{ .unknown }}`, "", true},
+		{"invalid json", `{{ .type }} completed`, "", true},
+	}
+
+	for _, tt := range tests {
+		wc := &WebhookConfig{Template: tt.template}
+		payload, err := wc.RenderPayload(event)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+			continue
+		}
+		if !tt.wantErr && string(payload) != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, payload)
+		}
+	}
+
+	payload, err := (&WebhookConfig{}).RenderPayload(event)
+	if err != nil || len(payload) == 0 || payload[0] != '{' {
+		t.Errorf("expected event as json, got %s, %v", payload, err)
+	}
+}
+
+func TestWebhookConfigValidatePayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		wc      WebhookConfig
+		wantErr bool
+	}{
+		{"valid", WebhookConfig{Headers: []WebhookHeader{{Name: "Authorization", Value: "${API_TOKEN}"}}, Template: "{{ .Type }}"}, false},
+		{"invalid header", WebhookConfig{Headers: []WebhookHeader{{Name: "Bad Header", Value: "${API_TOKEN}"}}}, true},
+		{"plain header value", WebhookConfig{Headers: []WebhookHeader{{Name: "Authorization", Value: "Bearer token"}}}, true},
+		{"embedded env var", WebhookConfig{Headers: []WebhookHeader{{Name: "Authorization", Value: "Bearer ${API_TOKEN}"}}}, true},
+		{"invalid template", WebhookConfig{Template: "{{ .Type "}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.wc.validatePayload(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestResolveWebhookHeaders(t *testing.T) {
+	t.Setenv("WEBHOOK_TEST_TOKEN", "Bearer secret")
+
+	headers, err := ResolveWebhookHeaders([]WebhookHeader{{Name: "Authorization", Value: "${WEBHOOK_TEST_TOKEN}"}})
+	if err != nil || headers[0].Value != "Bearer secret" {
+		t.Errorf("expected resolved header, got %v, %v", headers, err)
+	}
+
+	if _, err := ResolveWebhookHeaders([]WebhookHeader{{Name: "Authorization", Value: "${WEBHOOK_TEST_MISSING}"}}); err == nil {
+		t.Errorf("expected error for missing env var")
+	}
+}
@@ -0,0 +1,128 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{20, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if delay := WebhookRetryDelay(tt.attempts); delay != tt.expected {
+			t.Errorf("%d attempts: expected %v, got %v", tt.attempts, tt.expected, delay)
+		}
+	}
+}
+
+func TestWebhookDeliveryRecordAttempt(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		attempt           WebhookAttempt
+		expected          WebhookDeliveryStatus
+		expectedNextAt    time.Time
+		expectedDelivered bool
+	}{
+		{"delivered", WebhookAttempt{CreatedAt: now, Attempt: 1, ResponseStatus: 204}, WebhookDeliveryStatus_Delivered, time.Time{}, true},
+		{"server error", WebhookAttempt{CreatedAt: now, Attempt: 1, ResponseStatus: 500}, WebhookDeliveryStatus_Pending, now.Add(30 * time.Second), false},
+		{"network error", WebhookAttempt{CreatedAt: now, Attempt: 2, Error: "timeout"}, WebhookDeliveryStatus_Pending, now.Add(time.Minute), false},
+		{"dead", WebhookAttempt{CreatedAt: now, Attempt: 3, ResponseStatus: 500}, WebhookDeliveryStatus_Dead, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		d := &WebhookDelivery{MaxAttempts: 3}
+		d.RecordAttempt(tt.attempt)
+
+		if d.Status != tt.expected {
+			t.Errorf("%s: expected status %s, got %s", tt.name, tt.expected, d.Status)
+		}
+		if !d.NextAttemptAt.Equal(tt.expectedNextAt) {
+			t.Errorf("%s: expected next attempt at %v, got %v", tt.name, tt.expectedNextAt, d.NextAttemptAt)
+		}
+		if (d.DeliveredAt != nil) != tt.expectedDelivered {
+			t.Errorf("%s: expected delivered %v, got %v", tt.name, tt.expectedDelivered, d.DeliveredAt)
+		}
+	}
+}
+
+func TestWebhookDeliveryRedeliver(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	d := &WebhookDelivery{Status: WebhookDeliveryStatus_Dead, Attempts: 3, MaxAttempts: 3}
+	d.Redeliver(now, 3)
+	if d.Status != WebhookDeliveryStatus_Pending || d.MaxAttempts != 6 || !d.NextAttemptAt.Equal(now) {
+		t.Errorf("expected pending delivery with 6 max attempts at %v, got %+v", now, d)
+	}
+
+	// the new budget is used before the delivery is dead again
+	for attempt := 4; attempt <= 6; attempt++ {
+		d.RecordAttempt(WebhookAttempt{CreatedAt: now, Attempt: attempt, ResponseStatus: 500})
+	}
+	if d.Status != WebhookDeliveryStatus_Dead {
+		t.Errorf("expected dead delivery, got %s", d.Status)
+	}
+
+	d = &WebhookDelivery{Status: WebhookDeliveryStatus_Delivered, Attempts: 1, MaxAttempts: 8, DeliveredAt: &now}
+	d.Redeliver(now, 8)
+	if d.DeliveredAt != nil || d.MaxAttempts != 9 {
+		t.Errorf("expected delivered at to be reset and 9 max attempts, got %+v", d)
+	}
+}
+
+func TestWebhookDeliveriesFilterValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  WebhookDeliveriesFilter
+		wantErr bool
+	}{
+		{"empty", WebhookDeliveriesFilter{}, false},
+		{"status", WebhookDeliveriesFilter{Status: WebhookDeliveryStatus_Dead}, false},
+		{"invalid status", WebhookDeliveriesFilter{Status: "failed"}, true},
+		{"response status", WebhookDeliveriesFilter{ResponseStatus: 500}, false},
+		{"invalid response status", WebhookDeliveriesFilter{ResponseStatus: 42}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.filter.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+		if err == nil && tt.filter.Limit != 100 {
+			t.Errorf("%s: expected default limit 100, got %d", tt.name, tt.filter.Limit)
+		}
+	}
+}
+
+func TestWebhookConfigSecret(t *testing.T) {
+	tests := []struct {
+		secret   string
+		expected string
+		wantErr  bool
+	}{
+		{"", "", false},
+		{"${WEBHOOK_SECRET}", "WEBHOOK_SECRET", false},
+		{"plain-secret", "", true},
+		{"$WEBHOOK_SECRET", "", true},
+		{"${1SECRET}", "", true},
+	}
+
+	for _, tt := range tests {
+		wc := &WebhookConfig{URL: "https://example.com/webhook", Method: "POST", Secret: tt.secret}
+		if err := wc.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.secret, tt.wantErr, err)
+		}
+		if env := wc.SecretEnv(); env != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.secret, tt.expected, env)
+		}
+	}
+}
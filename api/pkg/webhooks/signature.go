@@ -0,0 +1,95 @@
+// Package webhooks signs webhook payloads and verifies them on the receiver side.
+//
+// Receivers can verify requests with:
+//
+//	body, err := webhooks.VerifyRequest(r, os.Getenv("WEBHOOK_SECRET"), webhooks.DefaultTolerance)
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Formulosity-Signature"
+	TimestampHeader = "X-Formulosity-Timestamp"
+	// version prefix of the signature, so the scheme can be changed without breaking receivers
+	signaturePrefix = "v1="
+	// requests signed earlier are rejected to prevent replay
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrMissingSignature = errors.New("webhook signature is missing")
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	ErrInvalidTimestamp = errors.New("webhook timestamp is invalid")
+	ErrExpiredTimestamp = errors.New("webhook timestamp is outside of tolerance")
+)
+
+// Sign returns the signature header value of the body sent at a given time
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return signaturePrefix + hex.EncodeToString(sign(secret, timestamp.Unix(), body))
+}
+
+// SetHeaders signs the body and sets signature and timestamp headers of the request
+func SetHeaders(header http.Header, secret string, timestamp time.Time, body []byte) {
+	header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	header.Set(SignatureHeader, Sign(secret, timestamp, body))
+}
+
+// Verify checks the signature of the body and that it was signed within tolerance from now
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	signatureHex, ok := strings.CutPrefix(header.Get(SignatureHeader), signaturePrefix)
+	if !ok || header.Get(TimestampHeader) == "" {
+		return ErrMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	signature, err := hex.DecodeString(signatureHex)
+	if err != nil || !hmac.Equal(signature, sign(secret, timestamp, body)) {
+		return ErrInvalidSignature
+	}
+
+	if diff := now.Sub(time.Unix(timestamp, 0)); diff > tolerance || diff < -tolerance {
+		return ErrExpiredTimestamp
+	}
+
+	return nil
+}
+
+// VerifyRequest reads and verifies the body of the webhook request, the body can be read again afterwards
+func VerifyRequest(r *http.Request, secret string, tolerance time.Duration) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := Verify(secret, r.Header, body, tolerance, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// timestamp is signed together with the body, so it can't be changed to replay the request
+func sign(secret string, timestamp int64, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return mac.Sum(nil)
+}
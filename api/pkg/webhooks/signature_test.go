@@ -0,0 +1,45 @@
+package webhooks
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	body := []byte(`{"uuid":"session-1"}`)
+
+	signed := http.Header{}
+	SetHeaders(signed, "secret", now, body)
+
+	tests := []struct {
+		name     string
+		secret   string
+		header   http.Header
+		body     []byte
+		now      time.Time
+		expected error
+	}{
+		{"valid", "secret", signed, body, now.Add(time.Minute), nil},
+		{"another secret", "other", signed, body, now, ErrInvalidSignature},
+		{"tampered body", "secret", signed, []byte(`{"uuid":"session-2"}`), now, ErrInvalidSignature},
+		{"replayed", "secret", signed, body, now.Add(DefaultTolerance + time.Second), ErrExpiredTimestamp},
+		{"missing signature", "secret", http.Header{}, body, now, ErrMissingSignature},
+		{"tampered timestamp", "secret", http.Header{
+			SignatureHeader: []string{signed.Get(SignatureHeader)},
+			TimestampHeader: []string{strconv.FormatInt(now.Add(time.Hour).Unix(), 10)},
+		}, body, now.Add(time.Hour), ErrInvalidSignature},
+		{"invalid timestamp", "secret", http.Header{
+			SignatureHeader: []string{signed.Get(SignatureHeader)},
+			TimestampHeader: []string{"yesterday"},
+		}, body, now, ErrInvalidTimestamp},
+	}
+
+	for _, tt := range tests {
+		if err := Verify(tt.secret, tt.header, tt.body, DefaultTolerance, tt.now); err != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+		}
+	}
+}
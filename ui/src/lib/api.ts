@@ -1,4 +1,7 @@
 const API_BASE_URL = import.meta.env.VITE_API_ADDR || 'http://localhost:9900'
+// cookies are sent to the api on another origin only if it allows this origin with CORS_ALLOWED_ORIGINS
+const API_CREDENTIALS: RequestCredentials =
+  import.meta.env.VITE_API_CREDENTIALS === 'include' ? 'include' : 'same-origin'
 
 export async function call(path: string, init?: RequestInit) {
   try {
@@ -101,8 +104,9 @@ export async function get(path: string) {
   })
 }
 
-export async function getSurvey(urlSlug: string) {
-  return await call(`/surveys/${urlSlug}`, {
+export async function getSurvey(urlSlug: string, lang?: string) {
+  const query = lang ? `?lang=${encodeURIComponent(lang)}` : ''
+  return await call(`/surveys/${urlSlug}${query}`, {
     method: 'GET',
   })
 }
@@ -111,15 +115,28 @@ export async function getSurveys() {
   return await get(`/app/surveys`)
 }
 
-export async function createSurveySession(urlSlug: string) {
+export async function createSurveySession(
+  urlSlug: string,
+  lang?: string,
+  hiddenFields?: Record<string, string>,
+  prefill?: URLSearchParams,
+  token?: string
+) {
   const headers = {
     'Content-Type': 'application/json',
   }
 
-  return await call(`/surveys/${urlSlug}/sessions`, {
+  const params = new URLSearchParams(prefill)
+  if (lang) {
+    params.set('lang', lang)
+  }
+  const query = params.size ? `?${params.toString()}` : ''
+  return await call(`/surveys/${urlSlug}/sessions${query}`, {
     method: 'PUT',
-    body: JSON.stringify({}),
+    body: JSON.stringify({ hidden_fields: hiddenFields, token: token }),
     headers: headers,
+    // respondent cookie is issued by the api for cookie duplicate protection
+    credentials: API_CREDENTIALS,
   })
 }
 
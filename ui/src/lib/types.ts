@@ -18,6 +18,8 @@ export type Survey = {
   error_log: string
   url: string
   url_slug: string
+  locale?: string
+  locales?: string[]
   config: SurveyConfig
   stats: SurveyStats
   sessions: Array<SurveySession>
@@ -39,6 +41,8 @@ export type SurveyConfig = {
   intro: string
   outro: string
   theme: string
+  opensAt?: string
+  closesAt?: string
   questions: SurveyQuestions
 }
 
@@ -53,12 +57,18 @@ export type SurveyQuestion = {
   label: string
   description: string
   options: string[]
+  optionLabels?: SurveyQuestionOption[]
   min?: number
   max?: number
   index: number
   answer: SurveyQuestionAnswerData
 }
 
+export type SurveyQuestionOption = {
+  value: string
+  label: string
+}
+
 export enum SurveyQuestionType {
   SingleChoice = 'single-choice',
   MultipleChoice = 'multiple-choice',
@@ -75,6 +85,7 @@ export enum SurveyQuestionType {
 export enum SurveySessionStatus {
   Completed = 'completed',
   InProgress = 'in_progress',
+  ScreenedOut = 'screened_out',
 }
 
 export type SurveySession = {
@@ -84,6 +95,11 @@ export type SurveySession = {
   completed_at: string
   question_answers: SurveyQuestionAnswer[]
   webhookData: WebhookData
+  score?: number
+  max_score?: number
+  outro?: string
+  hidden_fields?: Record<string, string>
+  quota_id?: string
 }
 
 export type WebhookData = {